@@ -15,8 +15,8 @@ func (InfoLogger) KeyValue(msg string, keysAndValues ...interface{}) {
 
 // StructuredFields logs an info message with structured fields
 func (InfoLogger) StructuredFields(msg string, fields ...ZField) {
-	if defaultLogger != nil {
-		defaultLogger.logStructuredFields(INFO, msg, fields...)
+	if logger := Default(); logger != nil {
+		logger.logStructuredFields(INFO, msg, fields...)
 	}
 }
 
@@ -27,8 +27,8 @@ func (InfoLogger) Pool(msg string, fn func(*PooledFields)) {
 
 // Msg logs a simple info message
 func (InfoLogger) Msg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(INFO, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(INFO, message, nil)
 	}
 }
 
@@ -47,8 +47,8 @@ func (ErrorLogger) KeyValue(msg string, keysAndValues ...interface{}) {
 
 // StructuredFields logs an error message with ultra-fast structured fields (Phase 5C)
 func (ErrorLogger) StructuredFields(msg string, fields ...ZField) {
-	if defaultLogger != nil {
-		defaultLogger.logStructuredFields(ERROR, msg, fields...)
+	if logger := Default(); logger != nil {
+		logger.logStructuredFields(ERROR, msg, fields...)
 	}
 }
 
@@ -59,8 +59,8 @@ func (ErrorLogger) Pool(msg string, fn func(*PooledFields)) {
 
 // Msg logs a simple error message
 func (ErrorLogger) Msg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(ERROR, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(ERROR, message, nil)
 	}
 }
 
@@ -79,8 +79,8 @@ func (WarnLogger) KeyValue(msg string, keysAndValues ...interface{}) {
 
 // StructuredFields logs a warn message with ultra-fast structured fields
 func (WarnLogger) StructuredFields(msg string, fields ...ZField) {
-	if defaultLogger != nil {
-		defaultLogger.logStructuredFields(WARN, msg, fields...)
+	if logger := Default(); logger != nil {
+		logger.logStructuredFields(WARN, msg, fields...)
 	}
 }
 
@@ -91,8 +91,8 @@ func (WarnLogger) Pool(msg string, fn func(*PooledFields)) {
 
 // Msg logs a simple warn message
 func (WarnLogger) Msg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(WARN, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(WARN, message, nil)
 	}
 }
 
@@ -111,8 +111,8 @@ func (DebugLogger) KeyValue(msg string, keysAndValues ...interface{}) {
 
 // StructuredFields logs a debug message with ultra-fast structured fields (Phase 5C)
 func (DebugLogger) StructuredFields(msg string, fields ...ZField) {
-	if defaultLogger != nil {
-		defaultLogger.logStructuredFields(DEBUG, msg, fields...)
+	if logger := Default(); logger != nil {
+		logger.logStructuredFields(DEBUG, msg, fields...)
 	}
 }
 
@@ -123,8 +123,8 @@ func (DebugLogger) Pool(msg string, fn func(*PooledFields)) {
 
 // Msg logs a simple debug message
 func (DebugLogger) Msg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(DEBUG, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(DEBUG, message, nil)
 	}
 }
 
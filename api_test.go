@@ -1,8 +1,23 @@
 package emit
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net"
+	"os"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -25,9 +40,9 @@ func TestCleanAPI(t *testing.T) {
 	}
 
 	// Replace default logger temporarily
-	originalLogger := defaultLogger
-	defaultLogger = testLogger
-	defer func() { defaultLogger = originalLogger }()
+	originalLogger := Default()
+	SetDefault(testLogger)
+	defer SetDefault(originalLogger)
 
 	// Test Info.Field()
 	Info.Field("Test structured logging",
@@ -112,9 +127,9 @@ func TestLogLevels(t *testing.T) {
 		piiFields:       defaultPIIFields,
 	}
 
-	originalLogger := defaultLogger
-	defaultLogger = testLogger
-	defer func() { defaultLogger = originalLogger }()
+	originalLogger := Default()
+	SetDefault(testLogger)
+	defer SetDefault(originalLogger)
 
 	Debug.Msg("Debug message")  // Should be filtered out
 	Info.Msg("Info message")    // Should be filtered out
@@ -203,3 +218,2549 @@ func TestParseKeyValuePairs(t *testing.T) {
 		t.Errorf("Expected nil result for empty args, got %v", result)
 	}
 }
+
+// TestContextLogging tests that context fields are merged beneath call-site fields
+func TestContextLogging(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger := &Logger{
+		level:           DEBUG,
+		writer:          &buf,
+		format:          JSON_FORMAT,
+		sensitiveMode:   MASK_SENSITIVE,
+		piiMode:         MASK_PII,
+		sensitiveFields: defaultSensitiveFields,
+		piiFields:       defaultPIIFields,
+		maskString:      "***MASKED***",
+		piiMaskString:   "***PII***",
+	}
+
+	ctx := WithContextFields(context.Background(), map[string]any{
+		"request_id": "req-1",
+		"route":      "/from-context",
+		"token":      "secret-value",
+	})
+
+	testLogger.InfoContext(ctx, "Handled request", Fields{"route": "/from-call-site"})
+
+	output := buf.String()
+	if !strings.Contains(output, `"request_id":"req-1"`) {
+		t.Errorf("Expected context field in output: %s", output)
+	}
+	if !strings.Contains(output, `"route":"/from-call-site"`) {
+		t.Errorf("Expected call-site field to override context field: %s", output)
+	}
+	if strings.Contains(output, "secret-value") {
+		t.Errorf("Expected context fields to be masked: %s", output)
+	}
+}
+
+// TestTraceFields tests automatic trace and span ID injection
+func TestTraceFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	type spanKey struct{}
+	testLogger, err := New(WithOTelTrace(func(ctx context.Context) (string, string) {
+		if span, ok := ctx.Value(spanKey{}).([2]string); ok {
+			return span[0], span[1]
+		}
+		return "", ""
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.writer = &buf
+
+	ctx := context.WithValue(context.Background(), spanKey{}, [2]string{"4bf92f3577b34da6", "00f067aa0ba902b7"})
+	testLogger.InfoContext(ctx, "Traced", "step", 1)
+	testLogger.InfoContext(context.Background(), "Untraced", "step", 2)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `"trace_id":"4bf92f3577b34da6"`) || !strings.Contains(lines[0], `"span_id":"00f067aa0ba902b7"`) {
+		t.Errorf("Expected trace fields in traced entry: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "trace_id") {
+		t.Errorf("Expected no trace fields without an active span: %s", lines[1])
+	}
+}
+
+// TestWithFields tests child loggers carrying persistent fields
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	parent, err := New()
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	parent.writer = &buf
+
+	child := parent.
+		WithFields(map[string]any{"service": "billing", "region": "eu"}).
+		WithFields(map[string]any{"region": "us", "api_key": "sk_live_123"})
+
+	child.Info("Invoice created", "invoice_id", "inv-1")
+	child.Info("Overridden", Fields{"service": "payments"})
+	parent.Info("Parent entry")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 log lines, got %d: %s", len(lines), buf.String())
+	}
+	for _, expected := range []string{`"service":"billing"`, `"region":"us"`, `"invoice_id":"inv-1"`, `"api_key":"***MASKED***"`} {
+		if !strings.Contains(lines[0], expected) {
+			t.Errorf("Expected %s in child entry: %s", expected, lines[0])
+		}
+	}
+	if !strings.Contains(lines[1], `"service":"payments"`) {
+		t.Errorf("Expected call-site fields to override base fields: %s", lines[1])
+	}
+	if strings.Contains(lines[2], "billing") {
+		t.Errorf("Expected parent logger to be unaffected by child fields: %s", lines[2])
+	}
+}
+
+// stackError mimics errors that expose their stack via StackTrace()
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e stackError) Error() string         { return e.msg }
+func (e stackError) StackTrace() []uintptr { return e.pcs }
+
+// TestStackTraceLogging tests stack trace extraction and capture
+func TestStackTraceLogging(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithStackTrace(ERROR), WithStackTraceDepth(2))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.writer = &buf
+
+	pcs := make([]uintptr, 8)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+
+	testLogger.Warn("Wrapped failure", Err(fmt.Errorf("wrapped: %w", stackError{msg: "boom", pcs: pcs})))
+	testLogger.Error("Plain failure", Err(errors.New("plain")))
+	testLogger.Warn("Below threshold", Err(errors.New("plain")))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	for i, line := range lines[:2] {
+		var entry struct {
+			Fields struct {
+				Error      string       `json:"error"`
+				Stacktrace []StackFrame `json:"stacktrace"`
+			} `json:"fields"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse line %d: %v", i, err)
+		}
+		if len(entry.Fields.Stacktrace) == 0 || len(entry.Fields.Stacktrace) > 2 {
+			t.Errorf("Expected 1-2 stack frames in line %d, got %d", i, len(entry.Fields.Stacktrace))
+			continue
+		}
+		// The error carries its own trace; a captured one starts outside the package
+		want := "TestStackTraceLogging"
+		if i == 1 {
+			want = "testing.tRunner"
+		}
+		if !strings.HasSuffix(entry.Fields.Stacktrace[0].Function, want) {
+			t.Errorf("Expected first frame in %s, got %+v", want, entry.Fields.Stacktrace[0])
+		}
+	}
+
+	if strings.Contains(lines[2], "stacktrace") {
+		t.Errorf("Expected no stack trace below the configured level: %s", lines[2])
+	}
+}
+
+// TestStreamingEncoder tests that the streaming encoder produces valid, masked JSON
+func TestStreamingEncoder(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithStreamingEncoder(), WithComponent("api"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("Streaming \"entry\"", Fields{
+		"user":     map[string]any{"email": "a@example.com", "plan": "pro"},
+		"password": "hunter2",
+		"items":    []any{1, 2.5, true, nil, "x\ny"},
+		"ratio":    math.Inf(1),
+	})
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Streaming output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	fields := entry["fields"].(map[string]any)
+	if fields["password"] != "***MASKED***" {
+		t.Errorf("Expected password to be masked, got %v", fields["password"])
+	}
+	if user := fields["user"].(map[string]any); user["email"] != "***PII***" || user["plan"] != "pro" {
+		t.Errorf("Expected nested email to be masked, got %v", user)
+	}
+	if fields["ratio"] != nil {
+		t.Errorf("Expected infinite float to be encoded as null, got %v", fields["ratio"])
+	}
+	if entry["message"] != `Streaming "entry"` || entry["component"] != "api" {
+		t.Errorf("Unexpected entry metadata: %v", entry)
+	}
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithFormat(FormatLogfmt))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("User signed in", Fields{
+		"user":     map[string]any{"email": "a@example.com", "plan": "pro"},
+		"password": "hunter2",
+		"query":    "a=b c",
+		"attempts": 3,
+	})
+
+	line := buf.String()
+	for _, want := range []string{
+		` level=info msg="User signed in" `,
+		` attempts=3 `,
+		` password=***MASKED*** `,
+		` query="a=b c" `,
+		` user.email=***PII*** user.plan=pro`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected logfmt output to contain %q, got %s", want, line)
+		}
+	}
+
+	buf.Reset()
+	testLogger.Warn("disk full")
+	if !strings.Contains(buf.String(), ` level=warn msg="disk full"`) {
+		t.Errorf("Expected simple logfmt message, got %s", buf.String())
+	}
+
+	if _, err := New(WithFormat(OutputFormat(99))); err == nil {
+		t.Errorf("Expected error for unknown output format")
+	}
+}
+
+func TestConsoleFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithFormat(FormatConsole), WithComponent("api"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Warn("Login failed", Fields{"password": "hunter2", "attempts": 3})
+
+	line := buf.String()
+	if strings.Contains(line, "\033[") {
+		t.Errorf("Expected no colors for non-terminal writer, got %q", line)
+	}
+	if !strings.Contains(line, " WARN  [api] Login failed attempts=3 password=***MASKED***\n") {
+		t.Errorf("Unexpected console output: %q", line)
+	}
+
+	buf.Reset()
+	if err := testLogger.apply(WithColor(true)); err != nil {
+		t.Fatalf("Unexpected error applying option: %v", err)
+	}
+	testLogger.Error("Boom")
+	if !strings.Contains(buf.String(), "\033[31mERROR\033[0m") {
+		t.Errorf("Expected colored level with WithColor(true), got %q", buf.String())
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithSampling(2, 3))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		testLogger.Error("db timeout", "attempt", i)
+	}
+	testLogger.Error("other message")
+
+	// first 2, then every 3rd of the remaining 8 (entries 5 and 8), plus the other message
+	if lines := strings.Count(buf.String(), "\n"); lines != 5 {
+		t.Errorf("Expected 5 sampled lines, got %d:\n%s", lines, buf.String())
+	}
+
+	buf.Reset()
+	byField, err := New(WithOutput(&buf), WithSampling(1, 0, SampleByField("code"), SampleEvery(time.Hour)))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		byField.Warn("request failed", "code", 500)
+		byField.Warn("request failed", "code", 404)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("Expected one line per field value, got %d:\n%s", lines, buf.String())
+	}
+
+	if _, err := New(WithSampling(-1, 1)); err == nil {
+		t.Errorf("Expected error for negative sampling count")
+	}
+}
+
+type traceContextKey struct{}
+
+func TestTraceSampling(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithTraceSampling(0.25), WithOTelTrace(func(ctx context.Context) (string, string) {
+		traceID, _ := ctx.Value(traceContextKey{}).(string)
+		return traceID, ""
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	kept := 0
+	for i := range 1000 {
+		ctx := context.WithValue(context.Background(), traceContextKey{}, fmt.Sprintf("%032x", i))
+		before := len(sink.Entries())
+		testLogger.InfoContext(ctx, "first")
+		testLogger.InfoContext(ctx, "second")
+		switch len(sink.Entries()) - before {
+		case 2:
+			kept++
+		case 1:
+			t.Fatalf("Expected both entries of trace %d to share one decision", i)
+		}
+	}
+	if kept < 200 || kept > 300 {
+		t.Errorf("Expected about 250 of 1000 traces kept, got %d", kept)
+	}
+
+	sink.Reset()
+	testLogger.Info("no trace")
+	if len(sink.Entries()) != 1 {
+		t.Error("Expected entries without a trace ID to be written")
+	}
+
+	for _, rate := range []float64{-0.1, 1.5, math.NaN()} {
+		if _, err := New(WithTraceSampling(rate)); err == nil {
+			t.Errorf("Expected an error for rate %v", rate)
+		}
+	}
+}
+
+func TestDedup(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithDedup(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		testLogger.Error("db timeout", "password", fmt.Sprintf("secret-%d", i))
+	}
+	testLogger.Info("recovered")
+	testLogger.Info("recovered")
+	if err := testLogger.Close(); err != nil {
+		t.Fatalf("Unexpected error closing logger: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines (entry, summary, entry, summary), got %d:\n%s", len(lines), buf.String())
+	}
+
+	var summary LogEntry
+	if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v", err)
+	}
+	if summary.Message != "db timeout" || summary.Fields["suppressed"] != float64(4) {
+		t.Errorf("Unexpected summary entry: %s", lines[1])
+	}
+	if !strings.Contains(lines[3], `"suppressed":1`) {
+		t.Errorf("Expected final summary on Close, got %s", lines[3])
+	}
+}
+
+func TestDedupWindowExpiry(t *testing.T) {
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	writer := writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return buf.Write(p)
+	})
+
+	testLogger, err := New(WithOutput(writer), WithDedup(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	defer testLogger.Close()
+
+	testLogger.Warn("retrying")
+	testLogger.Warn("retrying")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		flushed := strings.Contains(buf.String(), `"suppressed":1`)
+		mu.Unlock()
+		if flushed {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("Expected summary after window expiry")
+}
+
+// writerFunc adapts a function to io.Writer
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// closeRecorder records Sync and Close calls
+type closeRecorder struct {
+	bytes.Buffer
+	syncs, closes int
+}
+
+func (w *closeRecorder) Sync() error  { w.syncs++; return nil }
+func (w *closeRecorder) Close() error { w.closes++; return nil }
+
+func TestCloseAndSync(t *testing.T) {
+	writer := &closeRecorder{}
+
+	testLogger, err := New(WithOutput(writer), WithDedup(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	child := testLogger.WithFields(map[string]any{"request_id": "r1"})
+
+	testLogger.Info("started")
+	testLogger.Info("started")
+	if err := testLogger.Sync(); err != nil {
+		t.Fatalf("Unexpected error from Sync: %v", err)
+	}
+	if writer.syncs != 1 || !strings.Contains(writer.String(), `"suppressed":1`) {
+		t.Errorf("Expected Sync to flush the writer and the dedup summary, got %d syncs:\n%s", writer.syncs, writer.String())
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := testLogger.Close(); err != nil {
+			t.Fatalf("Unexpected error from Close: %v", err)
+		}
+	}
+	if writer.closes != 1 {
+		t.Errorf("Expected writer to be closed once, got %d", writer.closes)
+	}
+
+	before := writer.Len()
+	testLogger.Error("after close")
+	child.Error("after close")
+	testLogger.ErrorStructured("after close", ZString("k", "v"))
+	if writer.Len() != before {
+		t.Errorf("Expected logging after Close to be a no-op, got %s", writer.String()[before:])
+	}
+}
+
+// gatedWriter blocks every Write until release is closed
+type gatedWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsyncLogging(t *testing.T) {
+	writer := &gatedWriter{release: make(chan struct{})}
+
+	testLogger, err := New(WithOutput(writer), WithAsync(2, DropNewest))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	fields := map[string]any{"password": "hunter2", "n": 0}
+	for i := 0; i < 10; i++ {
+		fields["n"] = i
+		testLogger.Info("queued", fields)
+	}
+	fields["password"] = "reused"
+
+	if dropped := testLogger.AsyncStats().Dropped; dropped == 0 {
+		t.Errorf("Expected entries to be dropped with a full queue")
+	}
+
+	close(writer.release)
+	if err := testLogger.Close(); err != nil {
+		t.Fatalf("Unexpected error from Close: %v", err)
+	}
+
+	out := writer.buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "reused") {
+		t.Errorf("Expected queued entries to be masked before queueing, got %s", out)
+	}
+	written := strings.Count(out, "\n")
+	if stats := testLogger.AsyncStats(); uint64(written)+stats.Dropped != 10 {
+		t.Errorf("Expected written + dropped to equal 10, got %d + %d", written, stats.Dropped)
+	}
+	if !strings.Contains(out, `"n":0`) {
+		t.Errorf("Expected the first entry to be written with DropNewest, got %s", out)
+	}
+
+	if _, err := New(WithAsync(0, Block)); err == nil {
+		t.Errorf("Expected error for non-positive buffer size")
+	}
+}
+
+func TestAsyncSyncWaitsForQueue(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithAsync(16, Block))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	defer testLogger.Close()
+
+	for i := 0; i < 100; i++ {
+		testLogger.Info("blocking", "n", i)
+	}
+	if err := testLogger.Sync(); err != nil {
+		t.Fatalf("Unexpected error from Sync: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 100 {
+		t.Errorf("Expected 100 lines after Sync, got %d", lines)
+	}
+}
+
+func TestReconfigureAsync(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithAsync(16, Block))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	defer testLogger.Close()
+
+	if err := testLogger.Reconfigure(WithAsync(4, Block), WithOutput(nil)); err == nil {
+		t.Fatal("Expected an error for an invalid option")
+	}
+	if async := testLogger.resolve().async; async.closed {
+		t.Error("Expected a failed Reconfigure to leave the async writer running")
+	}
+	testLogger.Info("after failure")
+	if err := testLogger.Reconfigure(WithAsync(4, Block)); err != nil {
+		t.Fatalf("Unexpected error reconfiguring: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		testLogger.Info("after swap", "n", i)
+	}
+	if err := testLogger.Sync(); err != nil {
+		t.Fatalf("Unexpected error from Sync: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 11 {
+		t.Errorf("Expected 11 lines after Sync, got %d", lines)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	var all, errorsOnly bytes.Buffer
+	failing := writerFunc(func(p []byte) (int, error) { return 0, errors.New("sink down") })
+
+	testLogger, err := New(WithSinks(
+		Sink{Writer: failing},
+		Sink{Writer: &all},
+		Sink{Writer: &errorsOnly, MinLevel: LevelError},
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("routine")
+	testLogger.Error("broken", "password", "hunter2")
+
+	if lines := strings.Count(all.String(), "\n"); lines != 2 {
+		t.Errorf("Expected both entries despite failing sink, got %d:\n%s", lines, all.String())
+	}
+	if strings.Contains(errorsOnly.String(), "routine") || !strings.Contains(errorsOnly.String(), "broken") {
+		t.Errorf("Expected only error entries in level-filtered sink, got %s", errorsOnly.String())
+	}
+	if strings.Contains(all.String(), "hunter2") {
+		t.Errorf("Expected masked output in every sink")
+	}
+
+	var a, b bytes.Buffer
+	_, err = MultiWriter(&a, failing, &b, failing).Write([]byte("line\n"))
+	if a.String() != "line\n" || b.String() != "line\n" {
+		t.Errorf("Expected every writer to receive the line")
+	}
+	if err == nil || strings.Count(err.Error(), "sink down") != 2 {
+		t.Errorf("Expected joined errors from both failing writers, got %v", err)
+	}
+}
+
+func TestFormatMethods(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Warnf("user %s failed login %d times", "alice", 3, Fields{"password": "hunter2"}, ZString("ip_address", "10.0.0.1"))
+	testLogger.Debugf("hidden %s", "debug")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON entry, got %v:\n%s", err, buf.String())
+	}
+	if entry.Message != "user alice failed login 3 times" {
+		t.Errorf("Unexpected formatted message: %q", entry.Message)
+	}
+	if entry.Fields["password"] != "***MASKED***" || entry.Fields["ip_address"] != "***PII***" {
+		t.Errorf("Expected trailing fields to be masked, got %v", entry.Fields)
+	}
+
+	buf.Reset()
+	testLogger.Infof("100%% done")
+	if !strings.Contains(buf.String(), `"message":"100% done"`) {
+		t.Errorf("Expected format without arguments to be formatted, got %s", buf.String())
+	}
+}
+
+func TestTypedFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	testLogger.Info("order placed",
+		String("order_id", "o-1"),
+		Int("items", 3),
+		Bool("gift", true),
+		Duration("elapsed", 1500*time.Millisecond),
+		Time("placed_at", at),
+		String("customer_email", "a@example.com"),
+		Err(errors.New("partial stock")),
+	)
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	expected := map[string]any{
+		"order_id":       "o-1",
+		"items":          float64(3),
+		"gift":           true,
+		"elapsed":        float64(1500 * time.Millisecond),
+		"placed_at":      "2024-05-01T12:00:00Z",
+		"customer_email": "***PII***",
+		"error":          "partial stock",
+	}
+	for key, want := range expected {
+		if got := entry.Fields[key]; got != want {
+			t.Errorf("Field %s: expected %v, got %v", key, want, got)
+		}
+	}
+}
+
+func TestLazyFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithLevel(LevelInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	calls := 0
+	expensive := func() any {
+		calls++
+		return map[string]any{"token": "abc123", "size": 42}
+	}
+
+	testLogger.Debug("filtered", Lazy("snapshot", expensive))
+	if calls != 0 {
+		t.Fatalf("Expected lazy field to be skipped for filtered level, got %d calls", calls)
+	}
+
+	testLogger.Info("emitted", Lazy("snapshot", expensive), Lazy("broken", func() any { panic("boom") }))
+	if calls != 1 {
+		t.Fatalf("Expected lazy field to be evaluated once, got %d calls", calls)
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	snapshot := entry.Fields["snapshot"].(map[string]any)
+	if snapshot["token"] != "***MASKED***" || snapshot["size"] != float64(42) {
+		t.Errorf("Expected materialized lazy value to be masked, got %v", snapshot)
+	}
+	if entry.Fields["broken"] != "!PANIC: boom" {
+		t.Errorf("Expected panic placeholder, got %v", entry.Fields["broken"])
+	}
+}
+
+func TestNamedLoggers(t *testing.T) {
+	var buf bytes.Buffer
+
+	root, err := New(WithOutput(&buf), WithLevel(LevelInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	auth := root.Named("http").Named("auth")
+	other := root.Named("https")
+
+	if auth.Name() != "http.auth" {
+		t.Fatalf("Expected dotted name, got %q", auth.Name())
+	}
+
+	SetLevelForPrefix("http", LevelDebug)
+	defer ClearLevelForPrefix("http")
+
+	auth.Debug("token refreshed")
+	other.Debug("not covered by prefix")
+	root.Debug("root stays at info")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single entry, got %v:\n%s", err, buf.String())
+	}
+	if entry.Fields["logger"] != "http.auth" {
+		t.Errorf("Expected logger field, got %v", entry.Fields)
+	}
+
+	SetLevelForPrefix("http.auth", LevelError)
+	defer ClearLevelForPrefix("http.auth")
+	if auth.Enabled(LevelWarn) || !root.Named("http").Named("client").Enabled(LevelDebug) {
+		t.Errorf("Expected longest prefix to win")
+	}
+}
+
+func TestTimestampOptions(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithTimeKey("@timestamp"), WithTimeFormat(time.RFC3339Nano), WithUTC())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("custom time")
+	testLogger.InfoStructured("custom time", ZString("timestamp", "user value"))
+
+	for i, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON: %v\n%s", err, line)
+		}
+		ts, ok := entry["@timestamp"].(string)
+		if !ok {
+			t.Fatalf("Expected @timestamp key in entry %d, got %v", i, entry)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, ts); err != nil || !strings.HasSuffix(ts, "Z") {
+			t.Errorf("Expected UTC RFC3339Nano timestamp, got %q", ts)
+		}
+		if _, ok := entry["timestamp"]; ok {
+			t.Errorf("Expected default timestamp key to be absent, got %v", entry)
+		}
+	}
+
+	buf.Reset()
+	epoch, err := New(WithOutput(&buf), WithTimeFormat(TimeFormatEpochMillis))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	before := time.Now().UnixMilli()
+	epoch.Warn("numeric time", "k", "v")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	millis, ok := entry["timestamp"].(float64)
+	if !ok || int64(millis) < before {
+		t.Errorf("Expected numeric epoch millis, got %v", entry["timestamp"])
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 678000000, time.FixedZone("CET", 3600))
+	clock := WithClock(func() time.Time { return fixed })
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"json", nil, `{"timestamp":"2025-01-02T02:04:05.678Z","level":"info","message":"fixed"}`},
+		{"json with fields", nil, `"timestamp":"2025-01-02T02:04:05.678Z"`},
+		{"logfmt", []Option{WithFormat(FormatLogfmt)}, `timestamp=2025-01-02T02:04:05.678Z`},
+		{"epoch", []Option{WithTimeFormat(TimeFormatEpochMillis)}, `"timestamp":1735783445678`},
+		{"layout", []Option{WithTimeFormat(time.Kitchen), WithUTC()}, `"timestamp":"2:04AM"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			testLogger, err := New(append([]Option{WithOutput(&buf), clock}, tc.opts...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error creating logger: %v", err)
+			}
+			if tc.name == "json with fields" {
+				testLogger.Info("fixed", "order_id", 7)
+			} else {
+				testLogger.Info("fixed")
+			}
+			if !strings.Contains(buf.String(), tc.want) {
+				t.Errorf("Expected %s in output, got %s", tc.want, buf.String())
+			}
+		})
+	}
+
+	if _, err := New(WithClock(nil)); err == nil {
+		t.Error("Expected an error for a nil clock")
+	}
+}
+
+func TestECSFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithFormat(FormatECS), WithComponent("checkout"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	pcs := make([]uintptr, 8)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+
+	ctx := WithContextFields(context.Background(), map[string]any{"trace_id": "abc123"})
+	testLogger.ErrorContext(ctx, "payment failed", "password", "hunter2", "cart_size", 3, Err(stackError{msg: "declined", pcs: pcs}))
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	for key, want := range map[string]any{
+		"log.level":     "error",
+		"message":       "payment failed",
+		"ecs.version":   ecsVersion,
+		"service.name":  "checkout",
+		"trace.id":      "abc123",
+		"error.message": "declined",
+	} {
+		if entry[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+	if _, ok := entry["@timestamp"].(string); !ok {
+		t.Errorf("Expected @timestamp, got %v", entry)
+	}
+	if trace, ok := entry["error.stack_trace"].(string); !ok || !strings.Contains(trace, "TestECSFormat") {
+		t.Errorf("Expected text stack trace, got %v", entry["error.stack_trace"])
+	}
+
+	labels, ok := entry["labels"].(map[string]any)
+	if !ok || labels["password"] != "***MASKED***" || labels["cart_size"] != float64(3) {
+		t.Errorf("Expected unknown fields under labels with masking, got %v", entry["labels"])
+	}
+}
+
+func TestGCPFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithFormat(FormatGCP), WithGCPProject("my-project"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Warn("slow query", "trace_id", "4bf92f3577b34da6", "span_id", "00f067aa0ba902b7", "email", "a@example.com")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	for key, want := range map[string]any{
+		"severity":                      "WARNING",
+		"message":                       "slow query",
+		"logging.googleapis.com/trace":  "projects/my-project/traces/4bf92f3577b34da6",
+		"logging.googleapis.com/spanId": "00f067aa0ba902b7",
+		"email":                         "***PII***",
+	} {
+		if entry[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+	if _, ok := entry["trace_id"]; ok {
+		t.Errorf("Expected trace_id to be replaced by the GCP trace key")
+	}
+
+	for level, want := range map[LogLevel]string{DEBUG: "DEBUG", INFO: "INFO", ERROR: "ERROR", ERROR + 1: "CRITICAL"} {
+		if got := gcpSeverity(level); got != want {
+			t.Errorf("Expected severity %s for level %d, got %s", want, level, got)
+		}
+	}
+}
+
+func TestCallerAnnotation(t *testing.T) {
+	var buf bytes.Buffer
+
+	testLogger, err := New(WithOutput(&buf), WithCaller(CallerLevel(WARN), CallerFunction()))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	callers := func() []map[string]any {
+		defer buf.Reset()
+		var out []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry struct {
+				Fields map[string]any `json:"fields"`
+			}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Invalid JSON: %v\n%s", err, line)
+			}
+			out = append(out, entry.Fields)
+		}
+		return out
+	}
+
+	testLogger.Warn("direct")
+	testLogger.ErrorStructured("structured", ZString("k", "v"))
+	testLogger.Info("below level")
+
+	entries := callers()
+	want, wantFunction := runnerCaller(0)
+	for i := range 2 {
+		if caller, _ := entries[i][callerKey].(string); caller != want {
+			t.Errorf("Entry %d: expected caller %s, got %q", i, want, caller)
+		}
+		if fn, _ := entries[i][callerFunctionKey].(string); fn != wantFunction {
+			t.Errorf("Entry %d: expected caller function %s, got %q", i, wantFunction, fn)
+		}
+	}
+	if _, ok := entries[2][callerKey]; ok {
+		t.Errorf("Expected no caller below the caller level")
+	}
+
+	wrapped, err := New(WithOutput(&buf), WithCaller(), WithCallerSkip(1))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	logWrapped := func(msg string) { wrapped.Info(msg) }
+
+	logWrapped("via wrapper")
+
+	// The wrapper is inside the package too, so the skip applies past the runner
+	if want, _ := runnerCaller(1); callers()[0][callerKey] != want {
+		t.Error("Expected WithCallerSkip to skip the first frame outside the package")
+	}
+}
+
+// runnerCaller returns the caller, and its function, that an entry logged
+// directly by a test reports when skip frames are skipped. Test functions are
+// inside the package, so the first frame outside it is the test runner.
+func runnerCaller(skip int) (string, string) {
+	pc, file, line, _ := runtime.Caller(2 + skip)
+	return shortCallerPath(file) + ":" + strconv.Itoa(line), runtime.FuncForPC(pc).Name()
+}
+
+func TestDefaultFields(t *testing.T) {
+	for _, format := range []OutputFormat{FormatJSON, FormatLogfmt} {
+		var buf bytes.Buffer
+
+		testLogger, err := New(WithOutput(&buf), WithFormat(format),
+			WithDefaultFields(map[string]any{"hostname": "web-1", "api_key": "sk-live-123", "region": "eu"}))
+		if err != nil {
+			t.Fatalf("Unexpected error creating logger: %v", err)
+		}
+
+		testLogger.Info("no fields")
+		testLogger.Info("with fields", "region", "us", "password", "hunter2")
+		testLogger.WithFields(map[string]any{"request_id": "r-1"}).Info("child")
+
+		output := buf.String()
+		if strings.Count(output, "web-1") != 3 {
+			t.Errorf("format %d: expected default field on every line:\n%s", format, output)
+		}
+		if strings.Contains(output, "sk-live-123") || strings.Contains(output, "hunter2") {
+			t.Errorf("format %d: expected sensitive values to be masked:\n%s", format, output)
+		}
+		if !strings.Contains(output, "us") || !strings.Contains(output, "r-1") {
+			t.Errorf("format %d: expected call-site and child fields:\n%s", format, output)
+		}
+	}
+
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf), WithDefaultFields(map[string]any{"region": "eu"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("override", "region", "us")
+
+	var entry struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if entry.Fields["region"] != "us" {
+		t.Errorf("Expected call-site field to override default, got %v", entry.Fields["region"])
+	}
+}
+
+func TestFieldMapNotModified(t *testing.T) {
+	testLogger, err := New(WithOutput(io.Discard), WithCaller(), WithStackTrace(INFO),
+		WithDefaultFields(map[string]any{"host": "web-1"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	fields := map[string]any{"password": "hunter2", "lazy": Lazy("lazy", func() any { return 1 })}
+	testLogger.WithFields(map[string]any{"request_id": "r-1"}).Info("call", fields)
+
+	if len(fields) != 2 || fields["password"] != "hunter2" {
+		t.Errorf("Expected the caller's field map to be left untouched, got %v", fields)
+	}
+	if _, ok := fields["lazy"].(LazyZField); !ok {
+		t.Errorf("Expected lazy field to stay unevaluated in the caller's map, got %v", fields["lazy"])
+	}
+
+	// Hooks, flattening and shown modes work on the entry, not the caller's map
+	sink := NewMemorySink()
+	hooked, err := New(WithOutput(sink), WithAsync(16, Block), WithSensitiveMode(SHOW_SENSITIVE), WithPIIMode(SHOW_PII),
+		WithFlatten("."), WithHook(func(entry *Entry) error {
+			entry.Fields["hooked"] = true
+			delete(entry.Fields, "password")
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	fields = map[string]any{"password": "hunter2", "user": map[string]any{"id": "u-1"}}
+	hooked.Info("call", fields)
+	fields["user"] = "changed"
+	if err := hooked.Sync(); err != nil {
+		t.Fatalf("Unexpected error syncing: %v", err)
+	}
+
+	if len(fields) != 2 || fields["password"] != "hunter2" {
+		t.Errorf("Expected hooks to leave the caller's map untouched, got %v", fields)
+	}
+	if entry, _ := sink.LastEntry(); entry.Fields["user.id"] != "u-1" || entry.Fields["hooked"] != true {
+		t.Errorf("Expected the entry as logged before the caller changed its map, got %v", entry.Fields)
+	}
+}
+
+func TestStructuredFieldsShape(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"hot path":    nil,
+		"mask string": {WithMaskString("[hidden]")},
+		"scan limit":  {WithScanLimit(8, 0)},
+		"sorted keys": {WithSortedKeys()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			testLogger, err := New(append([]Option{WithOutput(&buf), WithComponent("api"), WithVersion("1.0")}, opts...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error creating logger: %v", err)
+			}
+			testLogger.InfoStructured("few", ZString("user_id", "u-1"), ZInt("attempt", 2))
+			testLogger.InfoStructured("many", ZString("a", "1"), ZString("b", "2"), ZString("c", "3"),
+				ZString("d", "4"), ZBool("e", true), ZFloat64("f", 1.5))
+			testLogger.InfoStructured("none")
+
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("Expected valid JSON, got %q: %v", line, err)
+				}
+				fields, _ := entry["fields"].(map[string]any)
+				switch entry["message"] {
+				case "few":
+					if fields["user_id"] != "u-1" || fields["attempt"] != float64(2) || entry["user_id"] != nil {
+						t.Errorf("Expected fields under \"fields\", got %s", line)
+					}
+				case "many":
+					if len(fields) != 6 || fields["f"] != 1.5 {
+						t.Errorf("Expected fields under \"fields\", got %s", line)
+					}
+				case "none":
+					if _, ok := entry["fields"]; ok {
+						t.Errorf("Expected no fields object, got %s", line)
+					}
+				}
+				if entry["component"] != "api" || entry["version"] != "1.0" {
+					t.Errorf("Expected component and version at the top level, got %s", line)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFieldMap(b *testing.B) {
+	testLogger, err := New(WithOutput(io.Discard))
+	if err != nil {
+		b.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	fields := map[string]any{"user_id": "u-1", "attempt": 3, "password": "hunter2"}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		testLogger.Info("call", fields)
+	}
+}
+
+func TestStats(t *testing.T) {
+	testLogger, err := New(WithOutput(io.Discard), WithLevel(DEBUG), WithSampling(1, 0), WithDedup(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	defer testLogger.Close()
+
+	testLogger.Info("request", "password", "hunter2", "request_id", "r-1")
+	testLogger.Info("request", "password", "hunter2", "request_id", "r-1") // sampled
+	testLogger.WithFields(map[string]any{"email": "a@example.com"}).Error("failed")
+	testLogger.Debug("startup")
+	testLogger.DebugStructured("cache", ZString("token", "t"))
+
+	stats := testLogger.Stats()
+	if stats.Emitted[INFO] != 1 || stats.Emitted[ERROR] != 1 || stats.Emitted[DEBUG] != 2 {
+		t.Errorf("Unexpected emitted counts: %v", stats.Emitted)
+	}
+	if stats.Sampled != 1 {
+		t.Errorf("Expected 1 sampled entry, got %d", stats.Sampled)
+	}
+	if stats.MaskedFields != 3 {
+		t.Errorf("Expected 3 masked fields, got %d", stats.MaskedFields)
+	}
+
+	dedupLogger, err := New(WithOutput(io.Discard), WithDedup(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	defer dedupLogger.Close()
+	for range 3 {
+		dedupLogger.Warn("disk almost full")
+	}
+	if stats := dedupLogger.Stats(); stats.Deduplicated != 2 || stats.Emitted[WARN] != 1 {
+		t.Errorf("Expected 2 deduplicated and 1 emitted entry, got %+v", stats)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	first := NewMemorySink()
+	second := NewMemorySink()
+	firstLogger, _ := New(WithOutput(first))
+	secondLogger, _ := New(WithOutput(second))
+
+	SetDefault(firstLogger)
+	if Default() != firstLogger {
+		t.Fatal("Expected Default to return the logger passed to SetDefault")
+	}
+	Info.Msg("to first")
+	Errorf("failed: %s", "first")
+
+	SetDefault(nil)
+	if Default() != firstLogger {
+		t.Error("Expected SetDefault(nil) to keep the current default")
+	}
+
+	SetDefault(secondLogger)
+	Info.KeyValue("to second", "password", "hunter2")
+
+	if len(first.Entries()) != 2 || !first.Contains(ERROR, "failed: first") {
+		t.Errorf("Unexpected entries on first logger: %+v", first.Entries())
+	}
+	entry, ok := second.LastEntry()
+	if !ok || entry.Message != "to second" || entry.Fields["password"] != defaultMaskString {
+		t.Errorf("Expected masked entry on second logger, got %+v", entry)
+	}
+
+	// Swapping while other goroutines log must be race-free
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				if i == 0 && j%2 == 0 {
+					SetDefault(firstLogger)
+				} else if i == 0 {
+					SetDefault(secondLogger)
+				}
+				Info.Msg("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if total := len(first.Entries()) + len(second.Entries()); total != 403 {
+		t.Errorf("Expected 403 entries across both loggers, got %d", total)
+	}
+}
+
+func TestCheckAndConditionalLogging(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithCaller())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	if ce := testLogger.Check(DEBUG); ce != nil {
+		t.Error("Expected Check to return nil for a filtered level")
+	}
+	testLogger.Check(DEBUG).Write("ignored") // nil entries are safe to write
+
+	if ce := testLogger.Check(WARN); ce != nil {
+		ce.Write("cache state", "entries", 3, "token", "t-1")
+	}
+	testLogger.InfoIf(false, "skipped")
+	testLogger.InfoIf(true, "kept")
+	testLogger.ErrorIf(true, "failed")
+
+	entries := sink.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v", entries)
+	}
+	if entries[0].Level != WARN || entries[0].Fields["entries"] != float64(3) || entries[0].Fields["token"] != defaultMaskString {
+		t.Errorf("Unexpected checked entry: %+v", entries[0])
+	}
+	if want, _ := runnerCaller(0); entries[0].Fields["caller"] != want {
+		t.Errorf("Expected caller %s, got %v", want, entries[0].Fields["caller"])
+	}
+	if entries[1].Message != "kept" || entries[2].Level != ERROR {
+		t.Errorf("Unexpected conditional entries: %+v", entries[1:])
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	const (
+		trace  = DEBUG - 4
+		notice = INFO + 2
+	)
+	if err := RegisterLevel("trace", trace); err != nil {
+		t.Fatalf("Unexpected error registering trace: %v", err)
+	}
+	if err := RegisterLevel("NOTICE", notice); err != nil {
+		t.Fatalf("Unexpected error registering notice: %v", err)
+	}
+	if err := RegisterLevel("warning", WARN+1); err == nil {
+		t.Error("Expected an error for a built-in level name")
+	}
+	if err := RegisterLevel("verbose", INFO); err == nil {
+		t.Error("Expected an error for a built-in level value")
+	}
+	if err := RegisterLevel("trace", trace-1); err == nil {
+		t.Error("Expected an error for a name registered at another level")
+	}
+
+	if level, err := ParseLevel("Notice"); err != nil || level != notice {
+		t.Errorf("Expected ParseLevel to find notice, got %v, %v", level, err)
+	}
+	if notice.String() != "notice" || trace.String() != "trace" {
+		t.Errorf("Unexpected level names %q and %q", notice.String(), trace.String())
+	}
+
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.SetLevel(ParseLogLevel("notice"))
+	testLogger.log(trace, "filtered", nil)
+	testLogger.Info("filtered")
+	testLogger.log(notice, "config reloaded", map[string]any{"password": "hunter2"})
+	testLogger.logStructuredFields(notice, "structured", ZString("user_id", "u-1"))
+	testLogger.Warn("kept")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines at notice and above, got %q", buf.String())
+	}
+	for _, line := range lines[:2] {
+		if !strings.Contains(line, `"level":"notice"`) {
+			t.Errorf("Expected the custom level name, got %s", line)
+		}
+	}
+	if !strings.Contains(lines[0], defaultMaskString) {
+		t.Errorf("Expected masking at a custom level, got %s", lines[0])
+	}
+	if gcpSeverity(notice) != "NOTICE" || gcpSeverity(trace) != "DEBUG" {
+		t.Errorf("Unexpected GCP severities %s and %s", gcpSeverity(notice), gcpSeverity(trace))
+	}
+}
+
+func TestHooks(t *testing.T) {
+	sink := NewMemorySink()
+	var order []string
+	var seenPassword any
+	var hookErrors []error
+	testLogger, err := New(WithOutput(sink),
+		WithErrorHandler(func(err error) { hookErrors = append(hookErrors, err) }),
+		WithHook(func(e *Entry) error {
+			order = append(order, "first")
+			if password, ok := e.Fields["password"]; ok {
+				seenPassword = password
+			}
+			if e.Message == "healthcheck" {
+				return ErrSkipEntry
+			}
+			e.Fields["region"] = "eu-west-1"
+			return nil
+		}),
+		WithHook(func(e *Entry) error {
+			order = append(order, "second")
+			e.Message = strings.ToUpper(e.Message)
+			return errors.New("lookup unavailable")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("healthcheck")
+	testLogger.Info("login", "password", "hunter2")
+	testLogger.InfoStructured("structured", ZString("user_id", "u-1"))
+
+	if want := []string{"first", "first", "second", "first", "second"}; !slices.Equal(order, want) {
+		t.Errorf("Expected hooks in registration order %v, got %v", want, order)
+	}
+	if seenPassword != defaultMaskString {
+		t.Errorf("Expected hooks to see masked values, got %v", seenPassword)
+	}
+	if len(hookErrors) != 2 || !strings.Contains(hookErrors[0].Error(), "lookup unavailable") {
+		t.Errorf("Expected hook errors to be reported, got %v", hookErrors)
+	}
+
+	entries := sink.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected the skipped entry to be dropped, got %+v", entries)
+	}
+	login := entries[0]
+	if login.Message != "LOGIN" || login.Fields["region"] != "eu-west-1" || login.Fields["password"] != defaultMaskString {
+		t.Errorf("Unexpected hooked entry: %+v", login)
+	}
+	if entries[1].Message != "STRUCTURED" || entries[1].Fields["user_id"] != "u-1" {
+		t.Errorf("Expected structured fields to pass through hooks, got %+v", entries[1])
+	}
+
+	if _, err := New(WithHook(nil)); err == nil {
+		t.Error("Expected an error for a nil hook")
+	}
+}
+
+func TestHooksMaskOnce(t *testing.T) {
+	sink := NewMemorySink()
+	var observed []string
+	testLogger, err := New(WithOutput(sink),
+		WithMaskObserver(func(field string, _ MaskCategory) { observed = append(observed, field) }),
+		WithHook(func(e *Entry) error {
+			e.Fields["hooked"] = true
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	var calls int
+	testLogger.RegisterMaskFunc("card", func(value any) any {
+		calls++
+		return "X" + fmt.Sprint(value)
+	})
+
+	testLogger.Info("payment", "card", "1234")
+	entry, _ := sink.LastEntry()
+	if calls != 1 || entry.Fields["card"] != "X1234" || entry.Fields["hooked"] != true {
+		t.Errorf("Expected the mask function applied once, got %d calls and %v", calls, entry.Fields)
+	}
+	if len(observed) != 1 || testLogger.Stats().MaskedFields != 1 {
+		t.Errorf("Expected one observed and counted mask, got %v and %d", observed, testLogger.Stats().MaskedFields)
+	}
+	if testLogger.Stats().Emitted[INFO] != 1 {
+		t.Errorf("Expected the entry to be counted once, got %v", testLogger.Stats().Emitted)
+	}
+}
+
+func TestFloatFormat(t *testing.T) {
+	decode := func(t *testing.T, line string) map[string]any {
+		t.Helper()
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Expected valid JSON, got %q: %v", line, err)
+		}
+		return entry
+	}
+
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("magnitudes", "large", 1e22, "small", 1e-7, "plain", 123456789.0, "nan", math.NaN())
+	line := buf.String()
+	for _, want := range []string{`"large":1e+22`, `"small":1e-7`, `"plain":123456789`, `"nan":null`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %s in %s", want, line)
+		}
+	}
+	decode(t, line)
+
+	buf.Reset()
+	testLogger.InfoStructured("structured", ZFloat64("ratio", math.Inf(1)), ZFloat64("amount", 123456789.5))
+	if fields, _ := decode(t, buf.String())["fields"].(map[string]any); fields["ratio"] != nil || fields["amount"] != 123456789.5 {
+		t.Errorf("Unexpected structured floats: %s", buf.String())
+	}
+
+	buf.Reset()
+	fixedLogger, err := New(WithOutput(&buf), WithFloatFormat('f', 2), WithNonFiniteFloatsAsStrings())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	fixedLogger.Info("fixed", "price", 3.14159, "large", 1e22, "nested", map[string]any{"cost": float32(0.5)}, "inf", math.Inf(-1))
+	fixedLogger.InfoStructured("structured", ZFloat64("nan", math.NaN()))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, want := range []string{`"price":3.14`, `"large":10000000000000000000000.00`, `"cost":0.50`, `"inf":"-Inf"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("Expected %s in %s", want, lines[0])
+		}
+	}
+	if fields := decode(t, lines[1])["fields"].(map[string]any); fields["nan"] != "NaN" {
+		t.Errorf("Expected the NaN sentinel, got %s", lines[1])
+	}
+
+	if _, err := New(WithFloatFormat('x', -1)); err == nil {
+		t.Error("Expected an error for an unknown float format")
+	}
+	if _, err := New(WithFloatFormat('f', -2)); err == nil {
+		t.Error("Expected an error for an invalid precision")
+	}
+}
+
+func TestTimeAndDurationValues(t *testing.T) {
+	local := time.Date(2024, 3, 1, 12, 30, 0, 500, time.FixedZone("CET", 3600))
+
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("defaults", "at", local, "took", 1500*time.Millisecond)
+	for _, want := range []string{`"at":"2024-03-01T12:30:00.0000005+01:00"`, `"took":1500000000`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %s in %s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	utcLogger, err := New(WithOutput(&buf), WithUTC(), WithDurationFormat(DurationString))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	utcLogger.Info("custom", "at", local, "took", 1500*time.Millisecond)
+	utcLogger.InfoStructured("structured", ZDuration("took", time.Minute))
+	for _, want := range []string{`"at":"2024-03-01T11:30:00.0000005Z"`, `"took":"1.5s"`, `"took":"1m0s"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %s in %s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	logfmtLogger, err := New(WithOutput(&buf), WithFormat(LOGFMT_FORMAT), WithDurationFormat(DurationNanos))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	logfmtLogger.Info("logfmt", "took", 1500*time.Millisecond)
+	if !strings.Contains(buf.String(), "took=1500000000") {
+		t.Errorf("Expected nanoseconds in logfmt output, got %s", buf.String())
+	}
+
+	if _, err := New(WithDurationFormat(DurationFormat(0))); err == nil {
+		t.Error("Expected an error for an unknown duration format")
+	}
+}
+
+// failingMarshaler is a value whose MarshalJSON always fails
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) { return nil, errors.New("boom") }
+
+func TestUnmarshalableFieldValues(t *testing.T) {
+	for _, opts := range [][]Option{
+		nil,
+		{WithStreamingEncoder()},
+		{WithFormat(ECS_FORMAT)},
+	} {
+		var buf bytes.Buffer
+		var reported []error
+		opts = append(opts, WithOutput(&buf), WithErrorHandler(func(err error) { reported = append(reported, err) }))
+		testLogger, err := New(opts...)
+		if err != nil {
+			t.Fatalf("Unexpected error creating logger: %v", err)
+		}
+
+		testLogger.Info("partial", "bad", failingMarshaler{}, "events", make(chan int), "user_id", "u-1",
+			"nested", map[string]any{"ok": 1, "handler": func() {}})
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected valid JSON, got %q: %v", buf.String(), err)
+		}
+		line := buf.String()
+		for _, want := range []string{
+			`"!ERROR: emit.failingMarshaler: json: error calling MarshalJSON for type *emit.failingMarshaler: boom"`,
+			`"!ERROR: chan int: json: unsupported type: chan int"`,
+			`"!ERROR: func(): json: unsupported type: func()"`,
+			`"u-1"`,
+			`"ok":1`,
+		} {
+			if !strings.Contains(line, want) {
+				t.Errorf("Expected %s in %s", want, line)
+			}
+		}
+		if len(reported) != 0 {
+			t.Errorf("Expected the entry to be written without errors, got %v", reported)
+		}
+	}
+}
+
+// testStatus is an enum with a text form
+type testStatus int
+
+func (s testStatus) String() string { return [...]string{"pending", "active"}[s] }
+
+// testVersion is a Stringer whose JSON encoding takes precedence
+type testVersion struct{ Major, Minor int }
+
+func (v testVersion) String() string               { return fmt.Sprintf("v%d.%d", v.Major, v.Minor) }
+func (v testVersion) MarshalJSON() ([]byte, error) { return []byte(`{"major":1}`), nil }
+
+// testNamed has a pointer-receiver String
+type testNamed struct{ name string }
+
+func (n *testNamed) String() string { return n.name }
+
+func TestStringerAndErrorValues(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithStreamingEncoder()}, {WithSensitiveMode(SHOW_SENSITIVE), WithPIIMode(SHOW_PII)}} {
+		var buf bytes.Buffer
+		testLogger, err := New(append(opts, WithOutput(&buf))...)
+		if err != nil {
+			t.Fatalf("Unexpected error creating logger: %v", err)
+		}
+
+		testLogger.Info("values",
+			"status", testStatus(1),
+			"cause", errors.New("connection refused"),
+			"gateway", net.IPv4(10, 0, 0, 1),
+			"release", testVersion{Major: 1},
+			"owner", (*testNamed)(nil),
+			"nested", map[string]any{"status": testStatus(0)},
+		)
+		line := buf.String()
+		for _, want := range []string{`"status":"active"`, `"cause":"connection refused"`, `"gateway":"10.0.0.1"`, `"release":{"major":1}`, `"owner":null`} {
+			if !strings.Contains(line, want) {
+				t.Errorf("Expected %s in %s", want, line)
+			}
+		}
+		if len(opts) == 0 && !strings.Contains(line, `"status":"pending"`) {
+			t.Errorf("Expected nested Stringer values as text, got %s", line)
+		}
+	}
+}
+
+func TestKeyTransformer(t *testing.T) {
+	for in, want := range map[string]string{
+		"userId": "user_id", "UserID": "user_id", "HTTPStatus": "http_status", "user-name": "user_name",
+		"http.statusCode": "http.status_code", "already_snake": "already_snake", "retry2Count": "retry2_count",
+	} {
+		if got := ToSnakeCase(in); got != want {
+			t.Errorf("ToSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for in, want := range map[string]string{
+		"user_id": "userId", "user-name": "userName", "UserID": "userID", "HTTPStatus": "httpStatus",
+		"http.status_code": "http.statusCode", "alreadyCamel": "alreadyCamel",
+	} {
+		if got := ToCamelCase(in); got != want {
+			t.Errorf("ToCamelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithKeyTransformer(ToSnakeCase))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("signup",
+		"orderId", "o-1",
+		"apiKey", "sk-123",
+		"requestMeta", map[string]any{"clientVersion": "1.2", "items": []any{map[string]any{"itemId": 3}}},
+	)
+
+	entry, _ := sink.LastEntry()
+	if entry.Fields["order_id"] != "o-1" || entry.Fields["orderId"] != nil {
+		t.Errorf("Expected snake_case keys, got %v", entry.Fields)
+	}
+	if entry.Fields["api_key"] != "***MASKED***" {
+		t.Errorf("Expected masking to apply before transformation, got %v", entry.Fields["api_key"])
+	}
+	meta, _ := entry.Fields["request_meta"].(map[string]any)
+	items, _ := meta["items"].([]any)
+	if meta["client_version"] != "1.2" || len(items) != 1 || items[0].(map[string]any)["item_id"] != float64(3) {
+		t.Errorf("Expected nested keys to be transformed, got %v", entry.Fields["request_meta"])
+	}
+
+	if _, err := New(WithKeyTransformer(nil)); err == nil {
+		t.Error("Expected an error for a nil key transformer")
+	}
+}
+
+func TestFieldLimits(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithMaxFields(3), WithMaxValueLen(8))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("upload",
+		"a_blob", strings.Repeat("QUJD", 1000),
+		"b_greeting", "héllo wörld",
+		"c_nested", map[string]any{"chunk": strings.Repeat("x", 20)},
+		"d_extra", 1,
+		"e_extra", 2,
+	)
+
+	entry, _ := sink.LastEntry()
+	if len(entry.Fields) != 4 || entry.Fields["fields_dropped"] != float64(2) || entry.Fields["d_extra"] != nil {
+		t.Errorf("Expected 3 fields and a dropped count, got %v", entry.Fields)
+	}
+	if entry.Fields["a_blob"] != "QUJDQUJD...(truncated)" {
+		t.Errorf("Expected a truncated blob, got %v", entry.Fields["a_blob"])
+	}
+	if entry.Fields["b_greeting"] != "héllo w...(truncated)" {
+		t.Errorf("Expected truncation on a rune boundary, got %v", entry.Fields["b_greeting"])
+	}
+	if nested, _ := entry.Fields["c_nested"].(map[string]any); nested["chunk"] != "xxxxxxxx...(truncated)" {
+		t.Errorf("Expected nested strings to be truncated, got %v", entry.Fields["c_nested"])
+	}
+
+	sink.Reset()
+	testLogger.Info("login", "password", "correct-horse-battery")
+	if entry, _ := sink.LastEntry(); entry.Fields["password"] != "***MASKED***" {
+		t.Errorf("Expected masking before truncation, got %v", entry.Fields["password"])
+	}
+
+	if _, err := New(WithMaxFields(0)); err == nil {
+		t.Error("Expected an error for a non-positive field limit")
+	}
+	if _, err := New(WithMaxValueLen(-1)); err == nil {
+		t.Error("Expected an error for a non-positive value length")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithFlatten("."))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("signup",
+		"account", map[string]any{"plan": "pro", "billing": map[string]any{"password": "hunter2", "country": "NO"}},
+		"items", []any{map[string]any{"sku": "a-1"}},
+		"empty", map[string]any{},
+	)
+
+	entry, _ := sink.LastEntry()
+	if entry.Fields["account.plan"] != "pro" || entry.Fields["account.billing.country"] != "NO" {
+		t.Errorf("Expected flat keys, got %v", entry.Fields)
+	}
+	if entry.Fields["account.billing.password"] != "***MASKED***" {
+		t.Errorf("Expected nested sensitive keys to be masked before flattening, got %v", entry.Fields)
+	}
+	if _, ok := entry.Fields["items"].([]any); !ok {
+		t.Errorf("Expected slices to be kept by default, got %v", entry.Fields["items"])
+	}
+	if _, ok := entry.Fields["empty"].(map[string]any); !ok {
+		t.Errorf("Expected empty maps to be kept, got %v", entry.Fields)
+	}
+
+	sliceLogger, err := New(WithOutput(sink), WithFlatten("_"), WithFlattenSlices())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	sliceLogger.Info("order", "items", []any{map[string]any{"sku": "a-1"}, "gift"})
+	entry, _ = sink.LastEntry()
+	if entry.Fields["items_0_sku"] != "a-1" || entry.Fields["items_1"] != "gift" {
+		t.Errorf("Expected indexed slice keys, got %v", entry.Fields)
+	}
+
+	if _, err := New(WithFlatten("")); err == nil {
+		t.Error("Expected an error for an empty separator")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("EMIT_LEVEL", "warn")
+	t.Setenv("EMIT_FORMAT", "logfmt")
+	t.Setenv("EMIT_MASK_SENSITIVE", "hash")
+	t.Setenv("EMIT_PII_MASK_STRING", "[PII]")
+	t.Setenv("EMIT_TIME_FORMAT", "epoch_millis")
+	t.Setenv("EMIT_COMPONENT", "")
+
+	var buf bytes.Buffer
+	testLogger, err := FromEnv(WithOutput(&buf), WithLevel(DEBUG), WithComponent("checkout"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	if testLogger.GetLevel() != WARN || testLogger.format != LOGFMT_FORMAT || testLogger.sensitiveMode != HASH_SENSITIVE {
+		t.Errorf("Expected variables to override options, got level %v format %v mode %v",
+			testLogger.GetLevel(), testLogger.format, testLogger.sensitiveMode)
+	}
+	if testLogger.component != "checkout" || testLogger.piiMaskString != "[PII]" || testLogger.timeFormat != TimeFormatEpochMillis {
+		t.Errorf("Expected options to apply where variables are unset or empty, got %+v", testLogger)
+	}
+
+	for name, value := range map[string]string{
+		"EMIT_LEVEL":          "verbose",
+		"EMIT_FORMAT":         "yaml",
+		"EMIT_SHOW_CALLER":    "maybe",
+		"EMIT_MASK_PII":       "hash",
+		"EMIT_TIME_FORMAT":    "iso",
+		"EMIT_MASK_SENSITIVE": "partial",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := FromEnv(); err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected an error naming %s, got %v", name, err)
+			}
+		})
+	}
+
+	// The default logger keeps plain text for console and dev
+	for _, name := range []string{"EMIT_LEVEL", "EMIT_MASK_SENSITIVE", "EMIT_PII_MASK_STRING"} {
+		t.Setenv(name, "")
+	}
+	defaultFormat := Default().format
+	defer func() { Default().format = defaultFormat }()
+	for value, want := range map[string]OutputFormat{"dev": PLAIN_FORMAT, "console": PLAIN_FORMAT, "ecs": ECS_FORMAT, "yaml": JSON_FORMAT} {
+		t.Setenv("EMIT_FORMAT", value)
+		initFromEnvironment()
+		if Default().format != want {
+			t.Errorf("Expected EMIT_FORMAT=%s to select format %v for the default logger, got %v", value, want, Default().format)
+		}
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	fields := map[string]FieldType{
+		"order_id": FieldString,
+		"attempt":  FieldInt,
+		"amount":   FieldFloat,
+		"elapsed":  FieldDuration,
+	}
+	newLogger := func(opts ...SchemaOption) (*Logger, *bytes.Buffer, *[]error) {
+		var buf bytes.Buffer
+		var reported []error
+		testLogger, err := New(WithOutput(&buf), WithSchema(fields, opts...),
+			WithErrorHandler(func(err error) { reported = append(reported, err) }))
+		if err != nil {
+			t.Fatalf("Unexpected error creating logger: %v", err)
+		}
+		return testLogger, &buf, &reported
+	}
+	decode := func(buf *bytes.Buffer) map[string]any {
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+		}
+		fields, _ := entry["fields"].(map[string]any)
+		return fields
+	}
+
+	testLogger, buf, reported := newLogger()
+	testLogger.Info("valid", "order_id", "A-1", "attempt", 2, "amount", 3, "elapsed", time.Second)
+	if len(*reported) != 0 {
+		t.Errorf("Expected no violations, got %v", *reported)
+	}
+
+	buf.Reset()
+	testLogger.Info("invalid", "order_id", 17, "gateway", "stripe")
+	if len(*reported) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", *reported)
+	}
+	for _, err := range *reported {
+		if !errors.Is(err, ErrSchemaViolation) {
+			t.Errorf("Expected ErrSchemaViolation, got %v", err)
+		}
+	}
+	if entry := decode(buf); entry["order_id"] != float64(17) || entry["gateway"] != "stripe" {
+		t.Errorf("Expected the entry to be written unchanged, got %v", entry)
+	}
+
+	testLogger, buf, reported = newLogger(SchemaCoerce(), SchemaDropUnknown())
+	testLogger.Info("coerced", "order_id", 17, "attempt", "3", "amount", "1.5", "elapsed", "2s", "gateway", "stripe")
+	entry := decode(buf)
+	if entry["order_id"] != "17" || entry["attempt"] != float64(3) || entry["amount"] != 1.5 || entry["elapsed"] != float64(2*time.Second) {
+		t.Errorf("Expected coerced values, got %v", entry)
+	}
+	if _, ok := entry["gateway"]; ok {
+		t.Errorf("Expected the undeclared field to be dropped, got %v", entry)
+	}
+	if len(*reported) != 1 {
+		t.Errorf("Expected only the undeclared field to be reported, got %v", *reported)
+	}
+
+	buf.Reset()
+	*reported = nil
+	testLogger.Info("uncoercible", "attempt", "three")
+	if len(*reported) != 1 || decode(buf)["attempt"] != "three" {
+		t.Errorf("Expected the uncoercible value to be reported and kept, got %v, %v", *reported, buf.String())
+	}
+
+	if _, err := New(WithSchema(map[string]FieldType{"order_id": FieldType(99)})); err == nil {
+		t.Error("Expected an error for an unknown field type")
+	}
+}
+
+func TestWithRuntimeInfo(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("Hostname unavailable: %v", err)
+	}
+
+	sink := NewMemorySink()
+	markPII := func(l *Logger) error {
+		l.AddPIIField("host", "pid")
+		return nil
+	}
+	testLogger, err := New(WithOutput(sink), markPII, WithRuntimeInfo(), WithDefaultFields(map[string]any{"region": "eu"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("started")
+	entry, _ := sink.LastEntry()
+	if entry.Fields["host"] != host || entry.Fields["pid"] != float64(os.Getpid()) || entry.Fields["region"] != "eu" {
+		t.Errorf("Expected unmasked runtime fields beside the defaults, got %v", entry.Fields)
+	}
+
+	sink.Reset()
+	testLogger, err = New(WithOutput(sink), WithRuntimeInfo(RuntimeOmitPID()), WithDefaultFields(map[string]any{"host": "edge-1"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("started")
+	entry, _ = sink.LastEntry()
+	if _, ok := entry.Fields["pid"]; ok || entry.Fields["host"] != "edge-1" {
+		t.Errorf("Expected no pid and the default host to win, got %v", entry.Fields)
+	}
+
+	sink.Reset()
+	testLogger, err = New(WithOutput(sink), WithRuntimeInfo(RuntimeOmitHost(), RuntimeOmitPID()))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("started")
+	if entry, _ := sink.LastEntry(); len(entry.Fields) != 0 {
+		t.Errorf("Expected no runtime fields, got %v", entry.Fields)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	fields := Fields{"table": "orders"}
+	span := testLogger.StartSpan("db.query", fields)
+	span.SetField("rows", 3)
+	now = now.Add(1500 * time.Microsecond)
+	span.Finish()
+	span.Finish()
+
+	entries := sink.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected start and finish entries, got %d", len(entries))
+	}
+	start, finish := entries[0], entries[1]
+	if start.Message != "span started" || start.Fields["span"] != "db.query" || start.Fields["table"] != "orders" {
+		t.Errorf("Unexpected start entry: %+v", start)
+	}
+	if _, ok := start.Fields["rows"]; ok {
+		t.Errorf("Expected SetField to apply to the finish entry only, got %v", start.Fields)
+	}
+	if finish.Level != INFO || finish.Fields["span_id"] != span.ID() || start.Fields["span_id"] != span.ID() {
+		t.Errorf("Expected both entries to share the span ID, got %v and %v", start.Fields, finish.Fields)
+	}
+	if finish.Fields["duration_ms"] != 1.5 || finish.Fields["rows"] != float64(3) {
+		t.Errorf("Unexpected finish fields: %v", finish.Fields)
+	}
+	if _, ok := fields["rows"]; ok {
+		t.Error("Expected SetField not to modify the caller's fields")
+	}
+
+	sink.Reset()
+	failed := testLogger.StartSpan("charge")
+	failed.Fail(errors.New("card declined"))
+	failed.Finish()
+	if entry, _ := sink.LastEntry(); entry.Level != ERROR || entry.Fields["error"] != "card declined" {
+		t.Errorf("Expected a failed span to finish at error level, got %+v", entry)
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithSource("mylib"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	child := testLogger.Named("cache").WithFields(map[string]any{"source": "app"})
+	child.Info("evicted", "source", "caller")
+	child.InfoStructured("hit", ZString("order_id", "A-1"))
+	testLogger.Info("plain")
+
+	for _, entry := range sink.Entries() {
+		if entry.Fields["source"] != "mylib" {
+			t.Errorf("Expected source mylib on %q, got %v", entry.Message, entry.Fields)
+		}
+	}
+	if entries := sink.Entries(); len(entries) != 3 || entries[0].Fields["logger"] != "cache" {
+		t.Errorf("Expected 3 entries with the child's name kept, got %+v", entries)
+	}
+
+	if _, err := New(WithSource("")); err == nil {
+		t.Error("Expected an error for an empty source")
+	}
+}
+
+func FuzzJSONEscaping(f *testing.F) {
+	for _, seed := range []string{
+		"", "plain", `say "hi"`, `C:\path`, "line\nbreak\ttab\r", "\x00\x01\x1f\x7f",
+		"\xff\xfe", "caf\xc3", "héllo 世界", "<script>&amp;</script>", "\u2028\u2029",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		want := string([]rune(s)) // each invalid byte becomes U+FFFD
+		for _, html := range []bool{false, true} {
+			out := appendJSONString(nil, s, html)
+			var got string
+			if err := json.Unmarshal(out, &got); err != nil || got != want {
+				t.Fatalf("appendJSONString(%q, %v) = %s, decoded %q, %v", s, html, out, got, err)
+			}
+			if html && bytes.ContainsAny(out, "<>&") {
+				t.Fatalf("Expected HTML characters escaped, got %s", out)
+			}
+			if jsonSafe(s, html) && string(out[1:len(out)-1]) != s {
+				t.Fatalf("jsonSafe(%q) but escaping changed it to %s", s, out)
+			}
+		}
+
+		// Every encoder: the no-field hot path, structured fields, the map
+		// path and the streaming encoder
+		for _, opts := range [][]Option{
+			{WithComponent(s)},
+			{WithVersion(s), WithHTMLSafeJSON()},
+			{WithStreamingEncoder()},
+		} {
+			var buf bytes.Buffer
+			testLogger, err := New(append(opts, WithOutput(&buf))...)
+			if err != nil {
+				t.Fatalf("Unexpected error creating logger: %v", err)
+			}
+			testLogger.Info(s)
+			testLogger.InfoStructured(s, ZString("k"+s, s), ZInt("n"+s, 1))
+			testLogger.Info(s, "k"+s, s, "nested", map[string]any{s: []string{s}})
+
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("Invalid JSON for %q: %s: %v", s, line, err)
+				}
+				if entry["message"] != want {
+					t.Fatalf("Expected message %q, got %q", want, entry["message"])
+				}
+			}
+		}
+	})
+}
+
+func TestMessageTranslatorAndLevelNames(t *testing.T) {
+	french := map[string]string{"payment accepted": "paiement accepté", "cache miss": "défaut de cache"}
+	translate := func(level LogLevel, message string) string {
+		if translated, ok := french[message]; ok {
+			return translated
+		}
+		return message
+	}
+
+	var buf bytes.Buffer
+	testLogger, err := New(
+		WithOutput(&buf),
+		WithLevel(INFO),
+		WithMessageTranslator(translate),
+		WithLevelNames(map[LogLevel]string{INFO: "INFORMATION"}),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Debug("cache miss")
+	testLogger.Info("payment accepted")
+	testLogger.InfoStructured("payment accepted", ZString("order_id", "A-1"))
+	testLogger.Warn("cache miss", "key", "user:42")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected translation not to affect filtering, got %d lines: %q", len(lines), lines)
+	}
+	for i, want := range []struct{ level, message string }{
+		{"INFORMATION", "paiement accepté"},
+		{"INFORMATION", "paiement accepté"},
+		{"warn", "défaut de cache"},
+	} {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", lines[i], err)
+		}
+		if entry["level"] != want.level || entry["message"] != want.message {
+			t.Errorf("Expected %s %q, got %v %q", want.level, want.message, entry["level"], entry["message"])
+		}
+	}
+
+	buf.Reset()
+	consoleLogger, err := New(WithOutput(&buf), WithFormat(FormatConsole), WithLevelNames(map[LogLevel]string{INFO: "INFORMATION"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	consoleLogger.Info("ready")
+	consoleLogger.Error("boom")
+	if out := buf.String(); !strings.Contains(out, " INFORMATION ready") || !strings.Contains(out, " ERROR boom") {
+		t.Errorf("Unexpected console output: %q", out)
+	}
+
+	if _, err := New(WithLevelNames(map[LogLevel]string{WARN: ""})); err == nil {
+		t.Error("Expected an error for an empty level name")
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	decodeAll := func(t *testing.T, out []byte) []map[string]any {
+		t.Helper()
+		var entries []map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(out))
+		for decoder.More() {
+			var entry map[string]any
+			if err := decoder.Decode(&entry); err != nil {
+				t.Fatalf("Invalid JSON %q: %v", out, err)
+			}
+			delete(entry, "timestamp")
+			delete(entry, "@timestamp")
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	clock := WithClock(func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) })
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"json", nil},
+		{"streaming", []Option{WithStreamingEncoder(), clock}},
+		{"default fields", []Option{WithStreamingEncoder(), WithDefaultFields(map[string]any{"host": "web-1", "zone": "a"})}},
+		{"ecs", []Option{WithFormat(FormatECS)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var compact, pretty bytes.Buffer
+			for _, pair := range []struct {
+				buf  *bytes.Buffer
+				opts []Option
+			}{{&compact, nil}, {&pretty, []Option{WithPrettyJSON(true)}}} {
+				testLogger, err := New(append(append([]Option{WithOutput(pair.buf), WithComponent("api")}, tc.opts...), pair.opts...)...)
+				if err != nil {
+					t.Fatalf("Unexpected error creating logger: %v", err)
+				}
+				testLogger.Info("started")
+				testLogger.InfoStructured("order", ZString("status", "paid"), ZInt("attempt", 2), ZString("password", "hunter2"))
+				testLogger.Info("login", "user", map[string]any{"role": "admin", "token": "abc"}, "password", "hunter2", "b", 1, "a", 2)
+			}
+
+			if lines := strings.Count(compact.String(), "\n"); lines != 3 {
+				t.Errorf("Expected 3 compact lines, got %d: %s", lines, compact.String())
+			}
+			if !strings.Contains(pretty.String(), "{\n  \"") || strings.Count(pretty.String(), "\n") <= 3 {
+				t.Errorf("Expected indented output, got %s", pretty.String())
+			}
+			if got, want := decodeAll(t, pretty.Bytes()), decodeAll(t, compact.Bytes()); !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected the same entries in both modes\npretty:  %v\ncompact: %v", got, want)
+			}
+			if strings.Contains(pretty.String(), "hunter2") || strings.Contains(pretty.String(), `"abc"`) {
+				t.Errorf("Expected masking in pretty mode, got %s", pretty.String())
+			}
+		})
+	}
+
+	// Keys are sorted, so identical entries encode identically
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf), WithStreamingEncoder(), clock, WithPrettyJSON(true))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	fields := map[string]any{"zeta": 1, "alpha": 2, "mid": map[string]any{"y": 1, "x": 2}}
+	testLogger.Info("same", fields)
+	first := buf.String()
+	for range 5 {
+		buf.Reset()
+		testLogger.Info("same", fields)
+		if buf.String() != first {
+			t.Fatalf("Expected deterministic pretty output, got %s and %s", first, buf.String())
+		}
+	}
+	if strings.Index(first, `"alpha"`) > strings.Index(first, `"zeta"`) {
+		t.Errorf("Expected sorted keys, got %s", first)
+	}
+
+	sink := NewMemorySink()
+	sinkLogger, _ := New(WithOutput(sink), WithPrettyJSON(true))
+	sinkLogger.Info("decoded", "order_id", "A-1")
+	if entry, _ := sink.LastEntry(); entry.Message != "decoded" || entry.Fields["order_id"] != "A-1" {
+		t.Errorf("Expected the memory sink to decode pretty entries, got %+v", entry)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	clock := WithClock(func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) })
+
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf), WithSortedKeys(), WithStreamingEncoder(), clock,
+		WithComponent("api"), WithDefaultFields(map[string]any{"host": "web-1"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("sorted", "zeta", 1, "alpha", map[string]any{"y": 2, "x": []any{map[string]any{"b": 1, "a": 2}}}, "mid", true)
+
+	want := `{"timestamp":"2025-01-02T03:04:05.000Z","level":"info","message":"sorted","component":"api",` +
+		`"fields":{"alpha":{"x":[{"a":2,"b":1}],"y":2},"host":"web-1","mid":true,"zeta":1}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected sorted output:\n got %s\nwant %s", buf.String(), want)
+	}
+
+	// Structured fields are sorted and follow the reserved keys
+	buf.Reset()
+	structured, err := New(WithOutput(&buf), WithSortedKeys(), WithComponent("api"), WithVersion("1.2"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	structured.InfoStructured("order", ZString("status", "paid"), ZInt("attempt", 2), ZBool("retry", false))
+	line := buf.String()
+	order := []string{`"message"`, `"component"`, `"version"`, `"attempt"`, `"retry"`, `"status"`}
+	for i := 1; i < len(order); i++ {
+		if strings.Index(line, order[i-1]) > strings.Index(line, order[i]) {
+			t.Errorf("Expected %s before %s, got %s", order[i-1], order[i], line)
+		}
+	}
+	if !json.Valid([]byte(line)) {
+		t.Errorf("Invalid JSON: %s", line)
+	}
+
+	buf.Reset()
+	plain, _ := New(WithOutput(&buf), WithFormat(FormatPlain), WithSortedKeys())
+	plain.Info("plain", "b", 1, "c", 2, "a", 3)
+	if !strings.Contains(buf.String(), "[a=3 b=1 c=2]") {
+		t.Errorf("Expected sorted plain fields, got %q", buf.String())
+	}
+}
+
+func TestFatal(t *testing.T) {
+	var buf bytes.Buffer
+	writer := bufio.NewWriter(&buf)
+	var events []string
+	testLogger, err := New(
+		WithOutput(writer),
+		WithLevel(INFO),
+		WithFatalHook(func() { events = append(events, "hook 1") }),
+		WithFatalHook(func() { events = append(events, "hook 2") }),
+		WithExitFunc(func(code int) {
+			events = append(events, fmt.Sprintf("exit %d flushed=%t", code, strings.Contains(buf.String(), `"database unreachable"`)))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Fatal("database unreachable", "attempts", 3)
+
+	want := []string{"hook 1", "hook 2", "exit 1 flushed=true"}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("Expected log, hooks, flush, then exit, got %q", events)
+	}
+	var entry struct {
+		Level  string         `json:"level"`
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON entry, got %q: %v", buf.String(), err)
+	}
+	if entry.Level != "fatal" || entry.Fields["attempts"] != float64(3) {
+		t.Errorf("Expected a fatal entry with its fields, got %v", entry)
+	}
+
+	// The exit happens even when FATAL is filtered out
+	events = nil
+	quiet, err := New(WithOutput(io.Discard), WithLevel(FATAL+1), WithExitFunc(func(code int) {
+		events = append(events, fmt.Sprintf("exit %d", code))
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	quiet.Fatalf("config %s missing", "app.yaml")
+	if !reflect.DeepEqual(events, []string{"exit 1"}) {
+		t.Errorf("Expected a filtered Fatalf to exit, got %q", events)
+	}
+
+	if level, err := ParseLevel("FATAL"); err != nil || level != FATAL {
+		t.Errorf("Expected ParseLevel to recognize fatal, got %v, %v", level, err)
+	}
+	if _, err := New(WithExitFunc(nil)); err == nil {
+		t.Error("Expected a nil exit function to be rejected")
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var buf bytes.Buffer
+	writer := bufio.NewWriter(&buf)
+	testLogger, err := New(WithOutput(writer), WithLevel(INFO))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	var flushed bool
+	recovered := func() (value any) {
+		defer func() {
+			flushed = strings.Contains(buf.String(), `"ledger out of balance"`)
+			value = recover()
+		}()
+		testLogger.Panic("ledger out of balance", "delta", 12)
+		return nil
+	}()
+	if recovered != "ledger out of balance" || !flushed {
+		t.Fatalf("Expected a flushed entry, then a panic with the message, got %v (flushed %t)", recovered, flushed)
+	}
+	if !strings.Contains(buf.String(), `"level":"panic"`) {
+		t.Errorf("Expected a panic level entry, got %s", buf.String())
+	}
+
+	errInvariant := errors.New("invariant violated")
+	sink := NewMemorySink()
+	custom, err := New(WithOutput(sink), WithPanicValue(func(msg string) any {
+		return fmt.Errorf("%w: %s", errInvariant, msg)
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	recovered = func() (value any) {
+		defer func() { value = recover() }()
+		defer LogPanic(custom)
+		custom.Panicf("order %s has no lines", "A-1")
+		return nil
+	}()
+	if err, ok := recovered.(error); !ok || !errors.Is(err, errInvariant) {
+		t.Errorf("Expected the configured panic value, got %v", recovered)
+	}
+	if entries := sink.Entries(); len(entries) != 1 || entries[0].Level != PANIC || entries[0].Message != "order A-1 has no lines" {
+		t.Errorf("Expected LogPanic not to log the entry again, got %+v", entries)
+	}
+}
+
+func TestOnlyAtLevel(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithLevel(DEBUG))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	calls := 0
+	requestLogger := testLogger.WithFields(map[string]any{
+		"request_id": "r-1",
+		"headers":    OnlyAtLevel(DEBUG, "headers", map[string]any{"accept": "json"}),
+	})
+	snapshot := OnlyAtLevel(WARN, "snapshot", func() any { calls++; return "state" })
+
+	requestLogger.Debug("routing")
+	requestLogger.Info("completed", snapshot)
+	requestLogger.ErrorStructured("failed", ZString("reason", "timeout"), snapshot)
+
+	entries := sink.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if _, ok := entries[0].Fields["headers"].(map[string]any); !ok {
+		t.Errorf("Expected headers on the debug entry, got %v", entries[0].Fields)
+	}
+	if _, ok := entries[1].Fields["headers"]; ok || entries[1].Fields["snapshot"] != "state" {
+		t.Errorf("Expected only the snapshot on the info entry, got %v", entries[1].Fields)
+	}
+	if _, ok := entries[2].Fields["snapshot"]; ok || entries[2].Fields["reason"] != "timeout" || entries[2].Fields["request_id"] != "r-1" {
+		t.Errorf("Expected the snapshot to be dropped above WARN, got %v", entries[2].Fields)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the lazy value to be evaluated only when kept, got %d calls", calls)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	type Office struct {
+		Floor string
+		Zip   string
+	}
+	type Account struct {
+		Name   string `json:"name"`
+		Role   string `json:"role"`
+		Badge  string `log:"mask"`
+		Office Office
+		Tags   []string
+	}
+	before := Account{Name: "Ada", Role: "viewer", Badge: "1111", Office: Office{Floor: "2", Zip: "N1"}, Tags: []string{"a"}}
+	after := &Account{Name: "Ada", Role: "admin", Badge: "2222", Office: Office{Floor: "5", Zip: "N1"}, Tags: []string{"a"}}
+
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("account updated", Diff(before, after))
+	testLogger.Info("settings updated", Diff(
+		map[string]any{"password": "old-secret", "theme": "dark", "beta": true},
+		map[string]any{"password": "new-secret", "theme": "dark", "locale": "fr"},
+	))
+	testLogger.Info("nothing changed", Diff(before, before))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(lines))
+	}
+	var entries [3]struct {
+		Fields struct {
+			Changes map[string]any `json:"changes"`
+		} `json:"fields"`
+	}
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("Unexpected invalid JSON %q: %v", line, err)
+		}
+	}
+
+	want := map[string]any{
+		"role":   map[string]any{"old": "viewer", "new": "admin"},
+		"Badge":  map[string]any{"old": defaultMaskString, "new": defaultMaskString},
+		"Office": map[string]any{"Floor": map[string]any{"old": "2", "new": "5"}},
+	}
+	if got := entries[0].Fields.Changes; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected only changed struct fields, got %v", got)
+	}
+	want = map[string]any{
+		"password": defaultMaskString,
+		"beta":     map[string]any{"old": true},
+		"locale":   map[string]any{"new": "fr"},
+	}
+	if got := entries[1].Fields.Changes; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected masked, removed and added keys, got %v", got)
+	}
+	if got := entries[2].Fields.Changes; got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty change set for equal values, got %v", got)
+	}
+	if strings.Contains(buf.String(), "secret") || strings.Contains(buf.String(), "1111") {
+		t.Errorf("Expected no sensitive values in the output, got %s", buf.String())
+	}
+}
+
+// recordingEncoder keeps a copy of each entry and writes its message
+type recordingEncoder struct {
+	entries []Entry
+}
+
+func (r *recordingEncoder) Encode(w io.Writer, e *Entry) error {
+	if e.Message == "unencodable" {
+		return errors.New("cannot encode")
+	}
+	entry := *e
+	entry.Fields = maps.Clone(e.Fields)
+	r.entries = append(r.entries, entry)
+	_, err := fmt.Fprintf(w, "%s|%s\n", e.Level, e.Message)
+	return err
+}
+
+func TestWithEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	var reported []error
+	encoder := &recordingEncoder{}
+	testLogger, err := New(
+		WithOutput(&buf),
+		WithComponent("billing"),
+		WithEncoder(encoder),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("charged", "amount", 10, "password", "hunter2")
+	testLogger.WarnStructured("retry", ZString("card_number", "4111111111111111"))
+	testLogger.Info("unencodable")
+
+	if got := buf.String(); got != "info|charged\nwarn|retry\n" {
+		t.Errorf("Expected the encoder's output only, got %q", got)
+	}
+	if len(reported) != 1 {
+		t.Errorf("Expected the encoding failure to be reported, got %v", reported)
+	}
+	if len(encoder.entries) != 2 {
+		t.Fatalf("Expected 2 encoded entries, got %d", len(encoder.entries))
+	}
+	first := encoder.entries[0]
+	if first.Fields["password"] != defaultMaskString || first.Fields["amount"] != 10 || first.Component != "billing" || first.Time.IsZero() {
+		t.Errorf("Expected a masked entry with its metadata, got %+v", first)
+	}
+	if encoder.entries[1].Fields["card_number"] != defaultPIIMaskString {
+		t.Errorf("Expected structured fields to be masked before encoding, got %+v", encoder.entries[1])
+	}
+
+	// The bundled encoders write the default layouts
+	buf.Reset()
+	jsonLogger, err := New(WithOutput(&buf), WithVersion("1.2.0"), WithEncoder(JSONEncoder{}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	jsonLogger.Error("declined", "reason", "<limit>")
+	entry, err := ParseEntry(buf.Bytes())
+	if err != nil || entry.Level != ERROR || entry.Version != "1.2.0" || entry.Fields["reason"] != "<limit>" || entry.Time.IsZero() {
+		t.Errorf("Expected JSONEncoder output to parse back, got %+v, %v from %s", entry, err, buf.String())
+	}
+
+	buf.Reset()
+	logfmtLogger, err := New(WithOutput(&buf), WithEncoder(LogfmtEncoder{}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	logfmtLogger.Info("user seen", "user", map[string]any{"plan": "pro"}, "token", "abc")
+	if got := buf.String(); !strings.Contains(got, ` level=info msg="user seen" token=***MASKED*** user.plan=pro`+"\n") {
+		t.Errorf("Expected a logfmt line, got %q", got)
+	}
+}
+
+func TestWithSequence(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink), WithSequence())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	child := testLogger.WithFields(map[string]any{"worker": true})
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 25 {
+				testLogger.Debug("filtered")
+				if i%2 == 0 {
+					testLogger.Warn("tick", "seq", "ignored")
+				} else {
+					child.WarnStructured("tick")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[float64]bool)
+	for _, entry := range sink.Entries() {
+		seq, ok := entry.Fields["seq"].(float64)
+		if !ok || seen[seq] {
+			t.Fatalf("Expected a distinct sequence number, got %v", entry.Fields["seq"])
+		}
+		seen[seq] = true
+	}
+	for seq := 1; seq <= 100; seq++ {
+		if !seen[float64(seq)] {
+			t.Fatalf("Expected numbers 1 to 100 without gaps, missing %d of %d", seq, len(seen))
+		}
+	}
+}
+
+func TestWithGroup(t *testing.T) {
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(sink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	db := testLogger.WithFields(map[string]any{"service": "api"}).WithGroup("db")
+	pool := db.WithFields(map[string]any{"table": "users"}).WithGroup("pool")
+
+	ctx := WithContextFields(WithRequestID(context.Background(), "req-1"), map[string]any{"tenant": "acme"})
+	db.InfoContext(ctx, "query", "password", "hunter2", "rows", OnlyAtLevel(INFO, "rows", 3))
+	pool.Info("acquired", "size", 4)
+	db.WithGroup("").InfoStructured("ping", Int("ms", 2))
+
+	entries := sink.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	first := entries[0].Fields
+	group, ok := first["db"].(map[string]any)
+	if !ok || first["service"] != "api" || first["request_id"] != "req-1" || group["tenant"] != "acme" {
+		t.Fatalf("Expected earlier and correlation fields at the top level and the rest grouped, got %v", first)
+	}
+	if group["password"] != defaultMaskString || group["rows"] != float64(3) {
+		t.Fatalf("Expected grouped fields masked and resolved, got %v", group)
+	}
+
+	group, _ = entries[1].Fields["db"].(map[string]any)
+	nested, ok := group["pool"].(map[string]any)
+	if !ok || group["table"] != "users" || nested["size"] != float64(4) {
+		t.Fatalf("Expected fields nested under db.pool, got %v", entries[1].Fields)
+	}
+
+	group, _ = entries[2].Fields["db"].(map[string]any)
+	if group["ms"] != float64(2) {
+		t.Fatalf("Expected structured fields grouped, got %v", entries[2].Fields)
+	}
+
+	// Groups survive a reconfiguration
+	if err := testLogger.Reconfigure(WithOutput(sink), WithLevel(DEBUG)); err != nil {
+		t.Fatalf("Unexpected error reconfiguring: %v", err)
+	}
+	db.Debug("reconfigured", "rows", 1)
+	entry, _ := sink.LastEntry()
+	if group, _ := entry.Fields["db"].(map[string]any); group["rows"] != float64(1) {
+		t.Errorf("Expected fields grouped after Reconfigure, got %v", entry.Fields)
+	}
+}
@@ -0,0 +1,167 @@
+package emit
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens when the async queue is full
+type OverflowPolicy int
+
+const (
+	DropNewest OverflowPolicy = iota // Discard the entry being logged
+	DropOldest                       // Discard the oldest queued entry to make room
+	Block                            // Wait for the worker to make room
+)
+
+// AsyncStats is a snapshot of the async queue
+type AsyncStats struct {
+	Queued   int    // Entries waiting to be written
+	Capacity int    // Queue size configured with WithAsync
+	Dropped  uint64 // Entries discarded by the overflow policy
+}
+
+// asyncItem is a queued line, or a sync marker when done is set. logger
+// receives write errors.
+type asyncItem struct {
+	logger *Logger
+	writer io.Writer
+	level  LogLevel
+	line   []byte
+	done   chan struct{}
+}
+
+// asyncWriter queues encoded lines for a background worker
+type asyncWriter struct {
+	mu      sync.RWMutex
+	closed  bool
+	policy  OverflowPolicy
+	queue   chan asyncItem
+	dropped atomic.Uint64
+	stopped chan struct{}
+}
+
+// WithAsync moves writes off the calling goroutine. Entries are encoded and
+// masked synchronously, so field maps can be reused as soon as the logging
+// call returns; a background worker writes the queued lines in order. When
+// the queue holds bufferSize entries, policy decides which entry is dropped
+// or whether the caller blocks. Close drains the queue before returning.
+func WithAsync(bufferSize int, policy OverflowPolicy) Option {
+	return func(l *Logger) error {
+		if bufferSize <= 0 {
+			return errors.New("emit: async buffer size must be positive")
+		}
+		if policy < DropNewest || policy > Block {
+			return errors.New("emit: unknown async overflow policy")
+		}
+		// The worker starts, and a replaced one stops, once every option applied
+		l.async = &asyncWriter{
+			policy:  policy,
+			queue:   make(chan asyncItem, bufferSize),
+			stopped: make(chan struct{}),
+		}
+		return nil
+	}
+}
+
+// enqueue queues a line that the caller no longer references
+func (a *asyncWriter) enqueue(l *Logger, w io.Writer, level LogLevel, line []byte) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		l.writeOutput(w, level, line)
+		return
+	}
+
+	item := asyncItem{logger: l, writer: w, level: level, line: line}
+	switch a.policy {
+	case Block:
+		a.queue <- item
+	case DropOldest:
+		for {
+			select {
+			case a.queue <- item:
+				return
+			default:
+			}
+			select {
+			case old := <-a.queue:
+				if old.done != nil {
+					// Never drop a sync marker, release its waiter instead
+					close(old.done)
+				} else {
+					a.dropped.Add(1)
+					l.reportError(ErrAsyncOverflow)
+				}
+			default:
+			}
+		}
+	default:
+		select {
+		case a.queue <- item:
+		default:
+			a.dropped.Add(1)
+			l.reportError(ErrAsyncOverflow)
+		}
+	}
+}
+
+// run writes queued lines until the queue is closed
+func (a *asyncWriter) run() {
+	defer close(a.stopped)
+	for item := range a.queue {
+		if item.done != nil {
+			close(item.done)
+			continue
+		}
+		item.logger.writeOutput(item.writer, item.level, item.line)
+	}
+}
+
+// flush waits until every line queued before the call has been written
+func (a *asyncWriter) flush() {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return
+	}
+	done := make(chan struct{})
+	a.queue <- asyncItem{done: done}
+	a.mu.RUnlock()
+	<-done
+}
+
+// close drains the queue and stops the worker
+func (a *asyncWriter) close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+	<-a.stopped
+}
+
+// stats returns a snapshot of the queue
+func (a *asyncWriter) stats() AsyncStats {
+	return AsyncStats{
+		Queued:   len(a.queue),
+		Capacity: cap(a.queue),
+		Dropped:  a.dropped.Load(),
+	}
+}
+
+// AsyncStats reports queue depth and dropped entries for a logger created
+// with WithAsync; it returns the zero value for synchronous loggers
+func (l *Logger) AsyncStats() AsyncStats {
+	l = l.resolve()
+	if l.async == nil {
+		return AsyncStats{}
+	}
+	return l.async.stats()
+}
@@ -0,0 +1,102 @@
+package emit
+
+import "context"
+
+// Batcher buffers entries logged inside Logger.Batch
+type Batcher struct {
+	logger *Logger
+}
+
+// batchBuffer holds the encoded lines of a batch in the order they were logged
+type batchBuffer struct {
+	lines []batchLine
+}
+
+// batchLine is an encoded line and the level route that selected its writer
+type batchLine struct {
+	route int
+	level LogLevel
+	line  []byte
+}
+
+// Batch runs fn and writes the entries it logs together once fn returns,
+// with one Write call per sink, so writers that serialize their Write calls
+// (files, MemorySink, the WithAsync queue) never interleave them with entries
+// from other goroutines:
+//
+//	logger.Batch(func(b *emit.Batcher) {
+//		b.Info("transfer started", "transfer_id", id)
+//		b.Info("funds debited", "account", from, "amount", amount)
+//		b.Info("funds credited", "account", to, "amount", amount)
+//	})
+//
+// Entries keep their order and are filtered, sampled and masked one by one as
+// usual. Entries logged before fn panics are still written. b must only be
+// used by fn's goroutine while fn runs.
+func (l *Logger) Batch(fn func(b *Batcher)) {
+	batched := *l.resolve()
+	batched.batch = &batchBuffer{}
+	batched.resolveCache = nil
+	defer batched.flushBatch()
+	fn(&Batcher{logger: &batched})
+}
+
+// Info buffers an info entry
+func (b *Batcher) Info(message string, fields ...any) {
+	b.logger.logContext(context.Background(), INFO, message, fields...)
+}
+
+// Error buffers an error entry
+func (b *Batcher) Error(message string, fields ...any) {
+	b.logger.logContext(context.Background(), ERROR, message, fields...)
+}
+
+// Warn buffers a warn entry
+func (b *Batcher) Warn(message string, fields ...any) {
+	b.logger.logContext(context.Background(), WARN, message, fields...)
+}
+
+// Debug buffers a debug entry
+func (b *Batcher) Debug(message string, fields ...any) {
+	b.logger.logContext(context.Background(), DEBUG, message, fields...)
+}
+
+// Log buffers an entry at level, including levels added with RegisterLevel
+func (b *Batcher) Log(level LogLevel, message string, fields ...any) {
+	b.logger.logContext(context.Background(), level, message, fields...)
+}
+
+// add keeps a private copy of an encoded line
+func (bb *batchBuffer) add(route int, level LogLevel, line []byte) {
+	bb.lines = append(bb.lines, batchLine{route: route, level: level, line: append([]byte(nil), line...)})
+}
+
+// flushBatch writes the buffered lines, joining consecutive lines for the
+// same route into one write. Level-aware writers such as MultiSink filter
+// each line by level, so they only join lines of the same level.
+func (l *Logger) flushBatch() {
+	lines := l.batch.lines
+	l.batch = nil
+
+	for start := 0; start < len(lines); {
+		first := lines[start]
+		w := l.output(first.level)
+		_, perLevel := w.(levelWriter)
+		joined := first.line
+		end := start + 1
+		for ; end < len(lines); end++ {
+			next := lines[end]
+			if next.route != first.route || (perLevel && next.level != first.level) {
+				break
+			}
+			joined = append(joined, next.line...)
+		}
+		start = end
+
+		if l.async != nil {
+			l.async.enqueue(l, w, first.level, joined)
+			continue
+		}
+		l.writeOutput(w, first.level, joined)
+	}
+}
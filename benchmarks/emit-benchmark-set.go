@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io"
 	"testing"
 	"time"
 
@@ -24,6 +25,10 @@ func (e EmitBenchmarkSet) GetBenchmarks() []BenchmarkFunc {
 		// Emit-specific field benchmarks
 		{"Emit_Field", e.BenchmarkField},
 		{"Emit_FieldComplex", e.BenchmarkFieldComplex},
+		{"Emit_FieldStreaming", e.BenchmarkFieldStreaming},
+		{"Emit_FieldComplexStreaming", e.BenchmarkFieldComplexStreaming},
+		{"Emit_FieldEncoder", e.BenchmarkFieldEncoder},
+		{"Emit_FieldParallel", e.BenchmarkFieldParallel},
 
 		// Key-value benchmarks
 		{"Emit_KeyValue", e.BenchmarkKeyValue},
@@ -36,6 +41,8 @@ func (e EmitBenchmarkSet) GetBenchmarks() []BenchmarkFunc {
 		// Security benchmarks
 		{"Emit_SecurityBuiltIn", e.BenchmarkSecurityBuiltIn},
 		{"Emit_SecurityDisabled", e.BenchmarkSecurityDisabled},
+		{"Emit_MapMasked", e.BenchmarkMapMasked},
+		{"Emit_MapUnmasked", e.BenchmarkMapUnmasked},
 	}
 }
 
@@ -118,6 +125,69 @@ func (e EmitBenchmarkSet) BenchmarkFieldComplex(b *testing.B) {
 	}
 }
 
+// streamingLogger writes through the streaming JSON encoder for comparison with the map-based path
+var streamingLogger, _ = emit.New(emit.WithOutput(io.Discard), emit.WithStreamingEncoder())
+
+func (e EmitBenchmarkSet) BenchmarkFieldStreaming(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		streamingLogger.Info("User action",
+			emit.NewFields().
+				String("user_id", "12345").
+				String("action", "login").
+				String("ip_address", "192.168.1.100").
+				Bool("success", true))
+	}
+}
+
+func (e EmitBenchmarkSet) BenchmarkFieldComplexStreaming(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		streamingLogger.Info("Complex operation",
+			emit.NewFields().
+				String("service", "user-service").
+				String("operation", "create_user").
+				String("user_id", "12345").
+				String("email", "user@example.com").
+				String("ip_address", "192.168.1.100").
+				Int("status_code", 201).
+				Float64("duration_ms", 15.75).
+				Bool("success", true).
+				Time("timestamp", time.Now()).
+				String("correlation_id", "corr_abc123"))
+	}
+}
+
+// encoderLogger writes through the Encoder interface for comparison with the built-in JSON format
+var encoderLogger, _ = emit.New(emit.WithOutput(io.Discard), emit.WithEncoder(emit.JSONEncoder{}))
+
+func (e EmitBenchmarkSet) BenchmarkFieldEncoder(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		encoderLogger.Info("User action",
+			emit.NewFields().
+				String("user_id", "12345").
+				String("action", "login").
+				String("ip_address", "192.168.1.100").
+				Bool("success", true))
+	}
+}
+
+// BenchmarkFieldParallel logs from all procs at once to exercise the pooled line buffers
+func (e EmitBenchmarkSet) BenchmarkFieldParallel(b *testing.B) {
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			emit.Info.Field("User action",
+				emit.NewFields().
+					String("user_id", "12345").
+					String("action", "login").
+					String("ip_address", "192.168.1.100").
+					Bool("success", true))
+		}
+	})
+}
+
 // Key-value benchmarks
 func (e EmitBenchmarkSet) BenchmarkKeyValue(b *testing.B) {
 	b.ResetTimer()
@@ -206,3 +276,34 @@ func (e EmitBenchmarkSet) BenchmarkSecurityDisabled(b *testing.B) {
 		)
 	}
 }
+
+// securityFields mixes sensitive, PII and plain keys
+var securityFields = emit.Fields{
+	"password":   "super_secret_123",
+	"email":      "user@example.com",
+	"api_key":    "sk_live_abc123",
+	"user_id":    "12345",
+	"action":     "register",
+	"ip_address": "192.168.1.100",
+}
+
+// Masked vs unmasked map throughput; with masking disabled the field map is
+// passed straight to the encoder, so this pair guards that fast lane
+func (e EmitBenchmarkSet) BenchmarkMapMasked(b *testing.B) {
+	b.ResetTimer()
+	for b.Loop() {
+		emit.Info.Field("User registration", securityFields)
+	}
+}
+
+func (e EmitBenchmarkSet) BenchmarkMapUnmasked(b *testing.B) {
+	emit.ShowSensitiveData()
+	emit.ShowPIIData()
+	defer emit.MaskSensitiveData()
+	defer emit.MaskPIIData()
+
+	b.ResetTimer()
+	for b.Loop() {
+		emit.Info.Field("User registration", securityFields)
+	}
+}
@@ -0,0 +1,180 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// bytesPrefixLen is the number of bytes BytesTruncate keeps
+const bytesPrefixLen = 16
+
+// ByteSliceMode selects how []byte field values are written
+type ByteSliceMode int
+
+const (
+	BytesBase64   ByteSliceMode = iota + 1 // The whole slice in base64, as encoding/json writes it
+	BytesLength                            // Only the size, such as "<512 bytes>"
+	BytesMask                              // The sensitive mask string
+	BytesTruncate                          // Base64 of the first 16 bytes, then the size
+)
+
+// ByteSliceOption customizes handling configured with WithByteSliceHandling
+type ByteSliceOption func(*byteSliceHandling)
+
+// byteSliceHandling holds the mode and whether JSON payloads are decoded
+type byteSliceHandling struct {
+	mode       ByteSliceMode
+	decodeJSON bool
+}
+
+// WithByteSliceHandling sets how []byte values are written whatever their
+// key, so a raw request body or key material logged by mistake does not
+// dump into the logs:
+//
+//	emit.WithByteSliceHandling(emit.BytesLength, emit.BytesAsJSON())
+//	logger.Info("webhook", "body", body) // "body":{"event":"paid","token":"***MASKED***"}
+//	logger.Info("upload", "chunk", chunk) // "chunk":"<4096 bytes>"
+//
+// Without it, []byte values are written in full as base64. Values nested in
+// field maps and slices are handled too; named byte types such as
+// json.RawMessage are not.
+func WithByteSliceHandling(mode ByteSliceMode, opts ...ByteSliceOption) Option {
+	return func(l *Logger) error {
+		if mode < BytesBase64 || mode > BytesTruncate {
+			return errors.New("emit: unknown byte slice mode")
+		}
+		handling := &byteSliceHandling{mode: mode}
+		for _, opt := range opts {
+			opt(handling)
+		}
+		l.byteSlices = handling
+		return nil
+	}
+}
+
+// BytesAsJSON writes []byte values that hold a JSON object or array as that
+// JSON, masked like any other nested fields, instead of applying the mode
+func BytesAsJSON() ByteSliceOption {
+	return func(h *byteSliceHandling) {
+		h.decodeJSON = true
+	}
+}
+
+// handleByteSlices rewrites []byte values in fields, copying maps and slices
+// only when one is found
+func (l *Logger) handleByteSlices(fields map[string]any) map[string]any {
+	if l.byteSlices == nil {
+		return fields
+	}
+	handled, _ := l.handleByteSliceMap(fields, nil)
+	return handled
+}
+
+// handleByteSliceMap rewrites the []byte values in one map level. visited
+// holds the containers above it, as in maskNestedValue.
+func (l *Logger) handleByteSliceMap(fields map[string]any, visited map[maskVisitKey]bool) (map[string]any, bool) {
+	var result map[string]any
+	for key, value := range fields {
+		handled, changed := l.handleByteSliceValue(value, visited)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = maps.Clone(fields)
+		}
+		result[key] = handled
+	}
+	if result == nil {
+		return fields, false
+	}
+	return result, true
+}
+
+// handleByteSliceValue rewrites value if it is a []byte or contains one
+func (l *Logger) handleByteSliceValue(value any, visited map[maskVisitKey]bool) (any, bool) {
+	var visitKey maskVisitKey
+	switch v := value.(type) {
+	case []byte:
+		return l.byteSliceValue(v), true
+	case map[string]any:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer()}
+	case Fields:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer()}
+	case []any:
+		if len(v) == 0 {
+			return value, false
+		}
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer(), length: len(v)}
+	default:
+		return value, false
+	}
+
+	// Cycles and containers beyond the depth limit are left to masking
+	if visited[visitKey] || len(visited) >= l.nestingLimit() {
+		return value, false
+	}
+	if visited == nil {
+		visited = make(map[maskVisitKey]bool, 4)
+	}
+	visited[visitKey] = true
+	defer delete(visited, visitKey)
+
+	switch v := value.(type) {
+	case map[string]any:
+		return l.handleByteSliceMap(v, visited)
+	case Fields:
+		handled, changed := l.handleByteSliceMap(v, visited)
+		return Fields(handled), changed
+	default:
+		elements := value.([]any)
+		var result []any
+		for i, element := range elements {
+			handled, changed := l.handleByteSliceValue(element, visited)
+			if !changed {
+				continue
+			}
+			if result == nil {
+				result = slices.Clone(elements)
+			}
+			result[i] = handled
+		}
+		if result == nil {
+			return value, false
+		}
+		return result, true
+	}
+}
+
+// byteSliceValue returns what is written in place of b
+func (l *Logger) byteSliceValue(b []byte) any {
+	if l.byteSlices.decodeJSON {
+		if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+			var decoded any
+			decoder := json.NewDecoder(bytes.NewReader(trimmed))
+			decoder.UseNumber()
+			if decoder.Decode(&decoded) == nil && !decoder.More() {
+				return decoded
+			}
+		}
+	}
+
+	size := "<" + strconv.Itoa(len(b)) + " bytes>"
+	switch l.byteSlices.mode {
+	case BytesLength:
+		return size
+	case BytesMask:
+		return l.maskString
+	case BytesTruncate:
+		if len(b) <= bytesPrefixLen {
+			return base64.StdEncoding.EncodeToString(b)
+		}
+		return base64.StdEncoding.EncodeToString(b[:bytesPrefixLen]) + "..." + size
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,169 @@
+package emit
+
+import (
+	"errors"
+	"maps"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Field keys written by WithCaller
+const (
+	callerKey         = "caller"
+	callerFunctionKey = "caller_function"
+)
+
+// CallerOption configures caller annotation
+type CallerOption func(*callerConfig)
+
+// callerConfig controls which entries get a caller field
+type callerConfig struct {
+	minLevel LogLevel
+	function bool
+}
+
+// WithCaller adds a "caller" field such as "auth/login.go:42" naming the
+// line that made the logging call. Frames inside this package are skipped,
+// so the field is correct for every logging method; use WithCallerSkip when
+// calls go through your own wrapper.
+func WithCaller(opts ...CallerOption) Option {
+	return func(l *Logger) error {
+		c := &callerConfig{minLevel: DEBUG}
+		for _, opt := range opts {
+			opt(c)
+		}
+		l.caller = c
+		return nil
+	}
+}
+
+// CallerLevel limits caller lookup to entries at level or above, e.g.
+// CallerLevel(emit.ERROR) keeps the cost off Info and Debug
+func CallerLevel(level LogLevel) CallerOption {
+	return func(c *callerConfig) {
+		c.minLevel = level
+	}
+}
+
+// CallerFunction also writes the fully qualified function name as
+// "caller_function"
+func CallerFunction() CallerOption {
+	return func(c *callerConfig) {
+		c.function = true
+	}
+}
+
+// WithCallerSkip skips n more frames when resolving the caller, for code that
+// wraps the logger in its own helpers. It applies to WithCaller and to the
+// file/line written by SetShowCaller.
+func WithCallerSkip(n int) Option {
+	return func(l *Logger) error {
+		if n < 0 {
+			return errors.New("emit: caller skip must not be negative")
+		}
+		l.extraCallerSkip = n
+		return nil
+	}
+}
+
+// AddCallerSkip returns a child logger that skips n more frames than l when
+// resolving the caller, for adapters that log on behalf of their own callers.
+// It returns l itself when n is not positive or l writes no caller.
+func (l *Logger) AddCallerSkip(n int) *Logger {
+	if n <= 0 || (l.tee == nil && l.caller == nil && !l.showCaller && !l.reconfigured()) {
+		return l
+	}
+	if l.tee != nil {
+		return l.teeMap(func(child *Logger) *Logger { return child.AddCallerSkip(n) })
+	}
+	child := l.derive()
+	child.extraCallerSkip += n
+	return child
+}
+
+// addCaller adds the caller field when the level requires it
+func (l *Logger) addCaller(level LogLevel, fields map[string]any) map[string]any {
+	if l.caller == nil || level < l.caller.minLevel {
+		return fields
+	}
+
+	var frame runtime.Frame
+	if l.callerPC != 0 {
+		frame, _ = runtime.CallersFrames([]uintptr{l.callerPC}).Next()
+	} else {
+		// callerFrame skips package frames itself, including the tee's
+		skip := l.extraCallerSkip
+		if l.teeChild {
+			skip -= teeCallerSkip
+		}
+		var ok bool
+		if frame, ok = callerFrame(skip); !ok {
+			return fields
+		}
+	}
+
+	withCaller := make(map[string]any, len(fields)+2)
+	maps.Copy(withCaller, fields)
+	withCaller[callerKey] = shortCallerPath(frame.File) + ":" + strconv.Itoa(frame.Line)
+	if l.caller.function {
+		withCaller[callerFunctionKey] = frame.Function
+	}
+	return withCaller
+}
+
+// withCallerPC returns a copy of l that reports the frame of pc as the
+// caller, for records such as slog's that carry their own program counter
+func (l *Logger) withCallerPC(pc uintptr) *Logger {
+	if l.tee != nil {
+		return l.teeMap(func(child *Logger) *Logger { return child.withCallerPC(pc) })
+	}
+	if l.caller == nil && !l.reconfigured() {
+		return l
+	}
+	// A one-off copy for a single entry, so it does not cache its resolve
+	child := *l
+	child.callerPC = pc
+	child.resolveCache = nil
+	return &child
+}
+
+// callerFrame returns the first frame outside this package, skipping skip
+// more frames after it
+func callerFrame(skip int) (runtime.Frame, bool) {
+	pcs := make([]uintptr, 32+skip)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	inside := true
+	for {
+		frame, more := frames.Next()
+		if inside && strings.HasPrefix(frame.Function, emitPackagePrefix) {
+			if !more {
+				return runtime.Frame{}, false
+			}
+			continue
+		}
+		inside = false
+
+		if skip == 0 {
+			return frame, true
+		}
+		skip--
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// shortCallerPath trims a file path to its last directory and file name
+func shortCallerPath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
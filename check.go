@@ -0,0 +1,71 @@
+package emit
+
+import "context"
+
+// CheckedEntry is a pending entry at a level the logger accepts, returned by
+// Logger.Check
+type CheckedEntry struct {
+	logger *Logger
+	level  LogLevel
+}
+
+// Check returns a CheckedEntry when level is enabled and nil otherwise, so
+// expensive fields are only built for entries that will be written:
+//
+//	if ce := logger.Check(emit.DEBUG); ce != nil {
+//		ce.Write("cache state", "entries", cache.Snapshot())
+//	}
+//
+// Sampling, deduplication and masking still apply when the entry is written.
+func (l *Logger) Check(level LogLevel) *CheckedEntry {
+	if !l.Enabled(level) {
+		return nil
+	}
+	return &CheckedEntry{logger: l, level: level}
+}
+
+// Write logs the entry with the given message and fields, which take the
+// same forms as Logger.Info. Write on a nil CheckedEntry does nothing.
+func (ce *CheckedEntry) Write(message string, fields ...any) {
+	if ce == nil {
+		return
+	}
+	ce.logger.logContext(context.Background(), ce.level, message, fields...)
+}
+
+// WriteContext logs the entry like Write, adding the fields, trace and
+// request IDs carried by ctx
+func (ce *CheckedEntry) WriteContext(ctx context.Context, message string, fields ...any) {
+	if ce == nil {
+		return
+	}
+	ce.logger.logContext(ctx, ce.level, message, fields...)
+}
+
+// InfoIf logs an info message only when cond is true
+func (l *Logger) InfoIf(cond bool, message string, fields ...any) {
+	if cond {
+		l.logContext(context.Background(), INFO, message, fields...)
+	}
+}
+
+// ErrorIf logs an error message only when cond is true
+func (l *Logger) ErrorIf(cond bool, message string, fields ...any) {
+	if cond {
+		l.logContext(context.Background(), ERROR, message, fields...)
+	}
+}
+
+// WarnIf logs a warn message only when cond is true
+func (l *Logger) WarnIf(cond bool, message string, fields ...any) {
+	if cond {
+		l.logContext(context.Background(), WARN, message, fields...)
+	}
+}
+
+// DebugIf logs a debug message only when cond is true
+func (l *Logger) DebugIf(cond bool, message string, fields ...any) {
+	if cond {
+		l.logContext(context.Background(), DEBUG, message, fields...)
+	}
+}
@@ -3,39 +3,42 @@ package emit
 import (
 	"io"
 	"os"
+	"slices"
 	"strings"
 )
 
+// defaultLoggerFormat maps EMIT_FORMAT for the default logger. Unlike
+// FromEnv it keeps writing plain text for console, development and dev, as
+// it did before the console format existed, and falls back to JSON for
+// unknown names.
+func defaultLoggerFormat(name string) OutputFormat {
+	switch strings.ToLower(name) {
+	case "console", "development", "dev":
+		return PLAIN_FORMAT
+	}
+	if format, ok := parseOutputFormat(name); ok {
+		return format
+	}
+	return JSON_FORMAT
+}
+
 // initFromEnvironment initializes logger settings from environment variables
 func initFromEnvironment() {
+	logger := Default()
 
 	// Check environment variable for format override
 	if logFormat := os.Getenv("EMIT_FORMAT"); logFormat != "" {
-
-		switch strings.ToLower(logFormat) {
-
-		case "plain", "text", "console", "development", "dev":
-			defaultLogger.format = PLAIN_FORMAT
-
-		case "json", "production", "prod":
-			defaultLogger.format = JSON_FORMAT
-
-		default:
-			// Invalid value, stick with JSON default
-			defaultLogger.format = JSON_FORMAT
-
-		}
-
+		logger.format = defaultLoggerFormat(strings.TrimSpace(logFormat))
 	}
 
 	// Also check for log level from environment
 	if logLevel := os.Getenv("EMIT_LEVEL"); logLevel != "" {
-		defaultLogger.level = ParseLogLevel(logLevel)
+		logger.SetLevel(ParseLogLevel(logLevel))
 	}
 
 	// Check for caller information setting
 	if showCaller := os.Getenv("EMIT_SHOW_CALLER"); showCaller != "" {
-		defaultLogger.showCaller = strings.ToLower(showCaller) == "true" || showCaller == "1"
+		logger.showCaller = strings.ToLower(showCaller) == "true" || showCaller == "1"
 	}
 
 	// Check for sensitive data masking setting
@@ -44,13 +47,19 @@ func initFromEnvironment() {
 		switch strings.ToLower(sensitiveMode) {
 
 		case "false", "0", "no", "off", "show":
-			defaultLogger.sensitiveMode = SHOW_SENSITIVE
+			logger.sensitiveMode = SHOW_SENSITIVE
 
 		case "true", "1", "yes", "on", "mask":
-			defaultLogger.sensitiveMode = MASK_SENSITIVE
+			logger.sensitiveMode = MASK_SENSITIVE
+
+		case "hash":
+			logger.sensitiveMode = HASH_SENSITIVE
+
+		case "drop":
+			logger.sensitiveMode = DROP_SENSITIVE
 
 		default:
-			defaultLogger.sensitiveMode = MASK_SENSITIVE // Default to masking
+			logger.sensitiveMode = MASK_SENSITIVE // Default to masking
 
 		}
 
@@ -62,25 +71,33 @@ func initFromEnvironment() {
 		switch strings.ToLower(piiMode) {
 
 		case "false", "0", "no", "off", "show":
-			defaultLogger.piiMode = SHOW_PII
+			logger.piiMode = SHOW_PII
 
 		case "true", "1", "yes", "on", "mask":
-			defaultLogger.piiMode = MASK_PII
+			logger.piiMode = MASK_PII
+
+		case "drop":
+			logger.piiMode = DROP_PII
 
 		default:
-			defaultLogger.piiMode = MASK_PII // Default to masking
+			logger.piiMode = MASK_PII // Default to masking
 		}
 
 	}
 
 	// Allow custom mask string
 	if maskString := os.Getenv("EMIT_MASK_STRING"); maskString != "" {
-		defaultLogger.maskString = maskString
+		logger.maskString = maskString
+	}
+
+	// Allow custom salt for hashed sensitive values
+	if hashSalt := os.Getenv("EMIT_HASH_SALT"); hashSalt != "" {
+		logger.SetHashSalt([]byte(hashSalt))
 	}
 
 	// Allow custom PII mask string
 	if piiMaskString := os.Getenv("EMIT_PII_MASK_STRING"); piiMaskString != "" {
-		defaultLogger.piiMaskString = piiMaskString
+		logger.piiMaskString = piiMaskString
 	}
 
 	// PHASE 3: Check for timestamp precision setting
@@ -91,47 +108,60 @@ func initFromEnvironment() {
 
 // SetComponent sets the component name for the default logger
 func SetComponent(component string) {
-	if defaultLogger != nil {
-		defaultLogger.component = component
+	if logger := Default(); logger != nil {
+		logger.component = component
 	}
 }
 
 // SetVersion sets the version for the default logger
 func SetVersion(version string) {
-	if defaultLogger != nil {
-		defaultLogger.version = version
+	if logger := Default(); logger != nil {
+		logger.version = version
 	}
 }
 
 // SetLevel sets the log level for the default logger
 func SetLevel(level string) {
-	if defaultLogger != nil {
-		defaultLogger.level = ParseLogLevel(level)
+	if logger := Default(); logger != nil {
+		logger.SetLevel(ParseLogLevel(level))
+	}
+}
+
+// GetLevel returns the current log level of the default logger
+func GetLevel() LogLevel {
+	logger := Default()
+	if logger == nil {
+		return INFO
 	}
+	return logger.GetLevel()
 }
 
 // SetShowCaller enables or disables caller information
 func SetShowCaller(show bool) {
-	if defaultLogger != nil {
-		defaultLogger.showCaller = show
+	if logger := Default(); logger != nil {
+		logger.showCaller = show
 	}
 }
 
-// SetFormat sets the output format (JSON or Plain)
+// SetFormat sets the output format (JSON, Plain or logfmt)
 func SetFormat(format string) {
+	logger := Default()
 
-	if defaultLogger != nil {
+	if logger != nil {
 
 		switch strings.ToLower(format) {
 
 		case "plain", "text", "console":
-			defaultLogger.format = PLAIN_FORMAT
+			logger.format = PLAIN_FORMAT
+
+		case "logfmt":
+			logger.format = LOGFMT_FORMAT
 
 		case "json":
-			defaultLogger.format = JSON_FORMAT
+			logger.format = JSON_FORMAT
 
 		default:
-			defaultLogger.format = JSON_FORMAT
+			logger.format = JSON_FORMAT
 
 		}
 
@@ -151,19 +181,26 @@ func SetJSONFormat() {
 
 // SetSensitiveMode sets whether to mask sensitive data
 func SetSensitiveMode(mode string) {
+	logger := Default()
 
-	if defaultLogger != nil {
+	if logger != nil {
 
 		switch strings.ToLower(mode) {
 
 		case "show", "false", "0", "no", "off":
-			defaultLogger.sensitiveMode = SHOW_SENSITIVE
+			logger.sensitiveMode = SHOW_SENSITIVE
 
 		case "mask", "true", "1", "yes", "on":
-			defaultLogger.sensitiveMode = MASK_SENSITIVE
+			logger.sensitiveMode = MASK_SENSITIVE
+
+		case "hash":
+			logger.sensitiveMode = HASH_SENSITIVE
+
+		case "drop":
+			logger.sensitiveMode = DROP_SENSITIVE
 
 		default:
-			defaultLogger.sensitiveMode = MASK_SENSITIVE
+			logger.sensitiveMode = MASK_SENSITIVE
 
 		}
 
@@ -171,6 +208,18 @@ func SetSensitiveMode(mode string) {
 
 }
 
+// HashSensitiveData replaces sensitive fields with salted hashes for correlation across logs
+func HashSensitiveData() {
+	SetSensitiveMode("hash")
+}
+
+// SetHashSalt sets the salt used when hashing sensitive data
+func SetHashSalt(salt []byte) {
+	if logger := Default(); logger != nil {
+		logger.SetHashSalt(salt)
+	}
+}
+
 // ShowSensitiveData disables masking of sensitive fields (not recommended for production)
 func ShowSensitiveData() {
 	SetSensitiveMode("show")
@@ -183,53 +232,82 @@ func MaskSensitiveData() {
 
 // SetOutput sets the output writer for the default logger
 func SetOutput(writer io.Writer) {
-	if defaultLogger != nil {
-		defaultLogger.writer = writer
+	if logger := Default(); logger != nil {
+		logger.writer = writer
 	}
 }
 
 // SetOutputToDiscard redirects output to discard for benchmarking
 func SetOutputToDiscard() {
-	if defaultLogger != nil {
-		defaultLogger.writer = io.Discard
+	if logger := Default(); logger != nil {
+		logger.writer = io.Discard
 	}
 }
 
 // SetMaskString sets the string used to mask sensitive data
 func SetMaskString(mask string) {
-	if defaultLogger != nil && mask != "" {
-		defaultLogger.maskString = mask
+	logger := Default()
+	if logger != nil && mask != "" {
+		logger.maskString = mask
 	}
 }
 
-// AddSensitiveField adds a custom field pattern to be masked
-func AddSensitiveField(field string) {
-	if defaultLogger != nil {
-		defaultLogger.sensitiveFields = append(defaultLogger.sensitiveFields, strings.ToLower(field))
+// SetPartialMask configures partial masking for sensitive fields.
+// Only string values are partially masked; other values keep the full mask string.
+func SetPartialMask(mask PartialMasking) {
+	if logger := Default(); logger != nil {
+		logger.partialMask = mask
 	}
 }
 
-// SetSensitiveFields replaces the default sensitive field patterns
-func SetSensitiveFields(fields []string) {
-	if defaultLogger != nil {
-		var lowerFields []string
-		for _, field := range fields {
-			lowerFields = append(lowerFields, strings.ToLower(field))
+// AddSensitiveField adds custom field patterns to be masked.
+// The patterns extend the global detection lists and may be added at any time.
+func AddSensitiveField(fields ...string) {
+	logger := Default()
+	updateGlobalFieldMap(&sensitiveFieldsMap, fields, true)
+
+	if logger != nil {
+		if logger.fieldRules != nil && logger.fieldRules.hasOverride(false) {
+			logger.AddSensitiveField(fields...)
+		} else {
+			logger.addFieldNames(false, fields)
 		}
-		defaultLogger.sensitiveFields = lowerFields
+	}
+}
+
+// RemoveSensitiveField removes field patterns from the sensitive detection lists
+func RemoveSensitiveField(fields ...string) {
+	logger := Default()
+	updateGlobalFieldMap(&sensitiveFieldsMap, fields, false)
+
+	if logger != nil {
+		if logger.fieldRules != nil && logger.fieldRules.hasOverride(false) {
+			logger.RemoveSensitiveField(fields...)
+		} else {
+			logger.removeFieldNames(false, fields)
+		}
+	}
+}
+
+// SetSensitiveFields replaces the sensitive field patterns of the default logger
+func SetSensitiveFields(fields []string) {
+	if logger := Default(); logger != nil {
+		logger.SetSensitiveFields(fields)
 	}
 }
 
 // SetPIIMode sets whether to mask PII data
 func SetPIIMode(mode string) {
-	if defaultLogger != nil {
+	if logger := Default(); logger != nil {
 		switch strings.ToLower(mode) {
 		case "show", "false", "0", "no", "off":
-			defaultLogger.piiMode = SHOW_PII
+			logger.piiMode = SHOW_PII
 		case "mask", "true", "1", "yes", "on":
-			defaultLogger.piiMode = MASK_PII
+			logger.piiMode = MASK_PII
+		case "drop":
+			logger.piiMode = DROP_PII
 		default:
-			defaultLogger.piiMode = MASK_PII
+			logger.piiMode = MASK_PII
 		}
 	}
 }
@@ -246,26 +324,64 @@ func MaskPIIData() {
 
 // SetPIIMaskString sets the string used to mask PII data
 func SetPIIMaskString(mask string) {
-	if defaultLogger != nil && mask != "" {
-		defaultLogger.piiMaskString = mask
+	logger := Default()
+	if logger != nil && mask != "" {
+		logger.piiMaskString = mask
 	}
 }
 
-// AddPIIField adds a custom field pattern to be masked as PII
-func AddPIIField(field string) {
-	if defaultLogger != nil {
-		defaultLogger.piiFields = append(defaultLogger.piiFields, strings.ToLower(field))
+// SetPIIPartialMask configures partial masking for PII fields.
+// Only string values are partially masked; other values keep the full PII mask string.
+func SetPIIPartialMask(mask PartialMasking) {
+	if logger := Default(); logger != nil {
+		logger.piiPartialMask = mask
 	}
 }
 
-// SetPIIFields replaces the default PII field patterns
-func SetPIIFields(fields []string) {
-	if defaultLogger != nil {
-		var lowerFields []string
-		for _, field := range fields {
-			lowerFields = append(lowerFields, strings.ToLower(field))
+// AddPIIField adds custom field patterns to be masked as PII.
+// The patterns extend the global detection lists and may be added at any time.
+func AddPIIField(fields ...string) {
+	logger := Default()
+	updateGlobalFieldMap(&piiFieldsMap, fields, true)
+
+	if logger != nil {
+		if logger.fieldRules != nil && logger.fieldRules.hasOverride(true) {
+			logger.AddPIIField(fields...)
+		} else {
+			logger.addFieldNames(true, fields)
 		}
-		defaultLogger.piiFields = lowerFields
+	}
+}
+
+// RemovePIIField removes field patterns from the PII detection lists
+func RemovePIIField(fields ...string) {
+	logger := Default()
+	updateGlobalFieldMap(&piiFieldsMap, fields, false)
+
+	if logger != nil {
+		if logger.fieldRules != nil && logger.fieldRules.hasOverride(true) {
+			logger.RemovePIIField(fields...)
+		} else {
+			logger.removeFieldNames(true, fields)
+		}
+	}
+}
+
+// removeFieldNames returns a copy of list without the given names (case-insensitive)
+func removeFieldNames(list []string, names []string) []string {
+	kept := make([]string, 0, len(list))
+	for _, field := range list {
+		if !slices.ContainsFunc(names, func(name string) bool { return foldFieldName(name) == foldFieldName(field) }) {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
+
+// SetPIIFields replaces the PII field patterns of the default logger
+func SetPIIFields(fields []string) {
+	if logger := Default(); logger != nil {
+		logger.SetPIIFields(fields)
 	}
 }
 
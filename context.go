@@ -0,0 +1,113 @@
+package emit
+
+import (
+	"context"
+	"maps"
+)
+
+// contextKey is the type for emit values stored in a context.Context
+type contextKey int
+
+const (
+	contextFieldsKey contextKey = iota
+)
+
+// WithContextFields returns a context carrying fields that are added to every
+// context-aware log call made with it. Fields already stored in ctx are kept
+// unless overridden by the new fields.
+func WithContextFields(ctx context.Context, fields map[string]any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	existing := contextFields(ctx)
+	merged := make(map[string]any, len(existing)+len(fields))
+	maps.Copy(merged, existing)
+	maps.Copy(merged, fields)
+
+	return context.WithValue(ctx, contextFieldsKey, merged)
+}
+
+// contextFields returns the fields stored in ctx, if any
+func contextFields(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey).(map[string]any)
+	return fields
+}
+
+// logContext merges context fields beneath call-site fields and logs the result.
+// Context fields go through the same masking as call-site fields.
+func (l *Logger) logContext(ctx context.Context, level LogLevel, message string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	if l.tee != nil {
+		l.teeLogContext(ctx, level, message, args...)
+		return
+	}
+	l = l.resolve()
+
+	ctxFields := contextFields(ctx)
+	spanFields := l.traceFields(ctx)
+	if len(ctxFields) == 0 && len(spanFields) == 0 {
+		l.log(level, message, collectFields(args...))
+		return
+	}
+
+	fields := make(map[string]any, len(ctxFields)+len(spanFields)+len(args))
+	maps.Copy(fields, spanFields)
+	maps.Copy(fields, ctxFields)
+	maps.Copy(fields, collectFields(args...))
+
+	l.log(level, message, fields)
+}
+
+// InfoContext logs an info message with fields from ctx and the call site
+func (l *Logger) InfoContext(ctx context.Context, message string, fields ...any) {
+	l.logContext(ctx, INFO, message, fields...)
+}
+
+// ErrorContext logs an error message with fields from ctx and the call site
+func (l *Logger) ErrorContext(ctx context.Context, message string, fields ...any) {
+	l.logContext(ctx, ERROR, message, fields...)
+}
+
+// WarnContext logs a warn message with fields from ctx and the call site
+func (l *Logger) WarnContext(ctx context.Context, message string, fields ...any) {
+	l.logContext(ctx, WARN, message, fields...)
+}
+
+// DebugContext logs a debug message with fields from ctx and the call site
+func (l *Logger) DebugContext(ctx context.Context, message string, fields ...any) {
+	l.logContext(ctx, DEBUG, message, fields...)
+}
+
+// InfoContext logs an info message on the default logger with fields from ctx
+func InfoContext(ctx context.Context, message string, fields ...any) {
+	if logger := Default(); logger != nil {
+		logger.logContext(ctx, INFO, message, fields...)
+	}
+}
+
+// ErrorContext logs an error message on the default logger with fields from ctx
+func ErrorContext(ctx context.Context, message string, fields ...any) {
+	if logger := Default(); logger != nil {
+		logger.logContext(ctx, ERROR, message, fields...)
+	}
+}
+
+// WarnContext logs a warn message on the default logger with fields from ctx
+func WarnContext(ctx context.Context, message string, fields ...any) {
+	if logger := Default(); logger != nil {
+		logger.logContext(ctx, WARN, message, fields...)
+	}
+}
+
+// DebugContext logs a debug message on the default logger with fields from ctx
+func DebugContext(ctx context.Context, message string, fields ...any) {
+	if logger := Default(); logger != nil {
+		logger.logContext(ctx, DEBUG, message, fields...)
+	}
+}
@@ -0,0 +1,146 @@
+package emit
+
+import (
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// dedupSuppressedKey is the field carrying the number of collapsed duplicates
+const dedupSuppressedKey = "suppressed"
+
+// deduplicator collapses consecutive identical entries into one summary line
+type deduplicator struct {
+	mu     sync.Mutex
+	window time.Duration
+
+	// The last entry written, kept so its summary can be emitted later
+	owner      *Logger
+	key        uint64
+	level      LogLevel
+	message    string
+	fields     map[string]any
+	lastSeen   time.Time
+	suppressed int
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// WithDedup collapses consecutive duplicate entries (same level, message and
+// masked fields). The first entry is written immediately; repeats are counted
+// and reported as a single entry with a "suppressed" field once a different
+// entry arrives, once no repeat has been seen for window, or on Close.
+func WithDedup(window time.Duration) Option {
+	return func(l *Logger) error {
+		if window <= 0 {
+			return errors.New("emit: dedup window must be positive")
+		}
+		// The worker starts, and a replaced one stops, once every option applied
+		l.dedup = &deduplicator{
+			window: window,
+			stop:   make(chan struct{}),
+			done:   make(chan struct{}),
+		}
+		return nil
+	}
+}
+
+// suppress reports whether the entry repeats the previous one and was counted
+// instead of written. A pending summary for a different entry is flushed first.
+func (d *deduplicator) suppress(l *Logger, level LogLevel, message string, fields map[string]any) bool {
+	key := l.dedupKey(level, message, fields)
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.owner != nil && key == d.key && level == d.level && message == d.message && now.Sub(d.lastSeen) < d.window {
+		d.suppressed++
+		d.lastSeen = now
+		return true
+	}
+
+	d.flushLocked()
+	d.owner = l
+	d.key = key
+	d.level = level
+	d.message = message
+	d.fields = maps.Clone(fields)
+	d.lastSeen = now
+	return false
+}
+
+// flush writes the pending summary, if any
+func (d *deduplicator) flush() {
+	d.mu.Lock()
+	d.flushLocked()
+	d.mu.Unlock()
+}
+
+// flushLocked writes the summary for the tracked entry, if any repeats were seen
+func (d *deduplicator) flushLocked() {
+	if d.suppressed == 0 || d.owner == nil {
+		return
+	}
+	summary := make(map[string]any, len(d.fields)+1)
+	maps.Copy(summary, d.fields)
+	summary[dedupSuppressedKey] = d.suppressed
+	d.suppressed = 0
+	d.owner.writeEntry(d.level, d.message, summary)
+}
+
+// run flushes summaries for entries that stopped repeating
+func (d *deduplicator) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.mu.Lock()
+			if time.Since(d.lastSeen) >= d.window {
+				d.flushLocked()
+				d.owner = nil
+			}
+			d.mu.Unlock()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// close stops the background goroutine and flushes the final summary
+func (d *deduplicator) close() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		<-d.done
+
+		d.mu.Lock()
+		d.flushLocked()
+		d.owner = nil
+		d.mu.Unlock()
+	})
+}
+
+// dedupKey hashes the level, message and masked fields. Field hashes are
+// combined order-independently so map iteration order does not matter.
+func (l *Logger) dedupKey(level LogLevel, message string, fields map[string]any) uint64 {
+	hash := fnvAddString(fnvAddByte(fnvOffset, byte(level)), message)
+
+	// The entry is masked again when written; count and report it only then
+	quiet := *l
+	quiet.maskObserver = nil
+	quiet.stats = nil
+	masker := &quiet
+
+	var fieldsHash uint64
+	for key, value := range masker.maskSensitiveFieldsFast(fields) {
+		fieldsHash += fnvAddValue(fnvAddString(fnvOffset, key), value)
+	}
+	return hash ^ fieldsHash
+}
@@ -0,0 +1,105 @@
+package emit
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+)
+
+// WithDefaultFields sets process-wide fields, such as hostname, version or
+// pid, written on every entry. Unlike WithFields, which suits per-request
+// values, they are masked once, after all options passed to New or Configure
+// are applied, and the masked map is shared by every entry. Call-site and
+// WithFields fields with the same key take precedence.
+func WithDefaultFields(fields map[string]any) Option {
+	return func(l *Logger) error {
+		l.unmaskedDefaults = maps.Clone(fields)
+		return nil
+	}
+}
+
+// maskDefaultFields masks the default fields with the current configuration.
+// Runtime fields are added beneath them unmasked.
+func (l *Logger) maskDefaultFields() {
+	if len(l.unmaskedDefaults) == 0 && len(l.runtimeFields) == 0 {
+		l.defaultFields = nil
+		return
+	}
+	masked := l.maskFields(l.unmaskedDefaults)
+	if len(l.runtimeFields) == 0 {
+		l.defaultFields = masked
+		return
+	}
+	l.defaultFields = make(map[string]any, len(l.runtimeFields)+len(masked))
+	maps.Copy(l.defaultFields, l.runtimeFields)
+	maps.Copy(l.defaultFields, masked)
+}
+
+// hasFields reports whether an entry has any fields to write
+func (l *Logger) hasFields(fields map[string]any) bool {
+	return len(fields) > 0 || len(l.defaultFields) > 0
+}
+
+// mergeDefaultFields returns the masked entry fields on top of the
+// pre-masked defaults
+func (l *Logger) mergeDefaultFields(fields map[string]any) map[string]any {
+	if len(fields) == 0 {
+		return l.defaultFields
+	}
+
+	merged := make(map[string]any, len(l.defaultFields)+len(fields))
+	maps.Copy(merged, l.defaultFields)
+	if l.skipsMasking(fields) {
+		maps.Copy(merged, textFields(fields))
+	} else {
+		l.maskFieldsInto(merged, l.applyMaskPaths(fields), nil)
+	}
+	return merged
+}
+
+// writeWithDefaultFields streams the pre-masked defaults and the masked
+// entry fields as one JSON object
+func (l *Logger) writeWithDefaultFields(buf *bytes.Buffer, fields map[string]any) {
+	masking := !l.skipsMasking(fields)
+
+	buf.WriteByte('{')
+	first := true
+	var visited map[maskVisitKey]bool
+	writeMember := func(key string) {
+		value, ok := fields[key]
+		if !ok {
+			// Defaults are masked already
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			writeJSONString(buf, key, l.escapeHTML)
+			buf.WriteByte(':')
+			l.writeJSONValue(buf, l.defaultFields[key])
+			return
+		}
+		var written bool
+		visited, written = l.writeMaskedMember(buf, key, value, !first, masking, visited)
+		if written {
+			first = false
+		}
+	}
+
+	if l.sortsKeys() {
+		keys := slices.AppendSeq(slices.Collect(maps.Keys(l.defaultFields)), maps.Keys(fields))
+		slices.Sort(keys)
+		for _, key := range slices.Compact(keys) {
+			writeMember(key)
+		}
+	} else {
+		for key := range l.defaultFields {
+			if _, overridden := fields[key]; !overridden {
+				writeMember(key)
+			}
+		}
+		for key := range fields {
+			writeMember(key)
+		}
+	}
+	buf.WriteByte('}')
+}
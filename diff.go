@@ -0,0 +1,137 @@
+package emit
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DiffZField is a field holding the changes between two states
+type DiffZField struct {
+	Key     string
+	Changes map[string]any
+}
+
+// Diff creates a "changes" field listing only what differs between before
+// and after, for audit entries that stay readable as objects grow:
+//
+//	logger.Info("user updated", emit.Diff(oldUser, newUser))
+//	// "changes":{"plan":{"old":"free","new":"pro"},"role":{"old":"viewer","new":"admin"}}
+//
+// Maps and structs, or pointers to structs, are compared key by key, and
+// nested ones produce nested changes. Added keys have no "old" value and
+// removed keys no "new" value; other values, slices included, are compared
+// whole with reflect.DeepEqual. Struct fields are named and tagged as with
+// WithStructMasking, and fields tagged `log:"mask"` are wrapped with Secret.
+// The changes are masked like any nested fields, so a sensitive key has its
+// old and new values replaced by the mask string together.
+func Diff(before, after any) DiffZField {
+	changes := diffValues(before, after)
+	if changes == nil {
+		changes = map[string]any{}
+	}
+	return DiffZField{Key: "changes", Changes: changes}
+}
+
+func (f DiffZField) WriteToEncoder(enc *ZeroAllocEncoder) {
+	enc.writeStringField(f.Key, fmt.Sprint(f.Changes))
+}
+
+func (f DiffZField) IsSensitive() bool { return false }
+func (f DiffZField) IsPII() bool       { return false }
+
+// diffValues returns the changes from before to after, or nil if they are equal
+func diffValues(before, after any) map[string]any {
+	beforeFields, beforeOK := diffFields(before)
+	afterFields, afterOK := diffFields(after)
+	if !beforeOK || !afterOK {
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		return diffChange(before, true, after, true)
+	}
+
+	changes := make(map[string]any)
+	for key, old := range beforeFields {
+		updated, ok := afterFields[key]
+		if !ok {
+			changes[key] = diffChange(old, true, nil, false)
+			continue
+		}
+		_, oldNested := diffFields(old)
+		_, newNested := diffFields(updated)
+		if oldNested && newNested {
+			if nested := diffValues(old, updated); len(nested) > 0 {
+				changes[key] = nested
+			}
+			continue
+		}
+		if !reflect.DeepEqual(unwrapSecret(old), unwrapSecret(updated)) {
+			changes[key] = diffChange(old, true, updated, true)
+		}
+	}
+	for key, updated := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = diffChange(nil, false, updated, true)
+		}
+	}
+	return changes
+}
+
+// diffChange builds the old and new entry of one changed value
+func diffChange(old any, hasOld bool, updated any, hasNew bool) map[string]any {
+	change := make(map[string]any, 2)
+	if hasOld {
+		change["old"] = old
+	}
+	if hasNew {
+		change["new"] = updated
+	}
+	return change
+}
+
+// diffFields returns the keys and values Diff compares for maps and structs
+func diffFields(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case Fields:
+		return v, true
+	case nil:
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || keepsOwnEncoding(rv.Type()) {
+		return nil, false
+	}
+
+	infos := structFields(rv.Type())
+	fields := make(map[string]any, len(infos))
+	for _, info := range infos {
+		fv, err := rv.FieldByIndexErr(info.index)
+		if err != nil {
+			// Field inside a nil embedded pointer
+			continue
+		}
+		if info.mask {
+			fields[info.name] = Secret(fv.Interface())
+		} else {
+			fields[info.name] = fv.Interface()
+		}
+	}
+	return fields, true
+}
+
+// unwrapSecret returns the value inside a Secret for comparison
+func unwrapSecret(value any) any {
+	if secret, ok := value.(SecretValue); ok {
+		return secret.value
+	}
+	return value
+}
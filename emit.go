@@ -1,6 +1,9 @@
 package emit
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+)
 
 // parseKeyValuePairs converts variadic args to map[string]any
 // Used internally by the API for emit.Info.KeyValue() etc.
@@ -27,60 +30,118 @@ func parseKeyValuePairs(keyValuePairs ...any) map[string]any {
 	return fields
 }
 
+// collectFields merges variadic logging arguments into a single field map.
+// Arguments may be Fields or map[string]any values (merged in order), ZField
+// values, or alternating key-value pairs. Later arguments override earlier ones.
+func collectFields(args ...any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+
+	// A single map is used as-is: masking and hooks work on copies and dedup
+	// clones what it keeps, so the caller's map is never changed or retained
+	if len(args) == 1 {
+		switch arg := args[0].(type) {
+		case Fields:
+			return arg
+		case map[string]any:
+			return arg
+		}
+	}
+
+	fields := make(map[string]any, len(args))
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i].(type) {
+		case nil:
+			continue
+		case Fields:
+			maps.Copy(fields, arg)
+		case map[string]any:
+			maps.Copy(fields, arg)
+		case ErrorZField:
+			if arg.Err == nil {
+				fields[arg.Key] = nil
+				continue
+			}
+			fields[arg.Key] = arg.Err.Error()
+			if trace := errorStackTrace(arg.Err, defaultStackTraceDepth); trace != nil {
+				fields[stackTraceKey] = trace
+			}
+		case ZField:
+			if key, value, ok := zfieldKeyValue(arg); ok {
+				fields[key] = value
+			}
+		default:
+			key, ok := arg.(string)
+			if !ok {
+				// Convert non-string keys to strings
+				key = fmt.Sprintf("%v", arg)
+			}
+			if i+1 < len(args) {
+				fields[key] = args[i+1]
+				i++
+			} else {
+				fields[key] = "<missing_value>"
+			}
+		}
+	}
+	return fields
+}
+
 // Internal helper functions for the API
 // These provide the actual logging implementation for the API namespace
 
 func logWithFields(level LogLevel, message string, fields Fields) {
-	if defaultLogger != nil {
-		defaultLogger.log(level, message, fields.ToMap())
+	if logger := Default(); logger != nil {
+		logger.log(level, message, fields.ToMap())
 	}
 }
 
 func logWithKeyValues(level LogLevel, message string, keysAndValues ...interface{}) {
-	if defaultLogger != nil {
+	if logger := Default(); logger != nil {
 		fields := parseKeyValuePairs(keysAndValues...)
-		defaultLogger.log(level, message, fields)
+		logger.log(level, message, fields)
 	}
 }
 
 func logWithPool(level LogLevel, message string, fn func(*PooledFields)) {
-	if defaultLogger != nil {
+	if logger := Default(); logger != nil {
 		pf := NewPooledFields()
 		fn(pf)
-		defaultLogger.log(level, message, pf.ToMap())
+		logger.log(level, message, pf.ToMap())
 		pf.Release()
 	}
 }
 
 // Simple message logging functions with clear names
 func InfoMsg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(INFO, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(INFO, message, nil)
 	}
 }
 
 func ErrorMsg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(ERROR, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(ERROR, message, nil)
 	}
 }
 
 func WarnMsg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(WARN, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(WARN, message, nil)
 	}
 }
 
 func DebugMsg(message string) {
-	if defaultLogger != nil {
-		defaultLogger.log(DEBUG, message, nil)
+	if logger := Default(); logger != nil {
+		logger.log(DEBUG, message, nil)
 	}
 }
 
 // InfoWithFields logs an info message with a map of fields
 func InfoWithFields(message string, fields map[string]any) {
-	if defaultLogger != nil {
-		defaultLogger.log(INFO, message, fields)
+	if logger := Default(); logger != nil {
+		logger.log(INFO, message, fields)
 	}
 }
 
@@ -88,47 +149,50 @@ func InfoWithFields(message string, fields map[string]any) {
 
 // Log is a generic logging function that can be used for custom integrations
 func Log(level, message string, optionalParams ...string) {
+	logger := Default()
 	logLevel := ParseLogLevel(level)
 
 	// Handle optional parameters for component and version
-	if len(optionalParams) >= 1 && defaultLogger.component == "" {
+	if len(optionalParams) >= 1 && logger.component == "" {
 		SetComponent(optionalParams[0])
 	}
-	if len(optionalParams) >= 2 && defaultLogger.version == "" {
+	if len(optionalParams) >= 2 && logger.version == "" {
 		SetVersion(optionalParams[1])
 	}
 
-	defaultLogger.log(logLevel, message, nil)
+	logger.log(logLevel, message, nil)
 }
 
 // JSON forces JSON output for a single log entry (for special cases)
 func JSON(severity, message string, optionalParams ...string) {
+	logger := Default()
 	logLevel := ParseLogLevel(severity)
 
 	// Handle optional parameters
-	if len(optionalParams) >= 1 && defaultLogger.component == "" {
+	if len(optionalParams) >= 1 && logger.component == "" {
 		SetComponent(optionalParams[0])
 	}
-	if len(optionalParams) >= 2 && defaultLogger.version == "" {
+	if len(optionalParams) >= 2 && logger.version == "" {
 		SetVersion(optionalParams[1])
 	}
 
 	// Force JSON format for this call
-	defaultLogger.logJSON(logLevel, message, nil)
+	logger.resolve().logJSON(logLevel, message, nil)
 }
 
 // Plain forces plain output for a single log entry (for special cases)
 func Plain(severity, message string, optionalParams ...string) {
+	logger := Default()
 	logLevel := ParseLogLevel(severity)
 
 	// Handle optional parameters
-	if len(optionalParams) >= 1 && defaultLogger.component == "" {
+	if len(optionalParams) >= 1 && logger.component == "" {
 		SetComponent(optionalParams[0])
 	}
-	if len(optionalParams) >= 2 && defaultLogger.version == "" {
+	if len(optionalParams) >= 2 && logger.version == "" {
 		SetVersion(optionalParams[1])
 	}
 
 	// Force plain format for this call
-	defaultLogger.logPlain(logLevel, message, nil)
+	logger.resolve().logPlain(logLevel, message, nil)
 }
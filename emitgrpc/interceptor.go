@@ -0,0 +1,197 @@
+// Package emitgrpc provides gRPC server interceptors that log each call
+// through an emit.Logger, so metadata and payload fields are masked like any
+// other entry. It lives in its own module to keep the core emit module free
+// of dependencies.
+package emitgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/cloudresty/emit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// healthPrefix is the method prefix of the standard gRPC health service
+const healthPrefix = "/grpc.health.v1.Health/"
+
+// Option configures the interceptors
+type Option func(*config)
+
+type config struct {
+	skipMethods  map[string]bool
+	skipPrefixes []string
+}
+
+// WithSkipMethods stops calls to the given full method names, such as
+// "/payments.v1.Payments/Ping", from being logged
+func WithSkipMethods(methods ...string) Option {
+	return func(c *config) {
+		for _, method := range methods {
+			c.skipMethods[method] = true
+		}
+	}
+}
+
+// WithSkipHealthChecks stops calls to the grpc.health.v1.Health service from
+// being logged
+func WithSkipHealthChecks() Option {
+	return func(c *config) {
+		c.skipPrefixes = append(c.skipPrefixes, healthPrefix)
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{skipMethods: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *config) skip(method string) bool {
+	if c.skipMethods[method] {
+		return true
+	}
+	for _, prefix := range c.skipPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryServerInterceptor logs each unary call with its method, status code,
+// duration, peer address and incoming metadata once it completes:
+//
+//	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+//		emitgrpc.UnaryServerInterceptor(logger, emitgrpc.WithSkipHealthChecks()),
+//	))
+//
+// Authorization and cookie metadata are always masked and other keys by the
+// logger's field rules. Fields, trace and request IDs carried by the call
+// context are added like in InfoContext. When the logger is at debug level the request and response messages
+// are added as "grpc.request" and "grpc.response", encoded with their proto
+// field names and masked field by field.
+func UnaryServerInterceptor(logger *emit.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if c.skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		ce := logger.Check(codeLevel(code))
+		if ce == nil {
+			return resp, err
+		}
+		fields := callFields(ctx, info.FullMethod, code, time.Since(start), err)
+		if logger.Enabled(emit.DEBUG) {
+			fields = append(fields, "grpc.request", payload(req))
+			if err == nil {
+				fields = append(fields, "grpc.response", payload(resp))
+			}
+		}
+		ce.WriteContext(ctx, "grpc call", fields...)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs each streaming call like
+// UnaryServerInterceptor once the stream ends. Stream messages are not
+// logged.
+func StreamServerInterceptor(logger *emit.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if c.skip(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		err := handler(srv, ss)
+
+		code := status.Code(err)
+		if ce := logger.Check(codeLevel(code)); ce != nil {
+			ctx := ss.Context()
+			ce.WriteContext(ctx, "grpc stream", callFields(ctx, info.FullMethod, code, time.Since(start), err)...)
+		}
+		return err
+	}
+}
+
+// callFields returns the fields shared by unary and streaming calls
+func callFields(ctx context.Context, method string, code codes.Code, duration time.Duration, err error) []any {
+	fields := []any{
+		"grpc.method", method,
+		"grpc.status", code.String(),
+		"duration", duration,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, "peer", p.Addr.String())
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && md.Len() > 0 {
+		fields = append(fields, "grpc.metadata", metadataFields(md))
+	}
+	if err != nil {
+		fields = append(fields, emit.Err(err))
+	}
+	return fields
+}
+
+// metadataFields turns metadata into a field map the logger can mask by key,
+// marking the keys the HTTP middleware always masks as secrets
+func metadataFields(md metadata.MD) map[string]any {
+	fields := make(map[string]any, len(md))
+	for key, values := range md {
+		value := strings.Join(values, ", ")
+		if emit.AlwaysMaskedHeader(key) {
+			fields[key] = emit.Secret(value)
+			continue
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// payload returns a proto message as a field map, so its fields are masked
+// by name. Other values are logged as they are.
+func payload(v any) any {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return v
+	}
+	encoded, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return v
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return v
+	}
+	return fields
+}
+
+// codeLevel maps a status code to a level: client errors are logged at info
+// or warn and server failures at error
+func codeLevel(code codes.Code) emit.LogLevel {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return emit.INFO
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return emit.WARN
+	default:
+		return emit.ERROR
+	}
+}
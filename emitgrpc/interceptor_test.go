@@ -0,0 +1,123 @@
+package emitgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cloudresty/emit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	sink := emit.NewMemorySink()
+	logger, err := emit.New(emit.WithOutput(sink), emit.WithLevel(emit.DEBUG))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	interceptor := UnaryServerInterceptor(logger, WithSkipHealthChecks(), WithSkipMethods("/test.v1.Test/Ping"))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer abc123", "x-tenant", "acme"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 5100}})
+	req, _ := structpb.NewStruct(map[string]any{"password": "hunter2", "amount": 5})
+
+	handler := func(ctx context.Context, req any) (any, error) { return req, nil }
+	if _, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test.v1.Test/Pay"}, handler); err != nil {
+		t.Fatalf("Unexpected handler error: %v", err)
+	}
+	entry, ok := sink.LastEntry()
+	if !ok || entry.Level != emit.INFO || entry.Fields["grpc.method"] != "/test.v1.Test/Pay" || entry.Fields["grpc.status"] != "OK" {
+		t.Fatalf("Unexpected entry: %+v", entry)
+	}
+	if entry.Fields["peer"] != "10.0.0.7:5100" {
+		t.Errorf("Expected the peer address, got %v", entry.Fields["peer"])
+	}
+	md, _ := entry.Fields["grpc.metadata"].(map[string]any)
+	if md["authorization"] != "***MASKED***" || md["x-tenant"] != "acme" {
+		t.Errorf("Expected masked authorization metadata, got %v", md)
+	}
+	request, _ := entry.Fields["grpc.request"].(map[string]any)
+	if request["password"] != "***MASKED***" || request["amount"] != float64(5) {
+		t.Errorf("Expected masked request payload, got %v", request)
+	}
+
+	failing := func(ctx context.Context, req any) (any, error) { return nil, status.Error(codes.Internal, "db down") }
+	interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test.v1.Test/Pay"}, failing)
+	if entry, _ := sink.LastEntry(); entry.Level != emit.ERROR || entry.Fields["grpc.status"] != "Internal" {
+		t.Errorf("Expected an error entry for Internal, got %+v", entry)
+	}
+
+	sink.Reset()
+	interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test.v1.Test/Ping"}, handler)
+	if entries := sink.Entries(); len(entries) != 0 {
+		t.Errorf("Expected skipped methods not to be logged, got %+v", entries)
+	}
+
+	logger.SetLevel(emit.INFO)
+	interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test.v1.Test/Pay"}, handler)
+	if entry, _ := sink.LastEntry(); entry.Fields["grpc.request"] != nil {
+		t.Errorf("Expected payloads only at debug level, got %v", entry.Fields)
+	}
+}
+
+func TestInterceptorShownSensitiveData(t *testing.T) {
+	sink := emit.NewMemorySink()
+	logger, err := emit.New(emit.WithOutput(sink), emit.WithSensitiveMode(emit.SHOW_SENSITIVE))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	interceptor := UnaryServerInterceptor(logger)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer abc123", "x-api-key", "k-1"))
+	ctx = emit.WithRequestID(emit.WithContextFields(ctx, map[string]any{"tenant": "acme"}), "req-42")
+	handler := func(ctx context.Context, req any) (any, error) { return req, nil }
+	interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.v1.Test/Pay"}, handler)
+
+	entry, _ := sink.LastEntry()
+	md, _ := entry.Fields["grpc.metadata"].(map[string]any)
+	if md["authorization"] != "***MASKED***" || md["x-api-key"] != "k-1" {
+		t.Errorf("Expected authorization masked while sensitive data is shown, got %v", md)
+	}
+	if entry.Fields["tenant"] != "acme" || entry.Fields["request_id"] != "req-42" {
+		t.Errorf("Expected context fields and request ID, got %v", entry.Fields)
+	}
+
+	stream := testStream{ctx: ctx}
+	StreamServerInterceptor(logger)(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.v1.Test/Watch"},
+		func(srv any, ss grpc.ServerStream) error { return nil })
+	if entry, _ := sink.LastEntry(); entry.Fields["request_id"] != "req-42" {
+		t.Errorf("Expected the stream context's request ID, got %v", entry.Fields)
+	}
+}
+
+// testStream is a ServerStream carrying a context
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s testStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	sink := emit.NewMemorySink()
+	logger, err := emit.New(emit.WithOutput(sink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	interceptor := StreamServerInterceptor(logger)
+
+	stream := testStream{ctx: context.Background()}
+	handler := func(srv any, ss grpc.ServerStream) error { return status.Error(codes.Unavailable, "draining") }
+	interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.v1.Test/Watch"}, handler)
+
+	entry, ok := sink.LastEntry()
+	if !ok || entry.Level != emit.WARN || entry.Fields["grpc.status"] != "Unavailable" || entry.Fields["error"] == nil {
+		t.Errorf("Unexpected stream entry: %+v", entry)
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/cloudresty/emit/emitlogr
+
+go 1.24
+
+require github.com/cloudresty/emit v1.1.2
+
+require github.com/go-logr/logr v1.4.2
+
+replace github.com/cloudresty/emit => ../
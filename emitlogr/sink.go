@@ -0,0 +1,144 @@
+// Package emitlogr adapts an emit.Logger to go-logr, so Kubernetes tooling
+// such as controller-runtime logs through emit's masking and sinks. It lives
+// in its own module to keep the core emit module free of dependencies.
+package emitlogr
+
+import (
+	"github.com/cloudresty/emit"
+	"github.com/go-logr/logr"
+)
+
+// nameKey is the field holding names accumulated with WithName
+const nameKey = "logger"
+
+// missingValue completes an odd key/value list, matching emit's own placeholder
+const missingValue = "<missing_value>"
+
+// badKey replaces a key that is not a string, as slog does
+const badKey = "!BADKEY"
+
+// sinkFrames is the number of frames the sink's own methods add between logr
+// and the emit logger
+const sinkFrames = 1
+
+// Sink is a logr.LogSink backed by an emit.Logger. V(0) entries are logged
+// at info level and higher verbosities at debug level.
+type Sink struct {
+	logger    *emit.Logger
+	callDepth int
+	name      string
+	values    []any
+}
+
+var (
+	_ logr.LogSink          = (*Sink)(nil)
+	_ logr.CallDepthLogSink = (*Sink)(nil)
+)
+
+// NewLogrSink returns a LogSink that writes through logger:
+//
+//	log := logr.New(emitlogr.NewLogrSink(logger))
+func NewLogrSink(logger *emit.Logger) *Sink {
+	return &Sink{logger: logger}
+}
+
+// Init records the frames logr adds, so caller information written with
+// emit.WithCaller points at the code calling logr
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// WithCallDepth returns a sink that skips depth more frames, for helpers
+// that wrap logr
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	child := *s
+	child.callDepth += depth
+	return &child
+}
+
+// caller returns the logger to write with, skipping the sink and logr frames
+func (s *Sink) caller() *emit.Logger {
+	return s.logger.AddCallerSkip(sinkFrames + s.callDepth)
+}
+
+// Enabled reports whether entries at the verbosity level would be logged
+func (s *Sink) Enabled(level int) bool {
+	return s.logger.Enabled(verbosityLevel(level))
+}
+
+// Info logs a non-error message with the given key/value pairs
+func (s *Sink) Info(level int, msg string, keysAndValues ...any) {
+	fields := s.fields(nil, keysAndValues)
+	if verbosityLevel(level) == emit.LevelDebug {
+		s.caller().Debug(msg, fields...)
+		return
+	}
+	s.caller().Info(msg, fields...)
+}
+
+// Error logs an error entry; err is recorded in the "error" field
+func (s *Sink) Error(err error, msg string, keysAndValues ...any) {
+	var errField any
+	if err != nil {
+		errField = emit.Err(err)
+	}
+	s.caller().Error(msg, s.fields(errField, keysAndValues)...)
+}
+
+// WithValues returns a sink that adds the key/value pairs to every entry
+func (s *Sink) WithValues(keysAndValues ...any) logr.LogSink {
+	child := *s
+	child.values = append(append(make([]any, 0, len(s.values)+len(keysAndValues)), s.values...), keysAndValues...)
+	return &child
+}
+
+// WithName returns a sink whose "logger" field has name appended with a dot
+func (s *Sink) WithName(name string) logr.LogSink {
+	child := *s
+	if s.name == "" {
+		child.name = name
+	} else {
+		child.name = s.name + "." + name
+	}
+	return &child
+}
+
+// fields merges the error, name, stored values and call-site pairs into one
+// argument list for the emit logger; later pairs win on duplicate keys
+func (s *Sink) fields(errField any, keysAndValues []any) []any {
+	fields := make([]any, 0, 5+len(s.values)+len(keysAndValues))
+	if errField != nil {
+		fields = append(fields, errField)
+	}
+	if s.name != "" {
+		fields = append(fields, nameKey, s.name)
+	}
+	fields = appendPairs(fields, s.values)
+	return appendPairs(fields, keysAndValues)
+}
+
+// appendPairs appends key/value pairs, completing a dangling key so it
+// cannot pair with whatever follows. Keys that are not strings, nil among
+// them, become badKey so emit cannot read them as fields and shift the pairs.
+func appendPairs(dst, keysAndValues []any) []any {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = badKey
+		}
+		value := any(missingValue)
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		dst = append(dst, key, value)
+	}
+	return dst
+}
+
+// verbosityLevel maps logr verbosity to an emit level
+func verbosityLevel(level int) emit.LogLevel {
+	if level > 0 {
+		return emit.LevelDebug
+	}
+	return emit.LevelInfo
+}
@@ -0,0 +1,85 @@
+package emitlogr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudresty/emit"
+	"github.com/go-logr/logr"
+)
+
+func TestLogrSink(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, err := emit.New(emit.WithOutput(&buf), emit.WithLevel(emit.LevelInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	log := logr.New(NewLogrSink(logger)).WithName("controller").WithName("pods").WithValues("namespace", "default")
+
+	log.V(1).Info("hidden at info level")
+	log.Info("reconciled", "token", "abc123", "dangling")
+	log.Error(errors.New("conflict"), "update failed", "attempt", 2)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %d:\n%s", len(lines), buf.String())
+	}
+
+	var info, failure emit.LogEntry
+	if err := json.Unmarshal(lines[0], &info); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(lines[1], &failure); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if info.Level != "info" || info.Fields["logger"] != "controller.pods" || info.Fields["namespace"] != "default" {
+		t.Errorf("Unexpected info entry: %+v", info)
+	}
+	if info.Fields["token"] != "***MASKED***" || info.Fields["dangling"] != missingValue {
+		t.Errorf("Expected masked token and completed dangling key, got %v", info.Fields)
+	}
+	if failure.Level != "error" || failure.Fields["error"] != "conflict" || failure.Fields["attempt"] != float64(2) {
+		t.Errorf("Unexpected error entry: %+v", failure)
+	}
+
+	buf.Reset()
+	log.Info("bad keys", nil, "first", 42, "second", "after", "kept")
+	var bad emit.LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &bad); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if _, ok := bad.Fields[badKey]; !ok || bad.Fields["after"] != "kept" || bad.Fields["first"] != nil || bad.Fields["second"] != nil {
+		t.Errorf("Expected non-string keys replaced without shifting the pairs, got %v", bad.Fields)
+	}
+
+	logger.SetLevel(emit.LevelDebug)
+	if !log.V(3).Enabled() {
+		t.Errorf("Expected verbose entries to be enabled at debug level")
+	}
+}
+
+func TestLogrSinkCaller(t *testing.T) {
+	sink := emit.NewMemorySink()
+	logger, err := emit.New(emit.WithOutput(sink), emit.WithCaller())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	log := logr.New(NewLogrSink(logger))
+	helper := func(msg string) { log.WithCallDepth(1).Info(msg) }
+
+	log.Info("direct")
+	helper("wrapped")
+
+	for _, entry := range sink.Entries() {
+		caller, _ := entry.Fields["caller"].(string)
+		if file, _, _ := strings.Cut(caller, ":"); !strings.HasSuffix(file, "/sink_test.go") {
+			t.Errorf("Expected the %s entry's caller in the test, got %q", entry.Message, caller)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Encoder writes entries in a custom format, such as protobuf or msgpack
+type Encoder interface {
+	// Encode writes one complete entry to w, including any delimiter. The
+	// entry and its fields must not be retained after Encode returns.
+	Encode(w io.Writer, e *Entry) error
+}
+
+// WithEncoder writes entries with enc instead of the configured format:
+//
+//	emit.WithEncoder(msgpackEncoder{})
+//
+// The entry passed to enc has already been through the whole pipeline:
+// level filtering, sampling, base and context fields, lazy fields and
+// masking, so an encoder never sees unmasked values and only has to lay the
+// entry out. The timestamp is the logger's clock; the time format, key and
+// level name options, which belong to the built-in formats, do not apply.
+// Lines go to the configured output, sinks and hooks as usual. An entry enc
+// fails to encode is dropped and the error reported to the error handler.
+//
+// The built-in formats keep their specialized encoders. JSONEncoder and
+// LogfmtEncoder write the default JSON and logfmt layouts through this
+// interface, for wrapping or as a starting point.
+func WithEncoder(enc Encoder) Option {
+	return func(l *Logger) error {
+		if enc == nil {
+			return errors.New("emit: encoder must not be nil")
+		}
+		l.encoder = enc
+		return nil
+	}
+}
+
+// logEncoded masks an entry and writes it with the custom encoder
+func (l *Logger) logEncoded(level LogLevel, message string, fields map[string]any) {
+	entry := &Entry{
+		Level:     level,
+		Message:   message,
+		Component: l.component,
+		Version:   l.version,
+	}
+	if !l.omitsTimestamp() {
+		entry.Time = l.now()
+	}
+	if l.hasFields(fields) {
+		entry.Fields = l.maskSensitiveFieldsFast(fields)
+	}
+
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	if err := l.encoder.Encode(buf, entry); err != nil {
+		l.reportError(fmt.Errorf("emit: encoding entry: %w", err))
+		return
+	}
+	l.writeLine(level, buf.Bytes())
+}
+
+// JSONEncoder writes entries as JSON lines in the layout of the default JSON
+// format
+type JSONEncoder struct{}
+
+// Encode writes e as one JSON line
+func (JSONEncoder) Encode(w io.Writer, e *Entry) error {
+	entry := LogEntry{
+		Timestamp: formatFastTimestamp(e.Time),
+		Level:     e.Level.String(),
+		Message:   e.Message,
+		Component: e.Component,
+		Version:   e.Version,
+		Fields:    e.Fields,
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(entry)
+}
+
+// LogfmtEncoder writes entries as logfmt lines in the layout of the logfmt
+// format, with nested fields flattened and sorted
+type LogfmtEncoder struct{}
+
+// logfmtValues writes logfmt values with the default value formats
+var logfmtValues = &Logger{}
+
+// Encode writes e as one logfmt line
+func (LogfmtEncoder) Encode(w io.Writer, e *Entry) error {
+	var buf bytes.Buffer
+	buf.WriteString(defaultTimeKey + "=")
+	writeLogfmtString(&buf, formatFastTimestamp(e.Time))
+	buf.WriteString(" level=")
+	writeLogfmtString(&buf, e.Level.String())
+	buf.WriteString(" msg=")
+	writeLogfmtString(&buf, e.Message)
+	if e.Component != "" {
+		buf.WriteString(" component=")
+		writeLogfmtString(&buf, e.Component)
+	}
+	if e.Version != "" {
+		buf.WriteString(" version=")
+		writeLogfmtString(&buf, e.Version)
+	}
+
+	flat := make(map[string]any, len(e.Fields))
+	flattenFields(flat, "", e.Fields, ".")
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		buf.WriteByte(' ')
+		writeLogfmtKey(&buf, key)
+		buf.WriteByte('=')
+		logfmtValues.writeLogfmtValue(&buf, flat[key])
+	}
+	buf.WriteByte('\n')
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
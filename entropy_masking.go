@@ -0,0 +1,101 @@
+package emit
+
+import (
+	"errors"
+	"math"
+)
+
+// Default length range of values checked by WithEntropyMasking
+const (
+	defaultEntropyMinLength = 20
+	defaultEntropyMaxLength = 512
+)
+
+// EntropyOption customizes detection configured with WithEntropyMasking
+type EntropyOption func(*entropyDetector)
+
+// entropyDetector flags string values that look randomly generated
+type entropyDetector struct {
+	threshold float64
+	minLength int
+	maxLength int
+}
+
+// WithEntropyMasking masks string values whose Shannon entropy, in bits per
+// byte, exceeds threshold, catching API keys and tokens logged under
+// innocent names:
+//
+//	emit.WithEntropyMasking(4.5, emit.EntropyLength(24, 256))
+//
+// Only values between 20 and 512 bytes long without whitespace are checked
+// by default. Random tokens score high, around 4.5-5 for 32 or more base64
+// characters, while identifiers, UUIDs and hex digests score 3.3-3.8. URLs
+// and qualified names with many distinct characters reach 4.3-4.4 though,
+// which is why detection is opt-in: start near 4.5 and raise the threshold
+// or the minimum length if ordinary values get masked. Like value patterns,
+// it runs after the field-name checks, on fields not exempted, and is
+// skipped with SHOW_SENSITIVE.
+func WithEntropyMasking(threshold float64, opts ...EntropyOption) Option {
+	return func(l *Logger) error {
+		if math.IsNaN(threshold) || threshold <= 0 || threshold > 8 {
+			return errors.New("emit: entropy threshold must be between 0 and 8 bits")
+		}
+		d := &entropyDetector{
+			threshold: threshold,
+			minLength: defaultEntropyMinLength,
+			maxLength: defaultEntropyMaxLength,
+		}
+		for _, opt := range opts {
+			opt(d)
+		}
+		if d.minLength < 1 || d.maxLength < d.minLength {
+			return errors.New("emit: invalid entropy length range")
+		}
+		l.entropy = d
+		return nil
+	}
+}
+
+// EntropyLength sets the range of value lengths, in bytes, that are checked
+func EntropyLength(minLength, maxLength int) EntropyOption {
+	return func(d *entropyDetector) {
+		d.minLength = minLength
+		d.maxLength = maxLength
+	}
+}
+
+// matchesEntropy reports whether a value looks like a random secret
+func (l *Logger) matchesEntropy(value any) bool {
+	if l.entropy == nil || l.sensitiveMode == SHOW_SENSITIVE {
+		return false
+	}
+	s, ok := value.(string)
+	if !ok || len(s) < l.entropy.minLength || len(s) > l.entropy.maxLength {
+		return false
+	}
+	return shannonEntropy(s) > l.entropy.threshold
+}
+
+// shannonEntropy returns the entropy of s in bits per byte, or zero when s
+// contains whitespace, which generated secrets do not
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case ' ', '\t', '\n', '\r':
+			return 0
+		default:
+			counts[c]++
+		}
+	}
+
+	n := float64(len(s))
+	entropy := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
@@ -0,0 +1,196 @@
+package emit
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FromEnv creates a logger configured from EMIT_* environment variables:
+//
+//	EMIT_LEVEL            debug, info, warn, error or a registered level
+//	EMIT_FORMAT           json, plain, logfmt, console, ecs or gcp
+//	EMIT_SHOW_CALLER      true or false
+//	EMIT_MASK_SENSITIVE   mask, show, hash or drop (true and false also work)
+//	EMIT_MASK_PII         mask, show or drop (true and false also work)
+//	EMIT_MASK_STRING      replacement for sensitive values
+//	EMIT_PII_MASK_STRING  replacement for PII values
+//	EMIT_HASH_SALT        salt for hashed sensitive values
+//	EMIT_TIME_FORMAT      rfc3339, rfc3339nano, epoch_millis or a time layout
+//	EMIT_TIME_KEY         key the timestamp is written under
+//	EMIT_UTC              true or false
+//	EMIT_COMPONENT        component name
+//	EMIT_VERSION          component version
+//
+// Unset or empty variables keep the defaults; a malformed value is an error
+// naming the variable. The given options are applied first and variables
+// that are set override them, so code sets the defaults and operators can
+// change them without a rebuild:
+//
+//	logger, err := emit.FromEnv(emit.WithComponent("checkout"), emit.WithLevel(emit.INFO))
+func FromEnv(opts ...Option) (*Logger, error) {
+	envOpts, err := envOptions(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	return New(slices.Concat(opts, envOpts)...)
+}
+
+// envOptions translates the EMIT_* variables into options
+func envOptions(lookup func(string) (string, bool)) ([]Option, error) {
+	var opts []Option
+	get := func(name string) (string, bool) {
+		value, ok := lookup(name)
+		value = strings.TrimSpace(value)
+		return value, ok && value != ""
+	}
+
+	if value, ok := get("EMIT_LEVEL"); ok {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, envError("EMIT_LEVEL", value)
+		}
+		opts = append(opts, WithLevel(level))
+	}
+
+	if value, ok := get("EMIT_FORMAT"); ok {
+		format, ok := parseOutputFormat(value)
+		if !ok {
+			return nil, envError("EMIT_FORMAT", value)
+		}
+		opts = append(opts, WithFormat(format))
+	}
+
+	if value, ok := get("EMIT_SHOW_CALLER"); ok {
+		show, ok := parseEnvBool(value)
+		if !ok {
+			return nil, envError("EMIT_SHOW_CALLER", value)
+		}
+		opts = append(opts, func(l *Logger) error {
+			l.showCaller = show
+			return nil
+		})
+	}
+
+	if value, ok := get("EMIT_MASK_SENSITIVE"); ok {
+		var mode SensitiveDataMode
+		switch strings.ToLower(value) {
+		case "true", "1", "yes", "on", "mask":
+			mode = MASK_SENSITIVE
+		case "false", "0", "no", "off", "show":
+			mode = SHOW_SENSITIVE
+		case "hash":
+			mode = HASH_SENSITIVE
+		case "drop":
+			mode = DROP_SENSITIVE
+		default:
+			return nil, envError("EMIT_MASK_SENSITIVE", value)
+		}
+		opts = append(opts, WithSensitiveMode(mode))
+	}
+
+	if value, ok := get("EMIT_MASK_PII"); ok {
+		var mode PIIDataMode
+		switch strings.ToLower(value) {
+		case "true", "1", "yes", "on", "mask":
+			mode = MASK_PII
+		case "false", "0", "no", "off", "show":
+			mode = SHOW_PII
+		case "drop":
+			mode = DROP_PII
+		default:
+			return nil, envError("EMIT_MASK_PII", value)
+		}
+		opts = append(opts, WithPIIMode(mode))
+	}
+
+	if value, ok := get("EMIT_MASK_STRING"); ok {
+		opts = append(opts, WithMaskString(value))
+	}
+	if value, ok := get("EMIT_PII_MASK_STRING"); ok {
+		opts = append(opts, WithPIIMaskString(value))
+	}
+	if value, ok := get("EMIT_HASH_SALT"); ok {
+		opts = append(opts, WithHashSalt(value))
+	}
+
+	if value, ok := get("EMIT_TIME_FORMAT"); ok {
+		layout, ok := parseTimeLayout(value)
+		if !ok {
+			return nil, envError("EMIT_TIME_FORMAT", value)
+		}
+		opts = append(opts, WithTimeFormat(layout))
+	}
+	if value, ok := get("EMIT_TIME_KEY"); ok {
+		opts = append(opts, WithTimeKey(value))
+	}
+	if value, ok := get("EMIT_UTC"); ok {
+		utc, ok := parseEnvBool(value)
+		if !ok {
+			return nil, envError("EMIT_UTC", value)
+		}
+		opts = append(opts, func(l *Logger) error {
+			l.timeUTC = utc
+			return nil
+		})
+	}
+
+	if value, ok := get("EMIT_COMPONENT"); ok {
+		opts = append(opts, WithComponent(value))
+	}
+	if value, ok := get("EMIT_VERSION"); ok {
+		opts = append(opts, WithVersion(value))
+	}
+	return opts, nil
+}
+
+func envError(name, value string) error {
+	return fmt.Errorf("emit: invalid %s value %q", name, value)
+}
+
+// parseOutputFormat maps a format name to its OutputFormat
+func parseOutputFormat(name string) (OutputFormat, bool) {
+	switch strings.ToLower(name) {
+	case "json", "production", "prod":
+		return JSON_FORMAT, true
+	case "plain", "text":
+		return PLAIN_FORMAT, true
+	case "logfmt":
+		return LOGFMT_FORMAT, true
+	case "console", "development", "dev":
+		return CONSOLE_FORMAT, true
+	case "ecs":
+		return ECS_FORMAT, true
+	case "gcp":
+		return GCP_FORMAT, true
+	}
+	return 0, false
+}
+
+// parseEnvBool accepts the boolean spellings used by the EMIT_* variables
+func parseEnvBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "on":
+		return true, true
+	case "false", "0", "no", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// parseTimeLayout maps a time format name to its layout. Other values must be
+// time.Format layouts, which change when a time is formatted with them.
+func parseTimeLayout(value string) (string, bool) {
+	switch strings.ToLower(value) {
+	case "rfc3339":
+		return time.RFC3339, true
+	case "rfc3339nano":
+		return time.RFC3339Nano, true
+	case TimeFormatEpochMillis:
+		return TimeFormatEpochMillis, true
+	}
+	sample := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	return value, sample.Format(value) != value
+}
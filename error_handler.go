@@ -0,0 +1,51 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrAsyncOverflow is reported when a WithAsync queue is full and an entry
+// is dropped
+var ErrAsyncOverflow = errors.New("emit: async queue full, entry dropped")
+
+// ErrWriteTimeout is reported when a write does not finish within the
+// WithWriteTimeout deadline, or the writer is still blocked, and an entry is
+// dropped
+var ErrWriteTimeout = errors.New("emit: write timed out, entry dropped")
+
+// WithErrorHandler sets the function called when the logger itself fails:
+// a sink write error such as a full disk or broken pipe, an entry that
+// cannot be encoded, an entry dropped by a full async queue (ErrAsyncOverflow)
+// or by a write timeout (ErrWriteTimeout), or a failing hook. Logging calls
+// never return or panic on these errors.
+// The handler may be called from the async worker goroutine and must not log
+// to the same logger. By default a one-line notice is written to stderr.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(l *Logger) error {
+		l.errorHandler = handler
+		return nil
+	}
+}
+
+// reportError passes an internal failure to the error handler
+func (l *Logger) reportError(err error) {
+	if l.errorHandler != nil {
+		l.errorHandler(err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
+
+// writeOutput writes a line to w, reporting a failed write
+func (l *Logger) writeOutput(w io.Writer, level LogLevel, line []byte) {
+	if l.writeGuard != nil {
+		l.writeGuard.write(l, w, level, line)
+		return
+	}
+	if err := writeLevel(w, level, line); err != nil {
+		l.reportError(fmt.Errorf("emit: write failed: %w", err))
+	}
+}
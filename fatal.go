@@ -0,0 +1,113 @@
+package emit
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// WithExitFunc replaces os.Exit as the function Fatal calls with exit code 1,
+// so tests can exercise fatal paths without ending the test binary:
+//
+//	logger, _ := emit.New(emit.WithExitFunc(func(code int) { exited = code }))
+//
+// If the function returns, so does Fatal.
+func WithExitFunc(exit func(code int)) Option {
+	return func(l *Logger) error {
+		if exit == nil {
+			return errors.New("emit: exit function must not be nil")
+		}
+		l.exitFunc = exit
+		return nil
+	}
+}
+
+// WithFatalHook adds a function that Fatal runs after writing its entry and
+// before flushing and exiting, such as closing a database or reporting to an
+// error tracker. Hooks run in the order they were added.
+func WithFatalHook(hook func()) Option {
+	return func(l *Logger) error {
+		if hook == nil {
+			return errors.New("emit: fatal hook must not be nil")
+		}
+		l.fatalHooks = append(l.fatalHooks, hook)
+		return nil
+	}
+}
+
+// Fatal logs a message at FATAL level, then runs the fatal hooks, flushes the
+// logger as Sync does and exits with code 1. The exit happens even when
+// FATAL is filtered out; deferred functions do not run.
+func (l *Logger) Fatal(message string, fields ...any) {
+	l.logContext(context.Background(), FATAL, message, fields...)
+	l.exit()
+}
+
+// FatalContext logs a fatal message with fields from ctx, then exits as Fatal does
+func (l *Logger) FatalContext(ctx context.Context, message string, fields ...any) {
+	l.logContext(ctx, FATAL, message, fields...)
+	l.exit()
+}
+
+// Fatalf logs a formatted fatal message, then exits as Fatal does
+func (l *Logger) Fatalf(format string, args ...any) {
+	if l.Enabled(FATAL) {
+		message, fields := splitFormatArgs(format, args)
+		l.logContext(context.Background(), FATAL, message, fields...)
+	}
+	l.exit()
+}
+
+// Fatal logs a fatal message on the default logger, then exits
+func Fatal(message string, fields ...any) {
+	logger := Default()
+	if logger == nil {
+		os.Exit(1)
+	}
+	logger.logContext(context.Background(), FATAL, message, fields...)
+	logger.exit()
+}
+
+// FatalContext logs a fatal message on the default logger with fields from ctx, then exits
+func FatalContext(ctx context.Context, message string, fields ...any) {
+	logger := Default()
+	if logger == nil {
+		os.Exit(1)
+	}
+	logger.logContext(ctx, FATAL, message, fields...)
+	logger.exit()
+}
+
+// Fatalf logs a formatted fatal message on the default logger, then exits
+func Fatalf(format string, args ...any) {
+	logger := Default()
+	if logger == nil {
+		os.Exit(1)
+	}
+	if logger.Enabled(FATAL) {
+		message, fields := splitFormatArgs(format, args)
+		logger.logContext(context.Background(), FATAL, message, fields...)
+	}
+	logger.exit()
+}
+
+// exit runs the fatal hooks, flushes and exits. A tee runs the hooks of
+// every logger and uses the exit function of the first.
+func (l *Logger) exit() {
+	loggers := []*Logger{l}
+	if l.tee != nil {
+		loggers = l.tee
+	}
+	exit := os.Exit
+	for i, logger := range loggers {
+		logger = logger.resolve()
+		for _, hook := range logger.fatalHooks {
+			hook()
+		}
+		if i == 0 && logger.exitFunc != nil {
+			exit = logger.exitFunc
+		}
+	}
+	_ = l.Sync()
+	exit(1)
+}
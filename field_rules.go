@@ -0,0 +1,264 @@
+package emit
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// loggerFieldRules holds per-logger detection maps that override the global ones.
+// A nil map falls back to the corresponding global map, so a logger can override
+// only PII or only sensitive detection.
+type loggerFieldRules struct {
+	mu              sync.RWMutex
+	piiFields       map[string]bool
+	sensitiveFields map[string]bool
+	piiCache        map[string]bool
+	sensitiveCache  map[string]bool
+	exemptFields    map[string]bool
+}
+
+// newLoggerFieldRules creates an empty rule set that defers to the global maps
+func newLoggerFieldRules() *loggerFieldRules {
+	return &loggerFieldRules{
+		piiCache:       make(map[string]bool, 100),
+		sensitiveCache: make(map[string]bool, 100),
+	}
+}
+
+// isPII checks a field against the logger's own PII map; handled is false when
+// the logger has no PII override and the global map should be used instead
+func (r *loggerFieldRules) isPII(fieldName string) (isPII bool, handled bool) {
+	r.mu.RLock()
+	if r.piiFields == nil {
+		r.mu.RUnlock()
+		return false, false
+	}
+	if cached, exists := r.piiCache[fieldName]; exists {
+		r.mu.RUnlock()
+		return cached, true
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.piiFields == nil {
+		return false, false
+	}
+	isPII = matchPIIPattern(r.piiFields, fieldName)
+	r.piiCache[fieldName] = isPII
+	return isPII, true
+}
+
+// isSensitive checks a field against the logger's own sensitive map; handled is
+// false when the logger has no sensitive override
+func (r *loggerFieldRules) isSensitive(fieldName string) (isSensitive bool, handled bool) {
+	r.mu.RLock()
+	if r.sensitiveFields == nil {
+		r.mu.RUnlock()
+		return false, false
+	}
+	if cached, exists := r.sensitiveCache[fieldName]; exists {
+		r.mu.RUnlock()
+		return cached, true
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sensitiveFields == nil {
+		return false, false
+	}
+	isSensitive = matchSensitivePattern(r.sensitiveFields, foldFieldName(fieldName))
+	r.sensitiveCache[fieldName] = isSensitive
+	return isSensitive, true
+}
+
+// update replaces one of the per-logger maps and drops the cached results
+func (r *loggerFieldRules) update(pii bool, fieldMap map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pii {
+		r.piiFields = fieldMap
+	} else {
+		r.sensitiveFields = fieldMap
+	}
+
+	r.piiCache = make(map[string]bool, 100)
+	r.sensitiveCache = make(map[string]bool, 100)
+}
+
+// reset drops every per-logger map, so the logger defers to the global ones
+func (r *loggerFieldRules) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.piiFields = nil
+	r.sensitiveFields = nil
+	r.exemptFields = nil
+	r.piiCache = make(map[string]bool, 100)
+	r.sensitiveCache = make(map[string]bool, 100)
+}
+
+// modify adds or removes patterns in a per-logger map, starting from the global
+// patterns when the logger does not yet have its own map
+func (r *loggerFieldRules) modify(pii bool, patterns []string, add bool) {
+	r.mu.RLock()
+	current := r.sensitiveFields
+	if pii {
+		current = r.piiFields
+	}
+	r.mu.RUnlock()
+
+	if current == nil {
+		initializeFieldMaps()
+		fieldMapsMu.RLock()
+		current = sensitiveFieldsMap
+		if pii {
+			current = piiFieldsMap
+		}
+		fieldMapsMu.RUnlock()
+	}
+
+	modified := make(map[string]bool, len(current)+len(patterns)*2)
+	for pattern := range current {
+		modified[pattern] = true
+	}
+	for _, pattern := range patterns {
+		pattern = foldFieldName(pattern)
+		if pattern == "" {
+			continue
+		}
+		if add {
+			modified[pattern] = true
+			modified[strings.ToUpper(pattern)] = true
+		} else {
+			delete(modified, pattern)
+			delete(modified, strings.ToUpper(pattern))
+		}
+	}
+
+	r.update(pii, modified)
+}
+
+// hasOverride reports whether a per-logger map is set for the category
+func (r *loggerFieldRules) hasOverride(pii bool) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if pii {
+		return r.piiFields != nil
+	}
+	return r.sensitiveFields != nil
+}
+
+// rules returns the logger's rule set, creating it on first use
+func (l *Logger) rules() *loggerFieldRules {
+	registryInitMu.Lock()
+	defer registryInitMu.Unlock()
+
+	if l.fieldRules == nil {
+		l.fieldRules = newLoggerFieldRules()
+	}
+	return l.fieldRules
+}
+
+// SetSensitiveFields replaces the sensitive field patterns used by this logger.
+// Passing nil makes the logger fall back to the global sensitive patterns.
+func (l *Logger) SetSensitiveFields(fields []string) {
+	lowerFields := lowerFieldNames(fields)
+	l.updateFieldList(false, func([]string) []string { return lowerFields })
+
+	if fields == nil {
+		l.rules().update(false, nil)
+		return
+	}
+	l.rules().update(false, buildFieldMap(lowerFields))
+}
+
+// SetPIIFields replaces the PII field patterns used by this logger.
+// Passing nil makes the logger fall back to the global PII patterns.
+func (l *Logger) SetPIIFields(fields []string) {
+	lowerFields := lowerFieldNames(fields)
+	l.updateFieldList(true, func([]string) []string { return lowerFields })
+
+	if fields == nil {
+		l.rules().update(true, nil)
+		return
+	}
+	l.rules().update(true, buildFieldMap(lowerFields))
+}
+
+// AddSensitiveField adds sensitive field patterns to this logger only
+func (l *Logger) AddSensitiveField(fields ...string) {
+	l.addFieldNames(false, fields)
+	l.rules().modify(false, fields, true)
+}
+
+// RemoveSensitiveField removes sensitive field patterns from this logger only
+func (l *Logger) RemoveSensitiveField(fields ...string) {
+	l.removeFieldNames(false, fields)
+	l.rules().modify(false, fields, false)
+}
+
+// AddPIIField adds PII field patterns to this logger only
+func (l *Logger) AddPIIField(fields ...string) {
+	l.addFieldNames(true, fields)
+	l.rules().modify(true, fields, true)
+}
+
+// RemovePIIField removes PII field patterns from this logger only
+func (l *Logger) RemovePIIField(fields ...string) {
+	l.removeFieldNames(true, fields)
+	l.rules().modify(true, fields, false)
+}
+
+// fieldListsMu guards the sensitiveFields and piiFields lists of every logger
+var fieldListsMu sync.Mutex
+
+// updateFieldList replaces the logger's PII or sensitive list with the result
+// of update. update must return a new slice, as child loggers share the old one.
+func (l *Logger) updateFieldList(pii bool, update func([]string) []string) {
+	fieldListsMu.Lock()
+	defer fieldListsMu.Unlock()
+
+	if pii {
+		l.piiFields = update(l.piiFields)
+	} else {
+		l.sensitiveFields = update(l.sensitiveFields)
+	}
+}
+
+// addFieldNames appends case-folded names to a copy of the logger's list
+func (l *Logger) addFieldNames(pii bool, fields []string) {
+	l.updateFieldList(pii, func(list []string) []string {
+		return slices.Concat(list, lowerFieldNames(fields))
+	})
+}
+
+// removeFieldNames removes names from a copy of the logger's list
+func (l *Logger) removeFieldNames(pii bool, fields []string) {
+	l.updateFieldList(pii, func(list []string) []string {
+		return removeFieldNames(list, fields)
+	})
+}
+
+// overridden reports whether the logger has its own PII or sensitive map
+func (r *loggerFieldRules) overridden() bool {
+	if r == nil {
+		return false
+	}
+	return r.hasOverride(true) || r.hasOverride(false)
+}
+
+// lowerFieldNames returns case-folded copies of field names
+func lowerFieldNames(fields []string) []string {
+	var lowerFields []string
+	for _, field := range fields {
+		lowerFields = append(lowerFields, foldFieldName(field))
+	}
+	return lowerFields
+}
@@ -0,0 +1,307 @@
+package emit
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Size units for WithMaxSize
+const (
+	KB int64 = 1024
+	MB       = 1024 * KB
+	GB       = 1024 * MB
+)
+
+// backupTimeFormat is embedded in rotated file names and sorts chronologically
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// FileOption configures a RotatingFile created with FileSink
+type FileOption func(*RotatingFile)
+
+// RotatingFile is an io.Writer that appends to a file and rotates it once it
+// grows past a size limit. Rotated backups are kept next to the file as
+// name-<timestamp>.ext (optionally gzipped) and pruned by count and age.
+// It is safe for concurrent use.
+type RotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	lastRotation time.Time
+	millCh       chan struct{}
+	wg           sync.WaitGroup
+	closed       bool
+}
+
+// FileSink opens (or creates) path for appending and returns a rotating writer
+// for use with WithOutput or as a Sink
+func FileSink(path string, opts ...FileOption) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:    path,
+		maxSize: 100 * MB,
+		millCh:  make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.maxSize <= 0 {
+		return nil, errors.New("emit: file sink max size must be positive")
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	f.wg.Add(1)
+	go f.mill()
+	f.millCh <- struct{}{}
+	return f, nil
+}
+
+// WithMaxSize rotates the file once writing would exceed size bytes (default 100MB)
+func WithMaxSize(size int64) FileOption {
+	return func(f *RotatingFile) {
+		f.maxSize = size
+	}
+}
+
+// WithMaxAge removes backups older than age; zero keeps them regardless of age
+func WithMaxAge(age time.Duration) FileOption {
+	return func(f *RotatingFile) {
+		f.maxAge = age
+	}
+}
+
+// WithMaxBackups keeps at most n backups; zero keeps all of them
+func WithMaxBackups(n int) FileOption {
+	return func(f *RotatingFile) {
+		f.maxBackups = n
+	}
+}
+
+// WithCompress gzips backups after rotation
+func WithCompress() FileOption {
+	return func(f *RotatingFile) {
+		f.compress = true
+	}
+}
+
+// open opens the current file, creating its directory if needed
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would push the file past the size limit
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	// A failed rotation leaves no file open; retry opening it
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file to a timestamped backup and opens a new one.
+// If the rename fails it reopens the current file, so writes keep appending.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return err
+	}
+	// Keep backup names unique when rotating more than once per millisecond
+	now := time.Now()
+	if !now.After(f.lastRotation.Add(time.Millisecond)) {
+		now = f.lastRotation.Add(time.Millisecond)
+	}
+	f.lastRotation = now
+
+	if err := os.Rename(f.path, f.backupName(now)); err != nil {
+		return errors.Join(err, f.open())
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	select {
+	case f.millCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// backupName returns the backup path for a rotation at t
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, base := filepath.Split(f.path)
+	ext := filepath.Ext(base)
+	return filepath.Join(dir, strings.TrimSuffix(base, ext)+"-"+t.Format(backupTimeFormat)+ext)
+}
+
+// mill compresses and prunes backups in the background after each rotation
+func (f *RotatingFile) mill() {
+	defer f.wg.Done()
+	for range f.millCh {
+		_ = f.processBackups()
+	}
+}
+
+// processBackups compresses new backups and removes those beyond the limits
+func (f *RotatingFile) processBackups() error {
+	backups, err := f.listBackups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if f.compress {
+		for i, backup := range backups {
+			if strings.HasSuffix(backup, ".gz") {
+				continue
+			}
+			if err := gzipFile(backup); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			backups[i] = backup + ".gz"
+		}
+	}
+
+	// Newest first, so the tail past maxBackups is the oldest
+	slices.Sort(backups)
+	slices.Reverse(backups)
+	for i, backup := range backups {
+		remove := f.maxBackups > 0 && i >= f.maxBackups
+		if !remove && f.maxAge > 0 {
+			if info, err := os.Stat(backup); err == nil && time.Since(info.ModTime()) > f.maxAge {
+				remove = true
+			}
+		}
+		if remove {
+			errs = append(errs, os.Remove(backup))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listBackups returns the rotated files belonging to this sink
+func (f *RotatingFile) listBackups() ([]string, error) {
+	dir, base := filepath.Split(f.path)
+	if dir == "" {
+		dir = "."
+	}
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ext)
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(stamp, prefix)); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, name))
+	}
+	return backups, nil
+}
+
+// gzipFile compresses path to path.gz and removes the original
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	err = errors.Join(err, zw.Close(), dst.Close())
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Sync commits the current file to stable storage
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed || f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close flushes and closes the file and waits for background compression.
+// It is safe to call more than once.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	var err error
+	if f.file != nil {
+		err = errors.Join(f.file.Sync(), f.file.Close())
+	}
+	close(f.millCh)
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
@@ -0,0 +1,81 @@
+package emit
+
+import (
+	"errors"
+	"strconv"
+)
+
+// WithFlatten writes nested field maps as flat keys joined by separator, for
+// backends that index flat keys better:
+//
+//	emit.WithFlatten(".")
+//	logger.Info("signup", "user", map[string]any{"address": map[string]any{"city": "Oslo"}})
+//	// {"fields":{"user.address.city":"Oslo"}}
+//
+// Masking runs first, on the nested maps, so nested sensitive keys are still
+// detected. Slices are kept as they are unless WithFlattenSlices is also set.
+func WithFlatten(separator string) Option {
+	return func(l *Logger) error {
+		if separator == "" {
+			return errors.New("emit: flatten separator must not be empty")
+		}
+		l.flattenSeparator = separator
+		return nil
+	}
+}
+
+// WithFlattenSlices also flattens slices under WithFlatten, using element
+// indices as keys: "items.0.sku", "items.1.sku"
+func WithFlattenSlices() Option {
+	return func(l *Logger) error {
+		l.flattenSlices = true
+		return nil
+	}
+}
+
+// flattenFields returns fields with nested maps joined into flat keys
+func (l *Logger) flattenFields(fields map[string]any) map[string]any {
+	flat := make(map[string]any, len(fields))
+	for key, value := range fields {
+		l.flattenInto(flat, key, value, 0)
+	}
+	return flat
+}
+
+func (l *Logger) flattenInto(flat map[string]any, key string, value any, depth int) {
+	if depth > jsonSafeMaxDepth {
+		flat[key] = value
+		return
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) > 0 {
+			for nestedKey, nested := range v {
+				l.flattenInto(flat, key+l.flattenSeparator+nestedKey, nested, depth+1)
+			}
+			return
+		}
+	case Fields:
+		if len(v) > 0 {
+			for nestedKey, nested := range v {
+				l.flattenInto(flat, key+l.flattenSeparator+nestedKey, nested, depth+1)
+			}
+			return
+		}
+	case []any:
+		if l.flattenSlices && len(v) > 0 {
+			for i, element := range v {
+				l.flattenInto(flat, key+l.flattenSeparator+strconv.Itoa(i), element, depth+1)
+			}
+			return
+		}
+	case []map[string]any:
+		if l.flattenSlices && len(v) > 0 {
+			for i, element := range v {
+				l.flattenInto(flat, key+l.flattenSeparator+strconv.Itoa(i), element, depth+1)
+			}
+			return
+		}
+	}
+	flat[key] = value
+}
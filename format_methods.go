@@ -0,0 +1,111 @@
+package emit
+
+import (
+	"context"
+	"fmt"
+)
+
+// The *f methods format the message with fmt.Sprintf. Trailing arguments that
+// are Fields, map[string]any or ZField values are logged as fields instead
+// of being passed to Sprintf:
+//
+//	logger.Infof("user %s logged in", name, emit.Fields{"ip": ip})
+//
+// Masking only applies to fields. Values interpolated into the message are
+// written verbatim, so pass passwords, tokens and personal data as fields,
+// never as format arguments.
+
+// Infof logs a formatted info message
+func (l *Logger) Infof(format string, args ...any) {
+	if !l.Enabled(INFO) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	l.logContext(context.Background(), INFO, message, fields...)
+}
+
+// Errorf logs a formatted error message
+func (l *Logger) Errorf(format string, args ...any) {
+	if !l.Enabled(ERROR) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	l.logContext(context.Background(), ERROR, message, fields...)
+}
+
+// Warnf logs a formatted warn message
+func (l *Logger) Warnf(format string, args ...any) {
+	if !l.Enabled(WARN) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	l.logContext(context.Background(), WARN, message, fields...)
+}
+
+// Debugf logs a formatted debug message
+func (l *Logger) Debugf(format string, args ...any) {
+	if !l.Enabled(DEBUG) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	l.logContext(context.Background(), DEBUG, message, fields...)
+}
+
+// Infof logs a formatted info message on the default logger
+func Infof(format string, args ...any) {
+	logger := Default()
+	if logger == nil || !logger.Enabled(INFO) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	logger.logContext(context.Background(), INFO, message, fields...)
+}
+
+// Errorf logs a formatted error message on the default logger
+func Errorf(format string, args ...any) {
+	logger := Default()
+	if logger == nil || !logger.Enabled(ERROR) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	logger.logContext(context.Background(), ERROR, message, fields...)
+}
+
+// Warnf logs a formatted warn message on the default logger
+func Warnf(format string, args ...any) {
+	logger := Default()
+	if logger == nil || !logger.Enabled(WARN) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	logger.logContext(context.Background(), WARN, message, fields...)
+}
+
+// Debugf logs a formatted debug message on the default logger
+func Debugf(format string, args ...any) {
+	logger := Default()
+	if logger == nil || !logger.Enabled(DEBUG) {
+		return
+	}
+	message, fields := splitFormatArgs(format, args)
+	logger.logContext(context.Background(), DEBUG, message, fields...)
+}
+
+// splitFormatArgs formats the message from the leading arguments and
+// returns the trailing field arguments
+func splitFormatArgs(format string, args []any) (string, []any) {
+	n := len(args)
+	for n > 0 && isFieldArg(args[n-1]) {
+		n--
+	}
+	return fmt.Sprintf(format, args[:n]...), args[n:]
+}
+
+// isFieldArg reports whether a *f argument carries fields rather than a format value
+func isFieldArg(arg any) bool {
+	switch arg.(type) {
+	case Fields, map[string]any, ZField:
+		return true
+	}
+	return false
+}
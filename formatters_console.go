@@ -0,0 +1,180 @@
+package emit
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// colorMode controls ANSI colors in the console format
+type colorMode int
+
+const (
+	colorAuto colorMode = iota // Color only when the writer is a terminal
+	colorOn
+	colorOff
+)
+
+// ANSI escape codes used by the console format
+const (
+	ansiReset  = "\033[0m"
+	ansiDim    = "\033[90m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiBlue   = "\033[34m"
+	ansiCyan   = "\033[36m"
+)
+
+// WithColor forces ANSI colors in the console format on or off instead of
+// detecting whether the output is a terminal
+func WithColor(enabled bool) Option {
+	return func(l *Logger) error {
+		if enabled {
+			l.colorMode = colorOn
+		} else {
+			l.colorMode = colorOff
+		}
+		return nil
+	}
+}
+
+// useColor reports whether console output should include ANSI colors.
+// Auto-detection honors NO_COLOR and treats character devices as terminals,
+// which avoids a dependency on golang.org/x/term.
+func (l *Logger) useColor() bool {
+	switch l.colorMode {
+	case colorOn:
+		return true
+	case colorOff:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(l.writer)
+}
+
+// isTerminal reports whether the writer is a file attached to a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// levelColor returns the ANSI color used for a level
+func levelColor(level LogLevel) string {
+	switch level {
+	case DEBUG:
+		return ansiBlue
+	case INFO:
+		return ansiGreen
+	case WARN:
+		return ansiYellow
+	case ERROR, PANIC, FATAL:
+		return ansiRed
+	default:
+		return ""
+	}
+}
+
+// logConsole writes a human-friendly entry for local development:
+// timestamp, level, component, message, then sorted key=value pairs
+func (l *Logger) logConsole(level LogLevel, message string, fields map[string]any) {
+	color := l.useColor()
+
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	if !l.omitsTimestamp() {
+		if color {
+			buf.WriteString(ansiDim)
+		}
+		ts, _ := l.formatTimestamp()
+		buf.WriteString(ts)
+		if color {
+			buf.WriteString(ansiReset)
+		}
+		buf.WriteByte(' ')
+	}
+
+	levelStr, renamed := l.levelNames[level]
+	if !renamed {
+		levelStr = strings.ToUpper(level.String())
+	}
+	if color {
+		buf.WriteString(levelColor(level))
+	}
+	buf.WriteString(levelStr)
+	if color {
+		buf.WriteString(ansiReset)
+	}
+	for i := len(levelStr); i < 5; i++ {
+		buf.WriteByte(' ')
+	}
+	buf.WriteByte(' ')
+
+	if l.component != "" {
+		buf.WriteByte('[')
+		buf.WriteString(l.component)
+		if l.version != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(l.version)
+		}
+		buf.WriteString("] ")
+	}
+
+	buf.WriteString(message)
+
+	if l.hasFields(fields) {
+		flat := make(map[string]any, len(fields))
+		flattenFields(flat, "", l.maskSensitiveFieldsFast(fields), ".")
+
+		keys := make([]string, 0, len(flat))
+		for key := range flat {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			buf.WriteByte(' ')
+			if color {
+				buf.WriteString(ansiCyan)
+			}
+			writeLogfmtKey(buf, key)
+			buf.WriteByte('=')
+			if color {
+				buf.WriteString(ansiReset)
+			}
+			l.writeLogfmtValue(buf, flat[key])
+		}
+	}
+
+	if l.showCaller {
+		if _, file, line, ok := runtime.Caller(callerSkip + l.extraCallerSkip); ok {
+			buf.WriteByte(' ')
+			if color {
+				buf.WriteString(ansiDim)
+			}
+			buf.WriteString(file)
+			buf.WriteByte(':')
+			buf.WriteString(strconv.Itoa(line))
+			if color {
+				buf.WriteString(ansiReset)
+			}
+		}
+	}
+
+	buf.WriteByte('\n')
+
+	l.writeLine(level, buf.Bytes())
+}
@@ -0,0 +1,86 @@
+package emit
+
+import (
+	"encoding/json"
+	"runtime"
+)
+
+// ecsVersion is the Elastic Common Schema version entries conform to
+const ecsVersion = "8.11.0"
+
+// ecsFieldNames maps well-known field keys to their ECS names. Other fields
+// are written under "labels".
+var ecsFieldNames = map[string]string{
+	"trace_id":       "trace.id",
+	"span_id":        "span.id",
+	"transaction_id": "transaction.id",
+	"error":          "error.message",
+	stackTraceKey:    "error.stack_trace",
+	loggerNameKey:    "log.logger",
+	"user_id":        "user.id",
+	"client_ip":      "client.ip",
+	"http_method":    "http.request.method",
+	"status_code":    "http.response.status_code",
+	"url":            "url.full",
+	"duration_ns":    "event.duration",
+	"host":           "host.name",
+	"hostname":       "host.name",
+}
+
+// logECS writes an entry following Elastic Common Schema conventions.
+// Fields are masked before they are mapped.
+func (l *Logger) logECS(level LogLevel, message string, fields map[string]any) {
+	entry := make(map[string]any, 8)
+
+	if !l.omitsTimestamp() {
+		ts, numeric := l.formatTimestamp()
+		if numeric {
+			entry["@timestamp"] = json.Number(ts)
+		} else {
+			entry["@timestamp"] = ts
+		}
+	}
+	entry["log.level"] = l.levelName(level)
+	entry["message"] = message
+	entry["ecs.version"] = ecsVersion
+
+	if l.component != "" {
+		entry["service.name"] = l.component
+	}
+	if l.version != "" {
+		entry["service.version"] = l.version
+	}
+
+	if l.showCaller {
+		if pc, file, line, ok := runtime.Caller(callerSkip + l.extraCallerSkip); ok {
+			entry["log.origin.file.name"] = file
+			entry["log.origin.file.line"] = line
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				entry["log.origin.function"] = fn.Name()
+			}
+		}
+	}
+
+	if l.hasFields(fields) {
+		var labels map[string]any
+		for key, value := range l.maskSensitiveFieldsFast(fields) {
+			name, known := ecsFieldNames[key]
+			if !known {
+				if labels == nil {
+					labels = make(map[string]any, len(fields))
+				}
+				labels[key] = value
+				continue
+			}
+			if trace, ok := value.(StackTrace); ok {
+				value = trace.String()
+			}
+			entry[name] = value
+		}
+		if labels != nil {
+			entry["labels"] = labels
+		}
+	}
+
+	l.writeJSONEntry(level, entry)
+}
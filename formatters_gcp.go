@@ -0,0 +1,110 @@
+package emit
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+)
+
+// Special keys recognized by Google Cloud Logging in structured stdout logs
+const (
+	gcpTraceKey          = "logging.googleapis.com/trace"
+	gcpSpanKey           = "logging.googleapis.com/spanId"
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+)
+
+// WithGCPProject sets the project used to build fully qualified trace names
+// ("projects/<id>/traces/<trace_id>") in FormatGCP. It defaults to the
+// GOOGLE_CLOUD_PROJECT environment variable.
+func WithGCPProject(projectID string) Option {
+	return func(l *Logger) error {
+		l.gcpProjectID = projectID
+		return nil
+	}
+}
+
+// gcpSeverity maps a level to a Cloud Logging severity. Custom levels take
+// the severity of the range they fall in, with NOTICE between INFO and WARN.
+func gcpSeverity(level LogLevel) string {
+	switch {
+	case level > ERROR:
+		return "CRITICAL"
+	case level == ERROR:
+		return "ERROR"
+	case level >= WARN:
+		return "WARNING"
+	case level > INFO:
+		return "NOTICE"
+	case level == INFO:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// logGCP writes an entry in the structured format Cloud Run and GKE parse
+// from stdout. Fields are masked and written at the top level, where they end
+// up in jsonPayload; trace_id and span_id become the trace correlation keys.
+func (l *Logger) logGCP(level LogLevel, message string, fields map[string]any) {
+	entry := make(map[string]any, len(fields)+6)
+
+	for key, value := range l.maskSensitiveFieldsFast(fields) {
+		switch key {
+		case "trace_id":
+			if traceID, ok := value.(string); ok && traceID != "" {
+				entry[gcpTraceKey] = l.gcpTraceName(traceID)
+				continue
+			}
+		case "span_id":
+			if spanID, ok := value.(string); ok && spanID != "" {
+				entry[gcpSpanKey] = spanID
+				continue
+			}
+		}
+		entry[key] = value
+	}
+
+	entry["severity"] = gcpSeverity(level)
+	entry["message"] = message
+
+	timeKey := "time"
+	if l.timeKey != "" {
+		timeKey = l.timeKey
+	}
+	if !l.omitsTimestamp() {
+		ts, numeric := l.formatTimestamp()
+		if numeric {
+			entry[timeKey] = json.Number(ts)
+		} else {
+			entry[timeKey] = ts
+		}
+	}
+
+	if l.component != "" || l.version != "" {
+		entry["serviceContext"] = map[string]string{"service": l.component, "version": l.version}
+	}
+
+	if l.showCaller {
+		if pc, file, line, ok := runtime.Caller(callerSkip + l.extraCallerSkip); ok {
+			location := map[string]any{"file": file, "line": line}
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				location["function"] = fn.Name()
+			}
+			entry[gcpSourceLocationKey] = location
+		}
+	}
+
+	l.writeJSONEntry(level, entry)
+}
+
+// gcpTraceName qualifies a trace ID with the project when one is known
+func (l *Logger) gcpTraceName(traceID string) string {
+	project := l.gcpProjectID
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if project == "" {
+		return traceID
+	}
+	return "projects/" + project + "/traces/" + traceID
+}
@@ -0,0 +1,174 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// logLogfmt writes a logfmt formatted log entry (key=value pairs separated by spaces)
+func (l *Logger) logLogfmt(level LogLevel, message string, fields map[string]any) {
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	if !l.omitsTimestamp() {
+		ts, _ := l.formatTimestamp()
+		writeLogfmtKey(buf, l.timestampKey())
+		buf.WriteByte('=')
+		writeLogfmtString(buf, ts)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("level=")
+	writeLogfmtString(buf, l.levelName(level))
+	buf.WriteString(" msg=")
+	writeLogfmtString(buf, message)
+
+	if l.component != "" {
+		buf.WriteString(" component=")
+		writeLogfmtString(buf, l.component)
+	}
+
+	if l.version != "" {
+		buf.WriteString(" version=")
+		writeLogfmtString(buf, l.version)
+	}
+
+	if l.showCaller {
+		if pc, file, line, ok := runtime.Caller(callerSkip + l.extraCallerSkip); ok {
+			buf.WriteString(" file=")
+			writeLogfmtString(buf, file)
+			buf.WriteString(" line=")
+			buf.WriteString(strconv.Itoa(line))
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				buf.WriteString(" function=")
+				writeLogfmtString(buf, fn.Name())
+			}
+		}
+	}
+
+	if l.hasFields(fields) {
+		flat := make(map[string]any, len(fields))
+		flattenFields(flat, "", l.maskSensitiveFieldsFast(fields), ".")
+
+		keys := make([]string, 0, len(flat))
+		for key := range flat {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			buf.WriteByte(' ')
+			writeLogfmtKey(buf, key)
+			buf.WriteByte('=')
+			l.writeLogfmtValue(buf, flat[key])
+		}
+	}
+
+	buf.WriteByte('\n')
+
+	l.writeLine(level, buf.Bytes())
+}
+
+// flattenFields copies nested maps into dst using sep-joined keys (user.email)
+func flattenFields(dst map[string]any, prefix string, fields map[string]any, sep string) {
+	for key, value := range fields {
+		if prefix != "" {
+			key = prefix + sep + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			if len(v) > 0 {
+				flattenFields(dst, key, v, sep)
+				continue
+			}
+		case Fields:
+			if len(v) > 0 {
+				flattenFields(dst, key, v, sep)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// writeLogfmtKey writes a key, replacing characters logfmt does not allow in keys
+func writeLogfmtKey(buf *bytes.Buffer, key string) {
+	if key == "" {
+		buf.WriteByte('_')
+		return
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			buf.WriteByte('_')
+			continue
+		}
+		buf.WriteRune(r)
+	}
+}
+
+// writeLogfmtValue writes a field value in its logfmt representation
+func (l *Logger) writeLogfmtValue(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeLogfmtString(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case uint:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(v, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case time.Time:
+		buf.WriteString(l.formatTime(v))
+	case time.Duration:
+		if l.durationFormat == DurationNanos {
+			buf.WriteString(strconv.FormatInt(int64(v), 10))
+		} else {
+			buf.WriteString(v.String())
+		}
+	case error:
+		writeLogfmtString(buf, v.Error())
+	case fmt.Stringer:
+		writeLogfmtString(buf, v.String())
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			writeLogfmtString(buf, fmt.Sprint(v))
+			return
+		}
+		writeLogfmtString(buf, string(data))
+	}
+}
+
+// writeLogfmtString writes a value, quoting it when it is empty or contains
+// spaces, '=', quotes or control characters
+func writeLogfmtString(buf *bytes.Buffer, s string) {
+	if s == "" {
+		buf.WriteString(`""`)
+		return
+	}
+	if !strings.ContainsFunc(s, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f || r == utf8.RuneError
+	}) {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(strconv.Quote(s))
+}
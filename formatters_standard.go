@@ -1,17 +1,19 @@
 package emit
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // logJSON writes a JSON formatted log entry
 func (l *Logger) logJSON(level LogLevel, message string, fields map[string]any) {
 	entry := LogEntry{
 		Timestamp: GetUltraFastTimestamp(),
-		Level:     level.StringFast(),
+		Level:     l.levelName(level),
 		Message:   message,
 	}
 
@@ -23,12 +25,12 @@ func (l *Logger) logJSON(level LogLevel, message string, fields map[string]any)
 		entry.Version = l.version
 	}
 
-	if len(fields) > 0 {
+	if l.hasFields(fields) {
 		entry.Fields = l.maskSensitiveFieldsFast(fields)
 	}
 
 	if l.showCaller {
-		if pc, file, line, ok := runtime.Caller(4); ok {
+		if pc, file, line, ok := runtime.Caller(callerSkip + l.extraCallerSkip); ok {
 			entry.File = file
 			entry.Line = line
 			if fn := runtime.FuncForPC(pc); fn != nil {
@@ -37,15 +39,60 @@ func (l *Logger) logJSON(level LogLevel, message string, fields map[string]any)
 		}
 	}
 
-	data, err := json.Marshal(entry)
-	if err != nil {
-		// Fallback to simple format if JSON marshaling fails
-		_, _ = fmt.Fprintf(l.writer, `{"timestamp":"%s","level":"error","message":"Failed to marshal log entry: %v","component":"%s"}`+"\n",
-			GetUltraFastTimestamp(), err, l.component)
-		return
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	// Encode appends the trailing newline
+	if err := l.encodeJSON(buf, entry); err != nil {
+		// Retry with values encoding/json rejects replaced
+		buf.Reset()
+		entry.Fields = l.jsonSafeFields(entry.Fields)
+		if err := l.encodeJSON(buf, entry); err != nil {
+			// Fallback to simple format if JSON marshaling fails
+			l.reportError(fmt.Errorf("emit: encoding entry: %w", err))
+			buf.Reset()
+			l.writeEncodingFailure(buf, err)
+		}
+	}
+
+	l.writeLine(level, buf.Bytes())
+}
+
+// writeJSONEntry encodes an entry map (keys sorted) and writes it
+func (l *Logger) writeJSONEntry(level LogLevel, entry map[string]any) {
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	if err := l.encodeJSON(buf, entry); err != nil {
+		buf.Reset()
+		if err := l.encodeJSON(buf, l.jsonSafeFields(entry)); err != nil {
+			l.reportError(fmt.Errorf("emit: encoding entry: %w", err))
+			buf.Reset()
+			l.writeEncodingFailure(buf, err)
+		}
 	}
 
-	_, _ = fmt.Fprintln(l.writer, string(data))
+	l.writeLine(level, buf.Bytes())
+}
+
+// encodeJSON encodes v with encoding/json, escaping HTML only when
+// WithHTMLSafeJSON is set so the output matches the hand-built encoders
+func (l *Logger) encodeJSON(buf *bytes.Buffer, v any) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(l.escapeHTML)
+	return encoder.Encode(v)
+}
+
+// writeEncodingFailure writes the entry logged in place of one that could not
+// be encoded
+func (l *Logger) writeEncodingFailure(buf *bytes.Buffer, err error) {
+	buf.WriteString(`{"timestamp":"`)
+	buf.WriteString(GetUltraFastTimestamp())
+	buf.WriteString(`","level":"error","message":`)
+	writeJSONString(buf, "Failed to marshal log entry: "+err.Error(), l.escapeHTML)
+	buf.WriteString(`,"component":`)
+	writeJSONString(buf, l.component, l.escapeHTML)
+	buf.WriteString("}\n")
 }
 
 // logPlain writes a plain text formatted log entry
@@ -76,20 +123,39 @@ func (l *Logger) logPlain(level LogLevel, message string, fields map[string]any)
 
 	// Build the message with fields if present (with masking)
 	finalMessage := message
-	if len(fields) > 0 {
+	if l.hasFields(fields) {
 		maskedFields := l.maskSensitiveFieldsFast(fields)
 		var fieldParts []string
-		for k, v := range maskedFields {
-			fieldParts = append(fieldParts, fmt.Sprintf("%s=%v", k, v))
+		for k, v := range l.orderedFields(maskedFields) {
+			fieldParts = append(fieldParts, fmt.Sprintf("%s=%v", k, l.plainValue(v)))
 		}
 		finalMessage = fmt.Sprintf("%s [%s]", message, strings.Join(fieldParts, " "))
 	}
 
 	// Console output format:
 	// {UTC TIME} | {LOGGING LEVEL} | {COMPONENT} {VERSION}: {MESSAGE}
-	_, _ = fmt.Fprintf(l.writer, "%s | %s%-7s%s | %s %s: %s\n",
-		GetUltraFastTimestamp()[:19],
-		colorCode, severity, resetCode, l.component, l.version, finalMessage)
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	if !l.omitsTimestamp() {
+		ts, _ := l.formatTimestamp()
+		if l.timeFormat == "" {
+			ts = ts[:19]
+		}
+		buf.WriteString(ts)
+		buf.WriteString(" | ")
+	}
+
+	_, _ = fmt.Fprintf(buf, "%s%-7s%s | %s %s: %s\n",
+		colorCode, l.levelName(level), resetCode, l.component, l.version, finalMessage)
+
+	l.writeLine(level, buf.Bytes())
+}
+
+// jsonSafeMetadata reports whether the message, component and version can be
+// copied into hand-built JSON without escaping
+func (l *Logger) jsonSafeMetadata(message string) bool {
+	return jsonSafe(message, l.escapeHTML) && jsonSafe(l.component, l.escapeHTML) && jsonSafe(l.version, l.escapeHTML)
 }
 
 // buildSimpleJSONUltraFast - Ultra-fast JSON builder for simple messages
@@ -245,3 +311,16 @@ func (l *Logger) buildSimplePlainUltraFast(buf []byte, level LogLevel, message s
 
 	return pos
 }
+
+// plainValue formats times and durations like the other text encoders
+func (l *Logger) plainValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return l.formatTime(v)
+	case time.Duration:
+		if l.durationFormat == DurationNanos {
+			return int64(v)
+		}
+	}
+	return value
+}
@@ -5,32 +5,6 @@ import (
 	"sync"
 )
 
-// Fast JSON string escaping for structured fields
-func escapeJSONString(dst []byte, src string) int {
-	// For performance, handle the common case of no escaping needed
-	needsEscaping := false
-	for i := 0; i < len(src); i++ {
-		c := src[i]
-		if c == '"' || c == '\\' || c < 0x20 {
-			needsEscaping = true
-			break
-		}
-	}
-
-	if !needsEscaping {
-		// Fast path: no escaping needed
-		copy(dst, src)
-		return len(src)
-	}
-
-	// Slow path: escape the string using Go's built-in escaping
-	escaped := strconv.Quote(src)
-	// Remove the surrounding quotes that strconv.Quote adds
-	escaped = escaped[1 : len(escaped)-1]
-	copy(dst, escaped)
-	return len(escaped)
-}
-
 // Structured fields - thread-safe buffer pool for concurrent access
 var (
 	// Pre-computed level strings as byte slices for maximum performance
@@ -38,6 +12,8 @@ var (
 	infoLevelBytes  = []byte(`","level":"info","message":"`)
 	warnLevelBytes  = []byte(`","level":"warn","message":"`)
 	errorLevelBytes = []byte(`","level":"error","message":"`)
+	panicLevelBytes = []byte(`","level":"panic","message":"`)
+	fatalLevelBytes = []byte(`","level":"fatal","message":"`)
 
 	// Thread-safe buffer pool to prevent race conditions
 	bufferPool = sync.Pool{
@@ -51,14 +27,81 @@ var (
 	// Pre-allocated component and version buffers for ultra-fast access
 	componentPrefix = []byte(`,"component":"`)
 	versionPrefix   = []byte(`,"version":"`)
+	fieldsPrefix    = []byte(`,"fields":`)
 )
 
+// needsPipeline reports whether the logger's configuration rules out the
+// hand-built JSON hot path
+func (l *Logger) needsPipeline() bool {
+	return l.format != JSON_FORMAT || len(l.baseFields) > 0 || l.stackTraceEnabled ||
+		l.sampler != nil || l.traceSampler != nil || l.dedup != nil || l.customTimestamp() || l.caller != nil ||
+		len(l.defaultFields) > 0 || l.processesEntries() || l.strictMasking || l.customValueFormat() || l.customMasking() ||
+		l.schema != nil || l.source != "" || l.translator != nil || l.levelNames != nil || l.encoder != nil ||
+		l.sequence || len(l.groups) > 0
+}
+
+// customMasking reports whether masking differs from what the hot path
+// hardcodes: fixed key lists, the default mask strings, the MASK modes and
+// no observer
+func (l *Logger) customMasking() bool {
+	return l.maskString != defaultMaskString || l.piiMaskString != defaultPIIMaskString ||
+		l.sensitiveMode != MASK_SENSITIVE || l.piiMode != MASK_PII ||
+		l.partialMask.enabled() || l.piiPartialMask.enabled() ||
+		hasMaskPaths() || len(l.maskExemptions()) > 0 || l.maskObserver != nil || l.hasJSONFields() ||
+		l.entropy != nil || len(l.maskCategories) > 0 || l.hasMaskFuncs() ||
+		l.detectsValues() || l.scanLimit != nil || l.fieldRules.overridden() || globalFieldsChanged.Load()
+}
+
+// needsMapPath reports whether any field lacks an inline encoder in the hot path
+func needsMapPath(fields []ZField) bool {
+	for _, field := range fields {
+		switch field.(type) {
+		case StringZField, IntZField, Float64ZField, BoolZField:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// needsEscaping reports whether any string in the entry needs JSON escaping,
+// which the hot path's plain copies do not do
+func (l *Logger) needsEscaping(message string, fields []ZField) bool {
+	if !l.jsonSafeMetadata(message) {
+		return true
+	}
+	for _, field := range fields {
+		if !jsonSafe(zfieldKey(field), l.escapeHTML) {
+			return true
+		}
+		if f, ok := field.(StringZField); ok && !jsonSafe(f.Value, l.escapeHTML) {
+			return true
+		}
+	}
+	return false
+}
+
 // logStructuredFields - optimized for maximum performance with thread-safe buffers
 func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...ZField) {
 	// Ultra-fast level check - most critical optimization
-	if level < l.level {
+	if !l.Enabled(level) {
+		return
+	}
+	if l.tee != nil {
+		l.teeLogStructured(level, message, fields...)
+		return
+	}
+	l = l.resolve()
+
+	// Non-JSON formats, logger features applied to the field map and field
+	// types without an inline encoder need the map-based path
+	if l.needsPipeline() || needsMapPath(fields) || l.needsEscaping(message, fields) {
+		l.log(level, message, collectFields(zfieldArgs(fields)...))
 		return
 	}
+	if l.sortsKeys() {
+		fields = sortedZFields(fields)
+	}
 
 	// Get thread-safe buffer from pool to prevent race conditions
 	bufPtr := bufferPool.Get().(*[]byte)
@@ -71,7 +114,7 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 	fieldCount := len(fields)
 	if fieldCount > 4 || len(message) > 200 {
 		// Only do estimation for complex cases
-		estimatedSize := 100 + len(message)
+		estimatedSize := 100 + len(message) + len(fieldsPrefix) + 1
 
 		if l.component != "" {
 			estimatedSize += 15 + len(l.component)
@@ -129,8 +172,12 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 			levelBytes = warnLevelBytes
 		case ERROR:
 			levelBytes = errorLevelBytes
+		case PANIC:
+			levelBytes = panicLevelBytes
+		case FATAL:
+			levelBytes = fatalLevelBytes
 		default:
-			levelBytes = infoLevelBytes
+			levelBytes = customLevelBytes(level)
 		}
 		copy(buf[pos:], levelBytes)
 		pos += len(levelBytes)
@@ -144,6 +191,11 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 	buf[pos] = '"'
 	pos++
 
+	// Component and version, then the fields object, in the order the
+	// map-based path writes them
+	pos = l.putMetadata(buf, pos)
+	pos, fieldsStart := openFields(buf, pos, fields)
+
 	// Process fields inline - unrolled for maximum performance
 	for _, field := range fields {
 		switch f := field.(type) {
@@ -163,12 +215,12 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 
 			// Security check inline - optimize for non-sensitive case
 			if f.IsSensitive() || f.IsPII() {
+				l.stats.countMasked()
 				copy(buf[pos:], "***MASKED***")
 				pos += 12
 			} else {
-				// Properly escape JSON strings to prevent invalid JSON
-				escaped := escapeJSONString(buf[pos:], f.Value)
-				pos += escaped
+				copy(buf[pos:], f.Value)
+				pos += len(f.Value)
 			}
 
 			buf[pos] = '"'
@@ -208,7 +260,7 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 
 			// Fast float conversion
 			var numBuf [32]byte
-			numStr := strconv.AppendFloat(numBuf[:0], f.Value, 'g', -1, 64)
+			numStr := l.floatFormat.append(numBuf[:0], f.Value, 64)
 			copy(buf[pos:], numStr)
 			pos += len(numStr)
 
@@ -242,25 +294,7 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 		}
 	}
 
-	// Add component if present (pre-computed prefix)
-	if l.component != "" {
-		copy(buf[pos:], componentPrefix)
-		pos += len(componentPrefix)
-		copy(buf[pos:], l.component)
-		pos += len(l.component)
-		buf[pos] = '"'
-		pos++
-	}
-
-	// Add version if present (pre-computed prefix)
-	if l.version != "" {
-		copy(buf[pos:], versionPrefix)
-		pos += len(versionPrefix)
-		copy(buf[pos:], l.version)
-		pos += len(l.version)
-		buf[pos] = '"'
-		pos++
-	}
+	pos = closeFields(buf, pos, fieldsStart)
 
 	// Close JSON: }\n - inline for final micro-optimization
 	buf[pos] = '}'
@@ -268,7 +302,7 @@ func (l *Logger) logStructuredFields(level LogLevel, message string, fields ...Z
 	pos += 2
 
 	// Single write operation
-	_, _ = l.writer.Write(buf[:pos])
+	l.writeLine(level, buf[:pos])
 }
 
 // logStructuredFieldsDynamic - handles cases where log entry is too large for stack buffer
@@ -285,6 +319,7 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 	}
 
 	// Add fields
+	size += len(fieldsPrefix) + 1
 	for _, field := range fields {
 		switch f := field.(type) {
 		case StringZField:
@@ -303,7 +338,10 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 		size = 2048
 	}
 
-	buf := make([]byte, size)
+	lineBuf := getLineBuffer()
+	defer putLineBuffer(lineBuf)
+	lineBuf.Grow(size)
+	buf := lineBuf.Bytes()[:size]
 	pos := 0
 
 	// Build JSON (similar to hot path but with bounds checking)
@@ -330,8 +368,12 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 		levelBytes = warnLevelBytes
 	case ERROR:
 		levelBytes = errorLevelBytes
+	case PANIC:
+		levelBytes = panicLevelBytes
+	case FATAL:
+		levelBytes = fatalLevelBytes
 	default:
-		levelBytes = infoLevelBytes
+		levelBytes = customLevelBytes(level)
 	}
 
 	copy(buf[pos:], levelBytes)
@@ -341,6 +383,8 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 	pos += len(message)
 	buf[pos] = '"'
 	pos++
+	pos = l.putMetadata(buf, pos)
+	pos, fieldsStart := openFields(buf, pos, fields)
 	// Process fields - inline version
 	for _, field := range fields {
 		switch f := field.(type) {
@@ -356,12 +400,12 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 			pos += 3
 
 			if f.IsSensitive() || f.IsPII() {
+				l.stats.countMasked()
 				copy(buf[pos:], "***MASKED***")
 				pos += 12
 			} else {
-				// Properly escape JSON strings in dynamic formatter too
-				escaped := escapeJSONString(buf[pos:], f.Value)
-				pos += escaped
+				copy(buf[pos:], f.Value)
+				pos += len(f.Value)
 			}
 			buf[pos] = '"'
 			pos++
@@ -392,7 +436,7 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 			pos += 2
 
 			var numBuf [32]byte
-			numStr := strconv.AppendFloat(numBuf[:0], f.Value, 'g', -1, 64)
+			numStr := l.floatFormat.append(numBuf[:0], f.Value, 64)
 			copy(buf[pos:], numStr)
 			pos += len(numStr)
 
@@ -423,7 +467,19 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 		}
 	}
 
-	// Add component and version
+	pos = closeFields(buf, pos, fieldsStart)
+
+	// Close JSON: }\n
+	buf[pos] = '}'
+	buf[pos+1] = '\n'
+	pos += 2
+
+	l.writeLine(level, buf[:pos])
+}
+
+// putMetadata writes the component and version members at pos and returns
+// the position after them
+func (l *Logger) putMetadata(buf []byte, pos int) int {
 	if l.component != "" {
 		copy(buf[pos:], componentPrefix)
 		pos += len(componentPrefix)
@@ -441,13 +497,28 @@ func (l *Logger) logStructuredFieldsDynamic(level LogLevel, message string, fiel
 		buf[pos] = '"'
 		pos++
 	}
+	return pos
+}
+
+// openFields writes the "fields" key when there are fields. It returns the
+// position after it and that of the comma the first field writes, or -1.
+func openFields(buf []byte, pos int, fields []ZField) (int, int) {
+	if len(fields) == 0 {
+		return pos, -1
+	}
+	pos += copy(buf[pos:], fieldsPrefix)
+	return pos, pos
+}
 
-	// Close JSON: }\n
+// closeFields turns the first field's leading comma into the opening brace
+// of the fields object and closes it, returning the position after it
+func closeFields(buf []byte, pos, fieldsStart int) int {
+	if fieldsStart < 0 {
+		return pos
+	}
+	buf[fieldsStart] = '{'
 	buf[pos] = '}'
-	buf[pos+1] = '\n'
-	pos += 2
-
-	_, _ = l.writer.Write(buf[:pos])
+	return pos + 1
 }
 
 // Route structured fields to implementation
@@ -0,0 +1,79 @@
+package emit
+
+import (
+	"maps"
+	"reflect"
+)
+
+// ungroupedKeys are correlation fields that stay at the top level of grouped
+// entries, where tracing backends and the ECS and GCP formats look for them
+var ungroupedKeys = map[string]bool{
+	traceIDKey: true, spanIDKey: true, requestIDKey: true, stackTraceKey: true,
+}
+
+// WithGroup returns a child logger that nests the fields of later calls
+// under name, as slog's Logger.WithGroup does:
+//
+//	dbLogger := logger.WithFields(map[string]any{"service": "api"}).WithGroup("db")
+//	dbLogger.Info("query", "table", "users", "rows", 3)
+//	// "service":"api","db":{"table":"users","rows":3}
+//
+// Call-site and context fields and fields added with WithFields on the
+// child go into the group; fields the logger already had stay where they
+// were. Groups nest, so WithGroup("db").WithGroup("pool") writes under
+// db.pool. Trace and span IDs, request IDs and stack traces stay at the top
+// level. Grouped fields are masked like any nested fields, so a password
+// inside a group is still masked. An empty name returns the logger itself.
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	if l.tee != nil {
+		return l.teeMap(func(child *Logger) *Logger { return child.WithGroup(name) })
+	}
+	child := l.derive()
+	child.groups = append(l.groups[:len(l.groups):len(l.groups)], name)
+	return child
+}
+
+// mergeGrouped returns base with fields added under the logger's groups,
+// copying the group maps so loggers derived from the same parent never
+// share them
+func (l *Logger) mergeGrouped(base, fields map[string]any) map[string]any {
+	merged := copyFieldTree(base)
+	if merged == nil {
+		merged = make(map[string]any, len(fields)+1)
+	}
+	var target map[string]any
+	for key, value := range fields {
+		if ungroupedKeys[key] {
+			merged[key] = value
+			continue
+		}
+		if target == nil {
+			target = groupMap(merged, l.groups)
+		}
+		target[key] = value
+	}
+	return merged
+}
+
+// resolveGroupedLazyFields resolves lazy fields at the top level and in
+// each group along groups, copying only the maps that change
+func resolveGroupedLazyFields(level LogLevel, fields map[string]any, groups []string) map[string]any {
+	fields = resolveLazyFields(level, fields)
+	if len(groups) == 0 {
+		return fields
+	}
+	nested, ok := fields[groups[0]].(map[string]any)
+	if !ok {
+		return fields
+	}
+	resolved := resolveGroupedLazyFields(level, nested, groups[1:])
+	if reflect.ValueOf(resolved).Pointer() == reflect.ValueOf(nested).Pointer() {
+		return fields
+	}
+	fields = maps.Clone(fields)
+	fields[groups[0]] = resolved
+	return fields
+}
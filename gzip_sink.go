@@ -0,0 +1,176 @@
+package emit
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultGzipFlushInterval is how often buffered compressed output is flushed
+const defaultGzipFlushInterval = time.Second
+
+// GzipOption configures a GzipSink created with GzipWriter
+type GzipOption func(*GzipSink)
+
+// GzipSink is an io.Writer that gzips log lines into another writer. It is
+// safe for concurrent use.
+type GzipSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	zw       *gzip.Writer
+	level    int
+	interval time.Duration
+	pending  bool
+	flushErr error
+	closed   bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// GzipWriter returns a writer that compresses the log stream into w, for
+// shipping logs over the network or to object storage cheaply:
+//
+//	conn, _ := net.Dial("tcp", "collector:5140")
+//	sink, _ := emit.GzipWriter(conn, emit.GzipFlushInterval(5*time.Second))
+//	logger, _ := emit.New(emit.WithOutput(sink))
+//	defer logger.Close()
+//
+// Compressed data is buffered and flushed to w once per interval (one second
+// by default) when lines were written. Flushing ends the current deflate
+// block, so frequent flushes deliver lines sooner but compress worse: with
+// an interval of zero every Write is flushed and little is saved, while
+// longer intervals approach the ratio of compressing the file afterwards at
+// the cost of lines arriving late and being lost if the process dies. Close
+// writes the gzip footer and closes w if it is an io.Closer.
+func GzipWriter(w io.Writer, opts ...GzipOption) (*GzipSink, error) {
+	s := &GzipSink{
+		w:        w,
+		level:    gzip.DefaultCompression,
+		interval: defaultGzipFlushInterval,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.interval < 0 {
+		return nil, errors.New("emit: gzip flush interval must not be negative")
+	}
+	zw, err := gzip.NewWriterLevel(w, s.level)
+	if err != nil {
+		return nil, fmt.Errorf("emit: invalid gzip compression level %d", s.level)
+	}
+	s.zw = zw
+
+	if s.interval > 0 {
+		s.wg.Add(1)
+		go s.flushLoop()
+	}
+	return s, nil
+}
+
+// GzipFlushInterval sets how often buffered output is flushed; zero flushes
+// after every Write
+func GzipFlushInterval(interval time.Duration) GzipOption {
+	return func(s *GzipSink) {
+		s.interval = interval
+	}
+}
+
+// GzipLevel sets the compression level, from gzip.BestSpeed to
+// gzip.BestCompression (default gzip.DefaultCompression)
+func GzipLevel(level int) GzipOption {
+	return func(s *GzipSink) {
+		s.level = level
+	}
+}
+
+// flushLoop flushes pending output once per interval until Close
+func (s *GzipSink) flushLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			if s.pending && !s.closed {
+				s.flushErr = s.flushLocked()
+			}
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Write compresses p. An error from the last background flush is returned
+// once.
+func (s *GzipSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+	if err := s.flushErr; err != nil {
+		s.flushErr = nil
+		return 0, err
+	}
+	n, err := s.zw.Write(p)
+	if err != nil {
+		return n, err
+	}
+	s.pending = true
+	if s.interval == 0 {
+		err = s.flushLocked()
+	}
+	return n, err
+}
+
+// flushLocked flushes compressed data to w; s.mu must be held
+func (s *GzipSink) flushLocked() error {
+	s.pending = false
+	return s.zw.Flush()
+}
+
+// Flush writes buffered compressed data to w and flushes w if it buffers too
+func (s *GzipSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	return errors.Join(s.flushLocked(), syncWriter(s.w))
+}
+
+// Sync is Flush, for writers that look for Sync
+func (s *GzipSink) Sync() error {
+	return s.Flush()
+}
+
+// Close stops background flushing, writes the gzip footer and closes w if it
+// is an io.Closer (stdout and stderr are left open). It is safe to call more
+// than once.
+func (s *GzipSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	err := errors.Join(s.zw.Close(), syncWriter(s.w))
+	if c, ok := s.w.(io.Closer); ok && s.w != os.Stdout && s.w != os.Stderr {
+		err = errors.Join(err, c.Close())
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
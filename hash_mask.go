@@ -0,0 +1,101 @@
+package emit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// hashedValueLength is the number of hex characters kept from the digest
+const hashedValueLength = 16
+
+// processHashSalt is the random salt used by loggers given no salt, so
+// unsalted digests of low-entropy values such as PINs cannot be looked up
+var processHashSalt = sync.OnceValue(func() []byte {
+	salt := make([]byte, 32)
+	_, _ = rand.Read(salt)
+	return salt
+})
+
+// hashSaltValue holds a logger's hash salt. Like the other registries it is
+// shared with child loggers and safe to change while logging.
+type hashSaltValue struct {
+	salt atomic.Pointer[[]byte]
+}
+
+// store replaces the salt with a copy of salt
+func (h *hashSaltValue) store(salt []byte) {
+	salt = slices.Clone(salt)
+	h.salt.Store(&salt)
+}
+
+// load returns the salt, or the process salt when none is set
+func (h *hashSaltValue) load() []byte {
+	if h != nil {
+		if salt := h.salt.Load(); salt != nil && len(*salt) > 0 {
+			return *salt
+		}
+	}
+	return processHashSalt()
+}
+
+// WithHashSalt sets the salt mixed into values hashed under HASH_SENSITIVE
+// or a hashing mask category. Without one, each process uses a random salt,
+// so tokens are stable within a process but differ between instances and
+// restarts; set a secret salt shared by a deployment to correlate them.
+func WithHashSalt(salt string) Option {
+	return func(l *Logger) error {
+		if salt == "" {
+			return errors.New("emit: hash salt must not be empty")
+		}
+		l.hashSalt = &hashSaltValue{}
+		l.hashSalt.store([]byte(salt))
+		return nil
+	}
+}
+
+// hashValue replaces a value with a salted SHA-256 hex prefix so identical
+// inputs produce identical tokens without exposing the original data
+func (l *Logger) hashValue(value any) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	h := sha256.New()
+	h.Write(l.hashSalt.load())
+	h.Write([]byte(s))
+	sum := h.Sum(nil)
+
+	return hex.EncodeToString(sum)[:hashedValueLength]
+}
+
+// maskSensitiveValue returns the representation of a detected sensitive value
+// according to the logger's sensitive mode
+func (l *Logger) maskSensitiveValue(value any) any {
+	if l.sensitiveMode == HASH_SENSITIVE {
+		return l.hashValue(value)
+	}
+	return l.partialMask.apply(value, l.maskString)
+}
+
+// SetHashSalt sets the salt mixed into hashed sensitive values, as
+// WithHashSalt does, for the logger and its children. It is safe to call
+// while logging; an empty salt restores the random process salt.
+func (l *Logger) SetHashSalt(salt []byte) {
+	registryInitMu.Lock()
+	if l.hashSalt == nil {
+		l.hashSalt = &hashSaltValue{}
+	}
+	registryInitMu.Unlock()
+
+	l.hashSalt.store(salt)
+}
@@ -0,0 +1,82 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrSkipEntry is returned by a Hook to drop the entry
+var ErrSkipEntry = errors.New("emit: skip entry")
+
+// Hook inspects or changes an entry before it is encoded. It may add or
+// remove fields, rewrite the message or change the level. Returning
+// ErrSkipEntry drops the entry; any other error is reported and the entry is
+// still written.
+type Hook func(e *Entry) error
+
+// WithHook adds a hook that runs on every entry that passes level filtering,
+// sampling and deduplication. Hooks run in the order they were added, after
+// masking, so they see masked values and the fields they add are written
+// as-is:
+//
+//	emit.WithHook(func(e *emit.Entry) error {
+//		if e.Message == "healthcheck" {
+//			return emit.ErrSkipEntry
+//		}
+//		e.Fields["region"] = region
+//		return nil
+//	})
+func WithHook(hook Hook) Option {
+	return func(l *Logger) error {
+		if hook == nil {
+			return errors.New("emit: hook must not be nil")
+		}
+		l.hooks = append(slices.Clip(l.hooks), hook)
+		return nil
+	}
+}
+
+// processesEntries reports whether entries are masked up front and changed
+// before encoding
+func (l *Logger) processesEntries() bool {
+	return len(l.hooks) > 0 || l.keyTransformer != nil || l.flattenSeparator != "" ||
+		l.maxFields > 0 || l.maxValueLen > 0
+}
+
+// writeProcessedEntry masks the fields, runs the hooks, transforms the keys,
+// flattens nested maps, applies the size limits and encodes the result
+// without masking it again
+func (l *Logger) writeProcessedEntry(level LogLevel, message string, fields map[string]any) {
+	entry := Entry{Level: level, Message: message, Fields: maps.Clone(l.maskSensitiveFieldsFast(fields))}
+	if entry.Fields == nil {
+		entry.Fields = make(map[string]any)
+	}
+
+	for _, hook := range l.hooks {
+		if err := hook(&entry); err != nil {
+			if errors.Is(err, ErrSkipEntry) {
+				return
+			}
+			l.reportError(fmt.Errorf("emit: hook failed: %w", err))
+		}
+	}
+
+	if l.keyTransformer != nil {
+		entry.Fields = l.transformKeys(entry.Fields, 0)
+	}
+	if l.flattenSeparator != "" {
+		entry.Fields = l.flattenFields(entry.Fields)
+	}
+	entry.Fields = l.limitFields(entry.Fields)
+
+	// The fields are already masked; encode them as they are, without
+	// reporting them to the observer again
+	masked := *l
+	masked.premasked = true
+	masked.maskObserver = nil
+	masked.defaultFields = nil
+	masked.extraCallerSkip++
+	masked.writeEntry(entry.Level, entry.Message, entry.Fields)
+}
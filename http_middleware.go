@@ -0,0 +1,225 @@
+package emit
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPOption configures HTTPMiddleware
+type HTTPOption func(*httpMiddleware)
+
+type httpMiddleware struct {
+	logger         *Logger
+	headers        []string
+	redactedParams map[string]bool
+	skipPaths      map[string]bool
+}
+
+// alwaysMaskedHeaders are masked whatever the logger's sensitive data mode
+var alwaysMaskedHeaders = map[string]bool{"authorization": true, "cookie": true, "proxy-authorization": true}
+
+// AlwaysMaskedHeader reports whether a header or gRPC metadata key, such as
+// Authorization, is masked by the middlewares whatever the logger's mode
+func AlwaysMaskedHeader(name string) bool {
+	return alwaysMaskedHeaders[strings.ToLower(name)]
+}
+
+// WithLoggedHeaders adds the named request headers to each entry under
+// "http.headers". Authorization and Cookie are always masked; other headers
+// are masked by the logger's field rules like any other field.
+func WithLoggedHeaders(headers ...string) HTTPOption {
+	return func(m *httpMiddleware) {
+		for _, header := range headers {
+			m.headers = append(m.headers, strings.ToLower(header))
+		}
+	}
+}
+
+// WithRedactedQueryParams masks the values of the named query-string
+// parameters, in addition to those whose names the logger detects as
+// sensitive or PII
+func WithRedactedQueryParams(keys ...string) HTTPOption {
+	return func(m *httpMiddleware) {
+		for _, key := range keys {
+			m.redactedParams[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// WithSkipPaths stops requests to the given paths, such as "/healthz" or
+// "/metrics", from being logged
+func WithSkipPaths(paths ...string) HTTPOption {
+	return func(m *httpMiddleware) {
+		for _, path := range paths {
+			m.skipPaths[path] = true
+		}
+	}
+}
+
+// HTTPMiddleware logs each request once the handler returns, with its method,
+// path, masked query string, status and duration:
+//
+//	mux := http.NewServeMux()
+//	handler := emit.HTTPMiddleware(logger,
+//		emit.WithLoggedHeaders("User-Agent", "X-Tenant"),
+//		emit.WithSkipPaths("/healthz", "/metrics"),
+//	)(mux)
+//
+// Responses below 400 are logged at info level, 4xx at warn and 5xx at error.
+// The request ID set by RequestIDMiddleware, when it wraps this middleware,
+// is logged as "request_id". A request whose handler panics is logged with
+// the "panic" value before the panic is re-raised.
+func HTTPMiddleware(logger *Logger, opts ...HTTPOption) func(http.Handler) http.Handler {
+	m := &httpMiddleware{
+		logger:         logger,
+		redactedParams: make(map[string]bool),
+		skipPaths:      make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if m.skipPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				// Log requests whose handler panics too, then re-raise the panic
+				recovered := recover()
+				status := recorder.statusCode()
+				if recovered != nil && recorder.status == 0 {
+					status = http.StatusInternalServerError
+				}
+				if ce := logger.Check(statusLevel(status)); ce != nil {
+					fields := m.fields(r, status, time.Since(start))
+					if recovered != nil {
+						fields = append(fields, "panic", fmt.Sprint(recovered))
+					}
+					ce.Write("http request", fields...)
+				}
+				if recovered != nil {
+					panic(recovered)
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// fields returns the entry fields for a completed request
+func (m *httpMiddleware) fields(r *http.Request, status int, duration time.Duration) []any {
+	fields := []any{
+		"http.method", r.Method,
+		"http.path", r.URL.Path,
+		"http.status", status,
+		"duration", duration,
+	}
+	if id, ok := contextFields(r.Context())[requestIDKey].(requestID); ok {
+		fields = append(fields, requestIDKey, id)
+	}
+	if r.URL.RawQuery != "" {
+		fields = append(fields, "http.query", m.redactQuery(m.logger.resolve(), r.URL.RawQuery))
+	}
+	if len(m.headers) > 0 {
+		headers := make(map[string]any, len(m.headers))
+		for _, name := range m.headers {
+			value := r.Header.Get(name)
+			if value == "" {
+				continue
+			}
+			if alwaysMaskedHeaders[name] {
+				headers[name] = Secret(value)
+				continue
+			}
+			headers[name] = value
+		}
+		if len(headers) > 0 {
+			fields = append(fields, "http.headers", headers)
+		}
+	}
+	return fields
+}
+
+// redactQuery masks parameter values in a raw query string with l's rules,
+// keeping the order and encoding of the rest
+func (m *httpMiddleware) redactQuery(l *Logger, rawQuery string) string {
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		rawKey, _, hasValue := strings.Cut(param, "=")
+		if !hasValue {
+			continue
+		}
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		switch {
+		case m.redactedParams[strings.ToLower(key)] || l.isSensitiveFieldFast(key):
+			params[i] = rawKey + "=" + l.maskString
+		case l.isPIIFieldFast(key):
+			params[i] = rawKey + "=" + l.piiMaskString
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// statusLevel maps a response status to a level
+func statusLevel(status int) LogLevel {
+	switch {
+	case status >= 500:
+		return ERROR
+	case status >= 400:
+		return WARN
+	default:
+		return INFO
+	}
+}
+
+// statusRecorder captures the status written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	// Informational 1xx responses precede the final status
+	if r.status == 0 && status >= 200 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush lets streaming handlers flush through the recorder
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap gives http.ResponseController access to the original writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// statusCode returns the written status, 200 when the handler wrote nothing
+func (r *statusRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
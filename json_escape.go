@@ -0,0 +1,108 @@
+package emit
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// WithHTMLSafeJSON escapes <, > and & in JSON strings as \u003c, \u003e
+// and \u0026, so entries can be embedded in HTML pages or script tags without
+// being interpreted. By default they are written as is, which is smaller
+// and easier to read in log viewers.
+func WithHTMLSafeJSON() Option {
+	return func(l *Logger) error {
+		l.escapeHTML = true
+		return nil
+	}
+}
+
+// appendJSONString appends s to dst as a quoted JSON string. Quotes,
+// backslashes and control characters are escaped, invalid UTF-8 is replaced
+// with U+FFFD and the line and paragraph separators U+2028 and U+2029 are
+// escaped for JavaScript consumers. With escapeHTML, <, > and & are escaped
+// too. The result always parses as a JSON string.
+func appendJSONString(dst []byte, s string, escapeHTML bool) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if !jsonEscapes(c, escapeHTML) {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// jsonEscapes reports whether the ASCII byte c must be escaped
+func jsonEscapes(c byte, escapeHTML bool) bool {
+	switch {
+	case c < 0x20, c == '"', c == '\\':
+		return true
+	case c == '<', c == '>', c == '&':
+		return escapeHTML
+	}
+	return false
+}
+
+// jsonSafe reports whether s can be copied between quotes without escaping,
+// which lets the hand-built encoders skip appendJSONString
+func jsonSafe(s string, escapeHTML bool) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if jsonEscapes(c, escapeHTML) {
+				return false
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || r == '\u2028' || r == '\u2029' {
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// writeJSONString writes s to buf as a quoted JSON string
+func writeJSONString(buf *bytes.Buffer, s string, escapeHTML bool) {
+	buf.Write(appendJSONString(buf.AvailableBuffer(), s, escapeHTML))
+}
@@ -0,0 +1,116 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// jsonFields holds case-folded names of string fields that carry JSON. The
+// map is replaced, never mutated, so readers load it without locking.
+var (
+	jsonFields   atomic.Pointer[map[string]bool]
+	jsonFieldsMu sync.Mutex
+)
+
+// AddJSONField marks string fields that hold JSON documents, such as a logged
+// request body. Their values are parsed, masked with the usual rules and
+// re-encoded, so a password inside the body is masked like a password field:
+//
+//	emit.AddJSONField("body")
+//	logger.Info("request", "body", `{"user":"ada","password":"hunter2"}`)
+//	// "body":"{\"password\":\"***MASKED***\",\"user\":\"ada\"}"
+//
+// Values that are not valid JSON are masked whole. Parsing costs time on
+// every entry carrying the field, so only list fields known to hold JSON.
+// The names apply to every logger; see WithRedactJSON for one logger.
+func AddJSONField(fields ...string) {
+	updateJSONFields(func(names map[string]bool) {
+		for _, field := range fields {
+			names[foldFieldName(field)] = true
+		}
+	})
+}
+
+// RemoveJSONField removes names added with AddJSONField
+func RemoveJSONField(fields ...string) {
+	updateJSONFields(func(names map[string]bool) {
+		for _, field := range fields {
+			delete(names, foldFieldName(field))
+		}
+	})
+}
+
+// updateJSONFields publishes a modified copy of the global JSON field names
+func updateJSONFields(update func(map[string]bool)) {
+	jsonFieldsMu.Lock()
+	defer jsonFieldsMu.Unlock()
+
+	names := make(map[string]bool)
+	if current := jsonFields.Load(); current != nil {
+		maps.Copy(names, *current)
+	}
+	update(names)
+	jsonFields.Store(&names)
+}
+
+// WithRedactJSON marks string fields holding JSON for this logger, in
+// addition to the names added with AddJSONField
+func WithRedactJSON(fields ...string) Option {
+	return func(l *Logger) error {
+		names := maps.Clone(l.jsonFields)
+		if names == nil {
+			names = make(map[string]bool, len(fields))
+		}
+		for _, field := range fields {
+			names[foldFieldName(field)] = true
+		}
+		l.jsonFields = names
+		return nil
+	}
+}
+
+// hasJSONFields reports whether any field is marked as holding JSON
+func (l *Logger) hasJSONFields() bool {
+	if len(l.jsonFields) > 0 {
+		return true
+	}
+	global := jsonFields.Load()
+	return global != nil && len(*global) > 0
+}
+
+// isJSONField reports whether a field is marked as holding JSON
+func (l *Logger) isJSONField(fieldName string) bool {
+	if !l.hasJSONFields() {
+		return false
+	}
+	folded := foldFieldName(fieldName)
+	if l.jsonFields[folded] {
+		return true
+	}
+	global := jsonFields.Load()
+	return global != nil && (*global)[folded]
+}
+
+// redactJSON masks the document in a JSON string field, masking the whole
+// value when it does not parse
+func (l *Logger) redactJSON(key, value string) any {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return l.maskSensitive(key, value)
+	}
+
+	masked, _ := l.maskNestedValue(document, nil)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(masked); err != nil {
+		return l.maskSensitive(key, value)
+	}
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
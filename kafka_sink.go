@@ -0,0 +1,220 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// KafkaMessage is one encoded log line published by a KafkaWriter
+type KafkaMessage struct {
+	Key   []byte // Value of the key field, nil when unset or absent
+	Value []byte // The encoded line without its trailing newline
+}
+
+// KafkaProducer publishes messages to a topic. Implement it over the client
+// already in use (Kafka, NATS, Pub/Sub, ...) so emit does not depend on one.
+// Messages and their slices are not reused after Produce returns.
+type KafkaProducer interface {
+	Produce(topic string, messages []KafkaMessage) error
+}
+
+// KafkaOption configures a KafkaWriter created with KafkaSink
+type KafkaOption func(*KafkaWriter)
+
+// KafkaWriter is an io.Writer that publishes each log line as a message. It
+// is safe for concurrent use.
+type KafkaWriter struct {
+	mu        sync.Mutex
+	producer  KafkaProducer
+	topic     string
+	keyField  string
+	batchSize int
+	linger    time.Duration
+	batch     []KafkaMessage
+	lingerErr error
+	closed    bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// KafkaSink returns a writer that publishes each encoded line to topic:
+//
+//	sink, _ := emit.KafkaSink(producer, "logs",
+//		emit.KafkaKeyField("tenant_id"),
+//		emit.KafkaBatch(100, 500*time.Millisecond),
+//	)
+//	logger, _ := emit.New(emit.WithOutput(sink), emit.WithAsync(10000, emit.DropOldest))
+//
+// Publishing blocks the writing goroutine, so pair it with WithAsync: the
+// queue absorbs slow brokers and its overflow policy decides what happens
+// when they fall behind. Errors publishing a full batch are returned from
+// the Write that filled it and reach the logger's error handler; errors from
+// a lingering batch are returned by the next Sync or Close. Close does not
+// close the producer.
+func KafkaSink(producer KafkaProducer, topic string, opts ...KafkaOption) (*KafkaWriter, error) {
+	if producer == nil {
+		return nil, errors.New("emit: kafka sink needs a producer")
+	}
+	k := &KafkaWriter{
+		producer:  producer,
+		topic:     topic,
+		batchSize: 1,
+		stop:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(k)
+	}
+	if k.batchSize < 1 {
+		return nil, errors.New("emit: kafka batch size must be positive")
+	}
+	if k.batchSize > 1 && k.linger <= 0 {
+		return nil, errors.New("emit: kafka batch linger must be positive")
+	}
+
+	if k.batchSize > 1 {
+		k.wg.Add(1)
+		go k.lingerLoop()
+	}
+	return k, nil
+}
+
+// KafkaKeyField keys each message by the value of a field, e.g. "tenant_id",
+// so a tenant's entries land on one partition in order. The field is read
+// from JSON entries, top level or under "fields"; other formats are
+// published without a key.
+func KafkaKeyField(field string) KafkaOption {
+	return func(k *KafkaWriter) {
+		k.keyField = field
+	}
+}
+
+// KafkaBatch publishes up to size lines per Produce call. Partial batches
+// are published every linger, bounding how late a line can arrive.
+func KafkaBatch(size int, linger time.Duration) KafkaOption {
+	return func(k *KafkaWriter) {
+		k.batchSize = size
+		k.linger = linger
+	}
+}
+
+// Write queues each line of p and publishes the batch once it is full
+func (k *KafkaWriter) Write(p []byte) (int, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.closed {
+		return 0, os.ErrClosed
+	}
+	var errs []error
+	for rest := p; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line, rest = rest[:i], rest[i+1:]
+		} else {
+			rest = nil
+		}
+		if len(line) == 0 {
+			continue
+		}
+		k.batch = append(k.batch, KafkaMessage{Key: k.messageKey(line), Value: bytes.Clone(line)})
+		if len(k.batch) >= k.batchSize {
+			errs = append(errs, k.publishLocked())
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// messageKey extracts the key field from an encoded JSON line
+func (k *KafkaWriter) messageKey(line []byte) []byte {
+	if k.keyField == "" || len(line) == 0 || line[0] != '{' {
+		return nil
+	}
+	var entry map[string]json.RawMessage
+	if json.Unmarshal(line, &entry) != nil {
+		return nil
+	}
+	raw, ok := entry[k.keyField]
+	if !ok {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(entry["fields"], &fields) != nil {
+			return nil
+		}
+		if raw, ok = fields[k.keyField]; !ok {
+			return nil
+		}
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []byte(s)
+	}
+	return bytes.Clone(raw)
+}
+
+// publishLocked produces the pending batch; k.mu must be held
+func (k *KafkaWriter) publishLocked() error {
+	if len(k.batch) == 0 {
+		return nil
+	}
+	batch := k.batch
+	k.batch = make([]KafkaMessage, 0, k.batchSize)
+	if err := k.producer.Produce(k.topic, batch); err != nil {
+		return fmt.Errorf("emit: publishing %d messages to %q: %w", len(batch), k.topic, err)
+	}
+	return nil
+}
+
+// lingerLoop publishes partial batches until Close
+func (k *KafkaWriter) lingerLoop() {
+	defer k.wg.Done()
+	ticker := time.NewTicker(k.linger)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			k.mu.Lock()
+			if !k.closed {
+				k.lingerErr = errors.Join(k.lingerErr, k.publishLocked())
+			}
+			k.mu.Unlock()
+		case <-k.stop:
+			return
+		}
+	}
+}
+
+// Sync publishes the pending batch
+func (k *KafkaWriter) Sync() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	err := errors.Join(k.lingerErr, k.publishLocked())
+	k.lingerErr = nil
+	return err
+}
+
+// Close publishes the pending batch and stops the linger timer. It is safe
+// to call more than once.
+func (k *KafkaWriter) Close() error {
+	k.mu.Lock()
+	if k.closed {
+		k.mu.Unlock()
+		return nil
+	}
+	k.closed = true
+	close(k.stop)
+	err := errors.Join(k.lingerErr, k.publishLocked())
+	k.lingerErr = nil
+	k.mu.Unlock()
+
+	k.wg.Wait()
+	return err
+}
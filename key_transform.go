@@ -0,0 +1,142 @@
+package emit
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithKeyTransformer rewrites every field key before the entry is encoded,
+// including the keys of field maps nested in values, so code paths that log
+// "userId" and "user_id" end up in the same index:
+//
+//	emit.WithKeyTransformer(emit.ToSnakeCase)
+//
+// Masking and hooks see the original keys; the entry keys (timestamp, level,
+// message) are not transformed. When two keys transform to the same name, one
+// of the values is kept.
+func WithKeyTransformer(transform func(key string) string) Option {
+	return func(l *Logger) error {
+		if transform == nil {
+			return errors.New("emit: key transformer must not be nil")
+		}
+		l.keyTransformer = transform
+		return nil
+	}
+}
+
+// transformKeys returns a copy of fields with the keys transformed,
+// descending into nested field maps and slices
+func (l *Logger) transformKeys(fields map[string]any, depth int) map[string]any {
+	transformed := make(map[string]any, len(fields))
+	for key, value := range fields {
+		transformed[l.keyTransformer(key)] = l.transformValueKeys(value, depth+1)
+	}
+	return transformed
+}
+
+func (l *Logger) transformValueKeys(value any, depth int) any {
+	if depth > jsonSafeMaxDepth {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		return l.transformKeys(v, depth)
+	case Fields:
+		return l.transformKeys(v, depth)
+	case []map[string]any:
+		transformed := make([]any, len(v))
+		for i, element := range v {
+			transformed[i] = l.transformKeys(element, depth+1)
+		}
+		return transformed
+	case []any:
+		transformed := make([]any, len(v))
+		for i, element := range v {
+			transformed[i] = l.transformValueKeys(element, depth+1)
+		}
+		return transformed
+	}
+	return value
+}
+
+// ToSnakeCase converts keys such as "userId", "UserID" or "user-name" to
+// "user_id" and "user_name". Dots are kept, so "http.statusCode" becomes
+// "http.status_code".
+func ToSnakeCase(key string) string {
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+
+	var prev, last rune // prev is the previous input rune, last the last one written
+	for i, r := range key {
+		out := r
+		switch {
+		case r == '-' || r == ' ':
+			out = '_'
+		case unicode.IsUpper(r):
+			next, _ := utf8.DecodeRuneInString(key[i+utf8.RuneLen(r):])
+			startsWord := unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && unicode.IsLower(next))
+			if startsWord && last != '_' {
+				b.WriteByte('_')
+			}
+			out = unicode.ToLower(r)
+		}
+		prev = r
+		if out == '_' && last == '_' {
+			continue
+		}
+		b.WriteRune(out)
+		last = out
+	}
+	return b.String()
+}
+
+// ToCamelCase converts keys such as "user_id", "user-name" or "UserID" to
+// "userId", "userName" and "userID". Dots are kept, and each dotted segment
+// is converted on its own.
+func ToCamelCase(key string) string {
+	segments := strings.Split(key, ".")
+	for i, segment := range segments {
+		segments[i] = camelSegment(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+func camelSegment(segment string) string {
+	words := strings.FieldsFunc(segment, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	if len(words) == 0 {
+		return segment
+	}
+
+	var b strings.Builder
+	b.Grow(len(segment))
+	b.WriteString(lowerLeadingWord(words[0]))
+	for _, word := range words[1:] {
+		r, size := utf8.DecodeRuneInString(word)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(word[size:])
+	}
+	return b.String()
+}
+
+// lowerLeadingWord lowercases the leading run of capitals, keeping the last
+// one when it starts the next word: "HTTPStatus" becomes "httpStatus"
+func lowerLeadingWord(word string) string {
+	runes := []rune(word)
+	end := 0
+	for end < len(runes) && unicode.IsUpper(runes[end]) {
+		end++
+	}
+	if end > 1 && end < len(runes) && unicode.IsLower(runes[end]) {
+		end--
+	}
+	if end == 0 {
+		end = 1
+	}
+	for i := range end {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,93 @@
+package emit
+
+import (
+	"fmt"
+	"maps"
+)
+
+// LazyZField is a field whose value is computed only when the entry is written
+type LazyZField struct {
+	Key string
+	Fn  func() any
+}
+
+// lazyValue marks a field map value that still has to be evaluated
+type lazyValue func() any
+
+// Lazy creates a field whose function is called only if the entry passes
+// the level check, so expensive values cost nothing for filtered levels:
+//
+//	logger.Debug("cache state", emit.Lazy("snapshot", func() any { return cache.Dump() }))
+//
+// The returned value is masked like any other field.
+func Lazy(key string, fn func() any) LazyZField {
+	return LazyZField{Key: key, Fn: fn}
+}
+
+func (f LazyZField) WriteToEncoder(enc *ZeroAllocEncoder) {
+	enc.writeStringField(f.Key, fmt.Sprint(evaluateLazy(f.Fn)))
+}
+
+func (f LazyZField) IsSensitive() bool { return false }
+func (f LazyZField) IsPII() bool       { return false }
+
+// resolveLazyFields evaluates lazy values and drops OnlyAtLevel fields above
+// level, copying the map only when one is present
+func resolveLazyFields(level LogLevel, fields map[string]any) map[string]any {
+	var resolved map[string]any
+	for key, value := range fields {
+		leveled, isLeveled := value.(LevelZField)
+		if isLeveled {
+			if level > leveled.Level {
+				if resolved == nil {
+					resolved = maps.Clone(fields)
+				}
+				delete(resolved, key)
+				continue
+			}
+			value = leveled.Value
+		}
+
+		var fn func() any
+		lazy := true
+		switch v := value.(type) {
+		case lazyValue:
+			fn = v
+		case LazyZField:
+			// Lazy used directly as a map value, e.g. in WithFields
+			fn = v.Fn
+		case func() any:
+			fn = v
+			lazy = isLeveled
+		default:
+			lazy = false
+		}
+		if !lazy && !isLeveled {
+			continue
+		}
+		if resolved == nil {
+			resolved = maps.Clone(fields)
+		}
+		if lazy {
+			value = evaluateLazy(fn)
+		}
+		resolved[key] = value
+	}
+	if resolved == nil {
+		return fields
+	}
+	return resolved
+}
+
+// evaluateLazy calls fn, turning a panic into a placeholder value
+func evaluateLazy(fn func() any) (value any) {
+	if fn == nil {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			value = fmt.Sprintf("!PANIC: %v", r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,34 @@
+package emit
+
+import "fmt"
+
+// LevelZField is a field written only on entries at or below its level
+type LevelZField struct {
+	Key   string
+	Level LogLevel
+	Value any
+}
+
+// OnlyAtLevel creates a field that is kept on entries at level or below and
+// dropped from the rest, so verbose context rides along on debug entries
+// without adding noise to info and warn ones:
+//
+//	requestLogger := logger.WithFields(map[string]any{
+//		"request_id": id,
+//		"headers":    emit.OnlyAtLevel(emit.DEBUG, "headers", r.Header),
+//	})
+//	requestLogger.Debug("routing")  // has request_id and headers
+//	requestLogger.Info("completed") // has request_id only
+//
+// As a map value the map key is used. Value may be a func() any or a Lazy
+// field, which is then called only when the field is kept.
+func OnlyAtLevel(level LogLevel, key string, value any) LevelZField {
+	return LevelZField{Key: key, Level: level, Value: value}
+}
+
+func (f LevelZField) WriteToEncoder(enc *ZeroAllocEncoder) {
+	enc.writeStringField(f.Key, fmt.Sprint(f.Value))
+}
+
+func (f LevelZField) IsSensitive() bool { return false }
+func (f LevelZField) IsPII() bool       { return false }
@@ -0,0 +1,100 @@
+package emit
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+)
+
+// levelRoute sends entries from minLevel up to the next route's level to writer
+type levelRoute struct {
+	minLevel LogLevel
+	writer   io.Writer
+}
+
+// WithLevelRouting sends each entry to exactly one writer chosen by its
+// level. Each key starts a range that extends up to the next key, so
+//
+//	emit.WithLevelRouting(map[emit.LogLevel]io.Writer{emit.WARN: os.Stderr})
+//
+// writes WARN, ERROR and above to stderr. Levels below the lowest key go to
+// the logger's output (WithOutput, stdout by default). Unlike WithSinks, a
+// line is never duplicated. An empty map removes the routing.
+func WithLevelRouting(routes map[LogLevel]io.Writer) Option {
+	return func(l *Logger) error {
+		if len(routes) == 0 {
+			l.levelRoutes = nil
+			return nil
+		}
+
+		sorted := make([]levelRoute, 0, len(routes))
+		for level, w := range routes {
+			if w == nil {
+				return errors.New("emit: level routing writer must not be nil")
+			}
+			sorted = append(sorted, levelRoute{minLevel: level, writer: w})
+		}
+		slices.SortFunc(sorted, func(a, b levelRoute) int { return int(a.minLevel) - int(b.minLevel) })
+		l.levelRoutes = sorted
+		return nil
+	}
+}
+
+// output returns the writer for an entry at level
+func (l *Logger) output(level LogLevel) io.Writer {
+	if route := l.route(level); route >= 0 {
+		return l.levelRoutes[route].writer
+	}
+	return l.writer
+}
+
+// route returns the index of the level route for level, or -1 for the
+// logger's output
+func (l *Logger) route(level LogLevel) int {
+	for i := len(l.levelRoutes) - 1; i >= 0; i-- {
+		if level >= l.levelRoutes[i].minLevel {
+			return i
+		}
+	}
+	return -1
+}
+
+// routedWriters returns the distinct routing writers other than the
+// logger's output, so each is flushed and closed once
+func (l *Logger) routedWriters() []io.Writer {
+	seen := []io.Writer{l.writer}
+	for _, route := range l.levelRoutes {
+		if !slices.ContainsFunc(seen, func(w io.Writer) bool { return sameWriter(w, route.writer) }) {
+			seen = append(seen, route.writer)
+		}
+	}
+	return seen[1:]
+}
+
+// sameWriter compares writers without panicking on uncomparable types
+func sameWriter(a, b io.Writer) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta.Comparable() && a == b
+}
+
+// syncRoutedWriters flushes the routing writers
+func (l *Logger) syncRoutedWriters() error {
+	var errs []error
+	for _, w := range l.routedWriters() {
+		errs = append(errs, syncWriter(w))
+	}
+	return errors.Join(errs...)
+}
+
+// closeRoutedWriters closes the routing writers, except stdout and stderr
+func (l *Logger) closeRoutedWriters() error {
+	var errs []error
+	for _, w := range l.routedWriters() {
+		if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
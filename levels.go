@@ -0,0 +1,111 @@
+package emit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// customLevel is a level added with RegisterLevel
+type customLevel struct {
+	name       string
+	levelBytes []byte // pre-encoded for the structured fields encoder
+}
+
+// customLevels maps registered levels to their names. The map is replaced,
+// never mutated, so encoders load it without locking.
+var (
+	customLevels   atomic.Pointer[map[LogLevel]customLevel]
+	customLevelsMu sync.Mutex
+)
+
+// RegisterLevel adds a named level, such as TRACE below DEBUG or NOTICE
+// between INFO and WARN. Built-in levels are four apart to leave room:
+//
+//	const (
+//		TRACE  emit.LogLevel = emit.DEBUG - 4
+//		NOTICE emit.LogLevel = emit.INFO + 2
+//	)
+//
+//	emit.RegisterLevel("trace", TRACE)
+//	emit.RegisterLevel("notice", NOTICE)
+//
+// Registered names are recognized by ParseLevel, SetLevel and EMIT_LEVEL and
+// written by every encoder, and levels filter by their numeric order. Names
+// are case-insensitive; registering a level again renames it.
+func RegisterLevel(name string, level LogLevel) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return errors.New("emit: level name must not be empty")
+	}
+	if _, err := parseBuiltinLevel(name); err == nil || isBuiltinLevel(level) {
+		return fmt.Errorf("emit: level %q conflicts with a built-in level", name)
+	}
+
+	customLevelsMu.Lock()
+	defer customLevelsMu.Unlock()
+
+	levels := make(map[LogLevel]customLevel)
+	if current := customLevels.Load(); current != nil {
+		maps.Copy(levels, *current)
+	}
+	for existing, custom := range levels {
+		if custom.name == name && existing != level {
+			return fmt.Errorf("emit: level %q is already registered", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`","level":`)
+	writeJSONString(&buf, name, false)
+	buf.WriteString(`,"message":"`)
+	levels[level] = customLevel{name: name, levelBytes: buf.Bytes()}
+	customLevels.Store(&levels)
+	return nil
+}
+
+// isBuiltinLevel reports whether level is one of the predefined levels
+func isBuiltinLevel(level LogLevel) bool {
+	switch level {
+	case DEBUG, INFO, WARN, ERROR, PANIC, FATAL:
+		return true
+	}
+	return false
+}
+
+// lookupCustomLevel returns a registered level
+func lookupCustomLevel(level LogLevel) (customLevel, bool) {
+	levels := customLevels.Load()
+	if levels == nil {
+		return customLevel{}, false
+	}
+	custom, ok := (*levels)[level]
+	return custom, ok
+}
+
+// parseCustomLevel finds a registered level by its lower-cased name
+func parseCustomLevel(name string) (LogLevel, bool) {
+	levels := customLevels.Load()
+	if levels == nil {
+		return 0, false
+	}
+	for level, custom := range *levels {
+		if custom.name == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// customLevelBytes returns the encoded level section for a registered level,
+// falling back to info like LogLevel.String
+func customLevelBytes(level LogLevel) []byte {
+	if custom, ok := lookupCustomLevel(level); ok {
+		return custom.levelBytes
+	}
+	return infoLevelBytes
+}
@@ -0,0 +1,126 @@
+package emit
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// loggerState tracks whether a logger has been closed. It is shared by a
+// logger and the children created from it with WithFields.
+type loggerState struct {
+	closed    atomic.Bool
+	closeOnce sync.Once
+	closeErr  error
+
+	// Set by Reconfigure; config is never modified once stored
+	config        atomic.Pointer[Logger]
+	level         atomic.Int32
+	reconfigureMu sync.Mutex
+	levelMu       sync.Mutex
+
+	// Last sequence number written by WithSequence
+	sequence atomic.Uint64
+}
+
+// syncer is implemented by writers that buffer data, such as *os.File
+type syncer interface {
+	Sync() error
+}
+
+// flusher is implemented by writers with an explicit flush, such as *bufio.Writer
+type flusher interface {
+	Flush() error
+}
+
+// isClosed reports whether Close has been called
+func (l *Logger) isClosed() bool {
+	return l.state != nil && l.state.closed.Load()
+}
+
+// Sync flushes pending deduplication summaries, waits for queued async
+// writes and flushes any data buffered by the writer. Errors from syncing
+// stdout or stderr are ignored because most terminals and pipes do not
+// support fsync.
+func (l *Logger) Sync() error {
+	if l.tee != nil {
+		return l.teeEach((*Logger).Sync)
+	}
+	l = l.resolve()
+	if l.dedup != nil {
+		l.dedup.flush()
+	}
+	if l.async != nil {
+		l.async.flush()
+	}
+	return errors.Join(syncWriter(l.writer), l.syncRoutedWriters())
+}
+
+// syncWriter flushes a writer if it supports Sync or Flush
+func syncWriter(w io.Writer) error {
+	switch v := w.(type) {
+	case flusher:
+		return v.Flush()
+	case syncer:
+		err := v.Sync()
+		if v == os.Stdout || v == os.Stderr {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Close flushes pending writes, stops background goroutines and closes the
+// writer if it implements io.Closer (stdout and stderr are left open).
+// Close is idempotent and safe to call concurrently, e.g. from a signal
+// handler. Logging after Close is a no-op, and Close on a child created with
+// WithFields closes the shared parent resources as well.
+func (l *Logger) Close() error {
+	if l.tee != nil {
+		l.state.closed.Store(true)
+		return l.teeEach((*Logger).Close)
+	}
+	l = l.resolve()
+	if l.state == nil {
+		return l.Sync()
+	}
+	l.state.closeOnce.Do(func() {
+		l.state.closed.Store(true)
+
+		var errs []error
+		if l.dedup != nil {
+			l.dedup.close()
+		}
+		if l.async != nil {
+			l.async.close()
+		}
+		errs = append(errs, syncWriter(l.writer))
+		if c, ok := l.writer.(io.Closer); ok && l.writer != os.Stdout && l.writer != os.Stderr {
+			errs = append(errs, c.Close())
+		}
+		errs = append(errs, l.syncRoutedWriters(), l.closeRoutedWriters())
+		l.state.closeErr = errors.Join(errs...)
+	})
+	return l.state.closeErr
+}
+
+// Sync flushes the default logger
+func Sync() error {
+	logger := Default()
+	if logger == nil {
+		return nil
+	}
+	return logger.Sync()
+}
+
+// Close closes the default logger; package-level logging becomes a no-op
+func Close() error {
+	logger := Default()
+	if logger == nil {
+		return nil
+	}
+	return logger.Close()
+}
@@ -0,0 +1,137 @@
+package emit
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"unicode/utf8"
+)
+
+// truncatedMarker ends string values cut by WithMaxValueLen
+const truncatedMarker = "...(truncated)"
+
+// maxDepthMarker replaces containers nested deeper than WithMaxDepth allows
+const maxDepthMarker = "...(max depth)"
+
+// defaultMaxDepth is the nesting masking descends into without WithMaxDepth
+const defaultMaxDepth = 32
+
+// fieldsDroppedKey holds the number of fields removed by WithMaxFields
+const fieldsDroppedKey = "fields_dropped"
+
+// WithMaxFields caps the number of top-level fields written per entry. When an
+// entry has more than n fields, the first n in key order are kept and the
+// rest are replaced by a "fields_dropped" count.
+func WithMaxFields(n int) Option {
+	return func(l *Logger) error {
+		if n <= 0 {
+			return errors.New("emit: max fields must be positive")
+		}
+		l.maxFields = n
+		return nil
+	}
+}
+
+// WithMaxDepth limits how deeply masking descends into nested maps, slices
+// and structs (32 levels by default). A container nested more than n levels
+// below the entry's fields is replaced by "...(max depth)", so deeply nested
+// values cannot exhaust the stack; self-references are replaced by
+// "[circular]" at any depth.
+func WithMaxDepth(n int) Option {
+	return func(l *Logger) error {
+		if n <= 0 {
+			return errors.New("emit: max depth must be positive")
+		}
+		l.maxDepth = n
+		return nil
+	}
+}
+
+// nestingLimit returns the number of nested levels masking descends into
+func (l *Logger) nestingLimit() int {
+	if l.maxDepth > 0 {
+		return l.maxDepth
+	}
+	return defaultMaxDepth
+}
+
+// WithMaxValueLen cuts string field values longer than n bytes, including
+// strings nested in maps and slices, and marks them with "...(truncated)".
+// Values are cut after masking, so a partial secret is never written, and
+// on a UTF-8 boundary.
+func WithMaxValueLen(n int) Option {
+	return func(l *Logger) error {
+		if n <= 0 {
+			return errors.New("emit: max value length must be positive")
+		}
+		l.maxValueLen = n
+		return nil
+	}
+}
+
+// limitFields applies the field count and value length limits to a masked
+// field map the caller owns
+func (l *Logger) limitFields(fields map[string]any) map[string]any {
+	if l.maxFields > 0 && len(fields) > l.maxFields {
+		keys := slices.Sorted(maps.Keys(fields))
+		for _, key := range keys[l.maxFields:] {
+			delete(fields, key)
+		}
+		fields[fieldsDroppedKey] = len(keys) - l.maxFields
+	}
+	if l.maxValueLen > 0 {
+		for key, value := range fields {
+			fields[key] = l.limitValue(value, 0)
+		}
+	}
+	return fields
+}
+
+// limitValue truncates long strings in value, copying containers it changes
+func (l *Logger) limitValue(value any, depth int) any {
+	if depth > jsonSafeMaxDepth {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return l.truncate(v)
+	case map[string]any:
+		limited := make(map[string]any, len(v))
+		for key, element := range v {
+			limited[key] = l.limitValue(element, depth+1)
+		}
+		return limited
+	case Fields:
+		limited := make(map[string]any, len(v))
+		for key, element := range v {
+			limited[key] = l.limitValue(element, depth+1)
+		}
+		return limited
+	case []any:
+		limited := make([]any, len(v))
+		for i, element := range v {
+			limited[i] = l.limitValue(element, depth+1)
+		}
+		return limited
+	case []string:
+		limited := make([]string, len(v))
+		for i, element := range v {
+			limited[i] = l.truncate(element)
+		}
+		return limited
+	}
+	return value
+}
+
+// truncate cuts s to the maximum value length on a rune boundary. Mask
+// strings are kept whole so masked values stay recognizable.
+func (l *Logger) truncate(s string) string {
+	if len(s) <= l.maxValueLen || s == l.maskString || s == l.piiMaskString {
+		return s
+	}
+	cut := l.maxValueLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncatedMarker
+}
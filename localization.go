@@ -0,0 +1,60 @@
+package emit
+
+import (
+	"errors"
+	"maps"
+)
+
+// MessageTranslator returns the message to write for an entry
+type MessageTranslator func(level LogLevel, message string) string
+
+// WithMessageTranslator rewrites each message just before it is encoded, for
+// audit logs read by people in their own language:
+//
+//	emit.WithMessageTranslator(func(level emit.LogLevel, msg string) string {
+//		return catalog.Translate(locale, msg)
+//	})
+//
+// Level filtering, sampling and deduplication see the original message, so
+// translation changes what is written, never what is logged. Hooks and the
+// memory sink receive the translated message.
+func WithMessageTranslator(translate MessageTranslator) Option {
+	return func(l *Logger) error {
+		l.translator = translate
+		return nil
+	}
+}
+
+// WithLevelNames replaces the level names written to JSON, ECS, logfmt,
+// plain and console output, e.g. {emit.INFO: "INFORMATION"}. Levels not in
+// names keep their usual name, and the console format writes overridden
+// names as given instead of upper-casing them. GCP severities are fixed by
+// Cloud Logging and are not affected. Parsing, such as ParseLevel and
+// EMIT_LEVEL, still uses the usual names.
+func WithLevelNames(names map[LogLevel]string) Option {
+	return func(l *Logger) error {
+		for _, name := range names {
+			if name == "" {
+				return errors.New("emit: level name must not be empty")
+			}
+		}
+		l.levelNames = maps.Clone(names)
+		return nil
+	}
+}
+
+// translate applies the message translator, if any
+func (l *Logger) translate(level LogLevel, message string) string {
+	if l.translator == nil {
+		return message
+	}
+	return l.translator(level, message)
+}
+
+// levelName returns the name written for level
+func (l *Logger) levelName(level LogLevel) string {
+	if name, ok := l.levelNames[level]; ok {
+		return name
+	}
+	return level.StringFast()
+}
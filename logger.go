@@ -1,37 +1,114 @@
 package emit
 
-import "os"
+import "sync/atomic"
 
-// Global logger instance
-var defaultLogger *Logger
+// defaultLogger holds the logger behind the package-level functions. It is
+// loaded on every call, so it is swapped atomically rather than locked.
+var defaultLogger atomic.Pointer[Logger]
 
 // init initializes a default logger
 func init() {
-	defaultLogger = &Logger{
-		level:           INFO,
-		writer:          os.Stdout,
-		showCaller:      false,
-		format:          JSON_FORMAT,    // JSON is default
-		sensitiveMode:   MASK_SENSITIVE, // Mask sensitive data by default
-		piiMode:         MASK_PII,       // Mask PII data by default
-		sensitiveFields: defaultSensitiveFields,
-		piiFields:       defaultPIIFields,
-		maskString:      "***MASKED***",
-		piiMaskString:   "***PII***",
-	}
+	defaultLogger.Store(newLogger())
 
 	// Initialize from environment variables
 	initFromEnvironment()
 }
 
+// Default returns the logger used by the package-level functions, such as
+// emit.Info.Msg, emit.Infof and emit.Named. Unless replaced with SetDefault it
+// writes JSON to stdout with sensitive and PII masking on.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault makes logger the target of the package-level functions. It is
+// safe to call while other goroutines are logging; entries already in
+// progress finish on the previous logger. Package-level setters such as
+// SetLevel and SetComponent affect the current default. A nil logger is
+// ignored.
+func SetDefault(logger *Logger) {
+	if logger != nil {
+		defaultLogger.Store(logger)
+	}
+}
+
+// callerSkip is the number of frames between an encoder's runtime.Caller call
+// and the user's logging call: encoder, writeEntry, log, logContext, level method
+const callerSkip = 5
+
 // log writes a log entry at the specified level
 func (l *Logger) log(level LogLevel, message string, fields map[string]any) {
-	if level < l.level {
+	if !l.Enabled(level) {
+		return
+	}
+	if l.tee != nil {
+		l.teeLog(level, message, fields)
+		return
+	}
+	l = l.resolve()
+
+	fields = l.withSource(l.withBaseFields(fields))
+
+	if l.traceSampler != nil && !l.traceSampler.allow(fields) {
+		if l.stats != nil {
+			l.stats.sampled.Add(1)
+		}
+		return
+	}
+
+	if l.sampler != nil && !l.sampler.allow(level, message, fields) {
+		if l.stats != nil {
+			l.stats.sampled.Add(1)
+		}
+		return
+	}
+
+	fields = l.handleByteSlices(resolveGroupedLazyFields(level, fields, l.groups))
+
+	if l.dedup != nil && l.dedup.suppress(l, level, message, fields) {
+		if l.stats != nil {
+			l.stats.deduplicated.Add(1)
+		}
+		return
+	}
+
+	if l.schema != nil {
+		fields = l.validateSchema(fields)
+	}
+
+	fields = l.addStackTrace(level, fields)
+	fields = l.addCaller(level, fields)
+
+	if l.strictMasking {
+		if err := l.checkStrictMasking(fields); err != nil {
+			panic(err)
+		}
+	}
+
+	if l.scanLimit != nil {
+		l = l.withScanBudget()
+		defer l.noteScanTruncated()
+	}
+
+	message = l.translate(level, message)
+
+	if l.processesEntries() {
+		l.writeProcessedEntry(level, message, fields)
+		return
+	}
+	l.writeEntry(level, message, fields)
+}
+
+// writeEntry encodes and writes an entry in the configured format
+func (l *Logger) writeEntry(level LogLevel, message string, fields map[string]any) {
+	fields = l.withSequence(fields)
+	if l.encoder != nil {
+		l.logEncoded(level, message, fields)
 		return
 	}
 
 	// Ultra-fast path for simple messages (no fields) - OPTIMIZED FOR SPEED
-	if len(fields) == 0 {
+	if !l.hasFields(fields) && (l.format == JSON_FORMAT || l.format == PLAIN_FORMAT) && !l.customTimestamp() && l.levelNames == nil {
 		l.logSimpleUltraFast(level, message)
 		return
 	}
@@ -39,6 +116,18 @@ func (l *Logger) log(level LogLevel, message string, fields map[string]any) {
 	// Route to appropriate formatter based on format setting and field complexity
 	if l.format == PLAIN_FORMAT {
 		l.logPlain(level, message, fields)
+	} else if l.format == LOGFMT_FORMAT {
+		l.logLogfmt(level, message, fields)
+	} else if l.format == CONSOLE_FORMAT {
+		l.logConsole(level, message, fields)
+	} else if l.format == ECS_FORMAT {
+		l.logECS(level, message, fields)
+	} else if l.format == GCP_FORMAT {
+		l.logGCP(level, message, fields)
+	} else if l.streamingEncoder || l.customTimestamp() || l.customValueFormat() {
+		// LogEntry has a fixed timestamp key and encoding/json formats values
+		// itself, so custom timestamps and value formats are streamed
+		l.logJSONStreaming(level, message, fields)
 	} else {
 		// JSON format
 		l.logJSON(level, message, fields)
@@ -54,6 +143,10 @@ func (l *Logger) logSimpleUltraFast(level LogLevel, message string) {
 
 	// First attempt with stack buffer
 	if l.format == JSON_FORMAT {
+		if !l.jsonSafeMetadata(message) {
+			l.logJSON(level, message, nil)
+			return
+		}
 		pos = l.buildSimpleJSONUltraFast(buf, level, message)
 	} else {
 		pos = l.buildSimplePlainUltraFast(buf, level, message)
@@ -67,7 +160,10 @@ func (l *Logger) logSimpleUltraFast(level LogLevel, message string) {
 		} else {
 			estimatedSize = l.estimatePlainSize(level, message)
 		}
-		dynamicBuf := make([]byte, estimatedSize)
+		lineBuf := getLineBuffer()
+		defer putLineBuffer(lineBuf)
+		lineBuf.Grow(estimatedSize)
+		dynamicBuf := lineBuf.Bytes()[:estimatedSize]
 
 		if l.format == JSON_FORMAT {
 			pos = l.buildSimpleJSONUltraFast(dynamicBuf, level, message)
@@ -89,12 +185,12 @@ func (l *Logger) logSimpleUltraFast(level LogLevel, message string) {
 	}
 
 	// Single write operation - most critical optimization
-	_, _ = l.writer.Write(buf[:pos])
+	l.writeLine(level, buf[:pos])
 }
 
 // InfoStructured logs at INFO level with structured fields optimization
 func InfoStructured(message string, fields ...ZField) {
-	defaultLogger.InfoStructured(message, fields...)
+	Default().InfoStructured(message, fields...)
 }
 
 func (l *Logger) InfoStructured(message string, fields ...ZField) {
@@ -103,7 +199,7 @@ func (l *Logger) InfoStructured(message string, fields ...ZField) {
 
 // DebugStructured logs at DEBUG level with structured fields optimization
 func DebugStructured(message string, fields ...ZField) {
-	defaultLogger.DebugStructured(message, fields...)
+	Default().DebugStructured(message, fields...)
 }
 
 func (l *Logger) DebugStructured(message string, fields ...ZField) {
@@ -112,7 +208,7 @@ func (l *Logger) DebugStructured(message string, fields ...ZField) {
 
 // WarnStructured logs at WARN level with structured fields optimization
 func WarnStructured(message string, fields ...ZField) {
-	defaultLogger.WarnStructured(message, fields...)
+	Default().WarnStructured(message, fields...)
 }
 
 func (l *Logger) WarnStructured(message string, fields ...ZField) {
@@ -121,7 +217,7 @@ func (l *Logger) WarnStructured(message string, fields ...ZField) {
 
 // ErrorStructured logs at ERROR level with structured fields optimization
 func ErrorStructured(message string, fields ...ZField) {
-	defaultLogger.ErrorStructured(message, fields...)
+	Default().ErrorStructured(message, fields...)
 }
 
 func (l *Logger) ErrorStructured(message string, fields ...ZField) {
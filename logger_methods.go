@@ -0,0 +1,66 @@
+package emit
+
+import (
+	"context"
+	"maps"
+)
+
+// Info logs an info message. Fields may be Fields or map[string]any values,
+// ZField values, or alternating key-value pairs.
+func (l *Logger) Info(message string, fields ...any) {
+	l.logContext(context.Background(), INFO, message, fields...)
+}
+
+// Error logs an error message with optional fields
+func (l *Logger) Error(message string, fields ...any) {
+	l.logContext(context.Background(), ERROR, message, fields...)
+}
+
+// Warn logs a warn message with optional fields
+func (l *Logger) Warn(message string, fields ...any) {
+	l.logContext(context.Background(), WARN, message, fields...)
+}
+
+// Debug logs a debug message with optional fields
+func (l *Logger) Debug(message string, fields ...any) {
+	l.logContext(context.Background(), DEBUG, message, fields...)
+}
+
+// WithFields returns a child logger that includes fields on every entry.
+// The child shares its parent's configuration; base fields are added beneath
+// call-site fields and are masked like any other field. Calls can be chained.
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	if l.tee != nil {
+		return l.teeMap(func(child *Logger) *Logger { return child.WithFields(fields) })
+	}
+	child := l.derive()
+
+	if len(fields) > 0 && len(l.groups) > 0 {
+		child.baseFields = l.mergeGrouped(l.baseFields, fields)
+	} else if len(fields) > 0 {
+		baseFields := make(map[string]any, len(l.baseFields)+len(fields))
+		maps.Copy(baseFields, l.baseFields)
+		maps.Copy(baseFields, fields)
+		child.baseFields = baseFields
+	}
+
+	return child
+}
+
+// withBaseFields merges the logger's base fields beneath the call-site fields
+func (l *Logger) withBaseFields(fields map[string]any) map[string]any {
+	if len(l.groups) > 0 && len(fields) > 0 {
+		return l.mergeGrouped(l.baseFields, fields)
+	}
+	if len(l.baseFields) == 0 {
+		return fields
+	}
+	if len(fields) == 0 {
+		return l.baseFields
+	}
+
+	merged := make(map[string]any, len(l.baseFields)+len(fields))
+	maps.Copy(merged, l.baseFields)
+	maps.Copy(merged, fields)
+	return merged
+}
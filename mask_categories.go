@@ -0,0 +1,151 @@
+package emit
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Default financial field patterns (case-insensitive)
+var defaultFinancialFields = []string{
+	"credit_card", "creditcard", "card_number", "cardnumber", "ccn", "cvv", "cvc",
+	"iban", "bic", "swift", "account_number", "bank_account", "routing_number", "sort_code",
+	"salary", "income", "balance", "tax_id",
+}
+
+// Default health field patterns (case-insensitive)
+var defaultHealthFields = []string{
+	"diagnosis", "medical_record", "mrn", "patient_id", "medication", "prescription",
+	"treatment", "allergy", "allergies", "blood_type", "health_insurance",
+	"icd_code", "symptoms", "lab_result",
+}
+
+// CategoryOption customizes a category configured with WithMaskCategory
+type CategoryOption func(*categoryRule)
+
+// categoryRule detects and masks the fields of one category
+type categoryRule struct {
+	category   MaskCategory
+	patterns   map[string]bool
+	maskString string
+	mode       SensitiveDataMode
+	cache      sync.Map // map[string]bool
+}
+
+// WithMaskCategory detects the fields of an extra category, MaskCategoryFinancial
+// or MaskCategoryHealth, and masks them with their own mask string and mode,
+// so health data can be dropped for HIPAA while financial data is hashed for
+// reconciliation:
+//
+//	emit.WithMaskCategory(emit.MaskCategoryHealth, emit.CategoryMode(emit.DROP_SENSITIVE))
+//	emit.WithMaskCategory(emit.MaskCategoryFinancial, emit.CategoryMode(emit.HASH_SENSITIVE),
+//		emit.CategoryFields("invoice_total"))
+//
+// Field names are matched like PII names against DefaultFinancialFields or
+// DefaultHealthFields and any CategoryFields. A category is more specific
+// than PII and sensitive data, so a field in one is masked as that category
+// even when it is also a PII or sensitive name, with health checked first.
+// Fields are masked with "***FINANCIAL***" or "***HEALTH***" by default.
+// SHOW_SENSITIVE turns the category off, leaving its fields to the usual
+// detection. Mask functions, Secret and PII values and WithNeverMask still
+// take precedence.
+func WithMaskCategory(category MaskCategory, opts ...CategoryOption) Option {
+	return func(l *Logger) error {
+		rule := &categoryRule{category: category}
+		switch category {
+		case MaskCategoryFinancial:
+			rule.patterns = buildFieldMap(defaultFinancialFields)
+			rule.maskString = "***FINANCIAL***"
+		case MaskCategoryHealth:
+			rule.patterns = buildFieldMap(defaultHealthFields)
+			rule.maskString = "***HEALTH***"
+		default:
+			return errors.New("emit: mask category must be financial or health")
+		}
+		for _, opt := range opts {
+			opt(rule)
+		}
+
+		categories := slices.DeleteFunc(slices.Clone(l.maskCategories), func(r *categoryRule) bool {
+			return r.category == category
+		})
+		categories = append(categories, rule)
+		// Health is the most specific category and is checked first
+		slices.SortFunc(categories, func(a, b *categoryRule) int {
+			return int(b.category) - int(a.category)
+		})
+		l.maskCategories = categories
+		return nil
+	}
+}
+
+// CategoryFields adds field name patterns to the category's defaults
+func CategoryFields(fields ...string) CategoryOption {
+	return func(r *categoryRule) {
+		patterns := buildFieldMap(fields)
+		for pattern := range r.patterns {
+			patterns[pattern] = true
+		}
+		r.patterns = patterns
+	}
+}
+
+// CategoryMaskString sets the string the category's fields are masked with
+func CategoryMaskString(mask string) CategoryOption {
+	return func(r *categoryRule) {
+		r.maskString = mask
+	}
+}
+
+// CategoryMode sets how the category's fields are written: masked, hashed,
+// dropped or, with SHOW_SENSITIVE, left to the usual detection
+func CategoryMode(mode SensitiveDataMode) CategoryOption {
+	return func(r *categoryRule) {
+		r.mode = mode
+	}
+}
+
+// DefaultFinancialFields returns the field patterns detected as financial data
+func DefaultFinancialFields() []string {
+	return slices.Clone(defaultFinancialFields)
+}
+
+// DefaultHealthFields returns the field patterns detected as health data
+func DefaultHealthFields() []string {
+	return slices.Clone(defaultHealthFields)
+}
+
+// categoryFor returns the most specific category a field belongs to, or nil
+func (l *Logger) categoryFor(fieldName string) *categoryRule {
+	if len(l.maskCategories) == 0 || l.isMaskExempt(fieldName) {
+		return nil
+	}
+	for _, rule := range l.maskCategories {
+		if rule.mode != SHOW_SENSITIVE && rule.matches(fieldName) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matches reports whether a field name is in the category, caching the result
+func (r *categoryRule) matches(fieldName string) bool {
+	if cached, ok := r.cache.Load(fieldName); ok {
+		return cached.(bool)
+	}
+	matched := matchPIIPattern(r.patterns, fieldName)
+	r.cache.Store(fieldName, matched)
+	return matched
+}
+
+// mask returns what is written for a field of the category
+func (r *categoryRule) mask(l *Logger, key string, value any) any {
+	l.observeMask(key, r.category)
+	switch r.mode {
+	case DROP_SENSITIVE:
+		return droppedField{}
+	case HASH_SENSITIVE:
+		return l.hashValue(value)
+	}
+	return r.maskString
+}
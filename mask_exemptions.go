@@ -0,0 +1,97 @@
+package emit
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// maskExemptions holds case-folded field names that are never masked by name.
+// The map is replaced, never mutated, so readers load it without locking.
+var (
+	maskExemptions   atomic.Pointer[map[string]bool]
+	maskExemptionsMu sync.Mutex
+)
+
+// AddMaskExemption exempts exact field names from name-based and value
+// pattern detection, for metadata that trips a pattern, such as "key_type"
+// matching "key". Exemptions apply to every logger without its own list (see
+// WithNeverMask). Mask paths, custom mask functions and `log:"mask"` tags
+// still apply.
+func AddMaskExemption(fields ...string) {
+	updateMaskExemptions(func(exempt map[string]bool) {
+		for _, field := range fields {
+			exempt[foldFieldName(field)] = true
+		}
+	})
+}
+
+// RemoveMaskExemption removes names added with AddMaskExemption
+func RemoveMaskExemption(fields ...string) {
+	updateMaskExemptions(func(exempt map[string]bool) {
+		for _, field := range fields {
+			delete(exempt, foldFieldName(field))
+		}
+	})
+}
+
+// updateMaskExemptions publishes a modified copy of the global exemptions
+func updateMaskExemptions(update func(map[string]bool)) {
+	maskExemptionsMu.Lock()
+	defer maskExemptionsMu.Unlock()
+
+	exempt := make(map[string]bool)
+	if current := maskExemptions.Load(); current != nil {
+		maps.Copy(exempt, *current)
+	}
+	update(exempt)
+	maskExemptions.Store(&exempt)
+}
+
+// WithNeverMask gives the logger its own exemption list, replacing the
+// global one from AddMaskExemption
+func WithNeverMask(fields ...string) Option {
+	return func(l *Logger) error {
+		exempt := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			exempt[foldFieldName(field)] = true
+		}
+		l.rules().setExemptions(exempt)
+		return nil
+	}
+}
+
+// setExemptions replaces the per-logger exemptions. They are checked before
+// the detection caches, so cached results stay valid.
+func (r *loggerFieldRules) setExemptions(exempt map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exemptFields = exempt
+}
+
+// exemptions returns the per-logger exemptions, nil when the global list applies
+func (r *loggerFieldRules) exemptions() map[string]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.exemptFields
+}
+
+// isMaskExempt reports whether a field name is exempt from detection
+func (l *Logger) isMaskExempt(fieldName string) bool {
+	exempt := l.maskExemptions()
+	return len(exempt) > 0 && exempt[foldFieldName(fieldName)]
+}
+
+// maskExemptions returns the exemption list in effect for the logger
+func (l *Logger) maskExemptions() map[string]bool {
+	if l.fieldRules != nil {
+		if exempt := l.fieldRules.exemptions(); exempt != nil {
+			return exempt
+		}
+	}
+	if global := maskExemptions.Load(); global != nil {
+		return *global
+	}
+	return nil
+}
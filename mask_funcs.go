@@ -0,0 +1,121 @@
+package emit
+
+import (
+	"strings"
+	"sync"
+)
+
+// MaskFunc transforms a field value into its masked representation
+type MaskFunc func(value any) any
+
+// maskFuncRegistry holds custom masking functions registered on a logger
+type maskFuncRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]MaskFunc // lower-cased field name or pattern -> mask function
+	cache map[string]MaskFunc // field name -> resolved mask function (nil when none matches)
+}
+
+// registryInitMu guards lazy creation of mask function registries
+var registryInitMu sync.Mutex
+
+// newMaskFuncRegistry creates an empty mask function registry
+func newMaskFuncRegistry() *maskFuncRegistry {
+	return &maskFuncRegistry{
+		funcs: make(map[string]MaskFunc),
+		cache: make(map[string]MaskFunc, 100),
+	}
+}
+
+// register adds or replaces the mask function for a field name or pattern
+func (r *maskFuncRegistry) register(pattern string, fn MaskFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if fn == nil {
+		delete(r.funcs, pattern)
+	} else {
+		r.funcs[pattern] = fn
+	}
+
+	// Registrations change resolution results, so drop cached lookups
+	r.cache = make(map[string]MaskFunc, 100)
+}
+
+// lookup resolves the mask function for a field name, caching the result
+func (r *maskFuncRegistry) lookup(fieldName string) MaskFunc {
+	r.mu.RLock()
+	if len(r.funcs) == 0 {
+		r.mu.RUnlock()
+		return nil
+	}
+	if fn, exists := r.cache[fieldName]; exists {
+		r.mu.RUnlock()
+		return fn
+	}
+	r.mu.RUnlock()
+
+	// Resolve and cache under the write lock so a concurrent register cannot
+	// leave a stale entry behind
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if fn, exists := r.cache[fieldName]; exists {
+		return fn
+	}
+
+	// Exact match first, then the longest pattern contained in the field name
+	lowerFieldName := foldFieldName(fieldName)
+	fn := r.funcs[lowerFieldName]
+	if fn == nil {
+		longest := 0
+		for pattern, candidate := range r.funcs {
+			if len(pattern) > longest && strings.Contains(lowerFieldName, pattern) {
+				fn = candidate
+				longest = len(pattern)
+			}
+		}
+	}
+	r.cache[fieldName] = fn
+
+	return fn
+}
+
+// empty reports whether no mask function is registered
+func (r *maskFuncRegistry) empty() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.funcs) == 0
+}
+
+// hasMaskFuncs reports whether the logger has any mask function registered
+func (l *Logger) hasMaskFuncs() bool {
+	return l.maskFuncs != nil && !l.maskFuncs.empty()
+}
+
+// maskFuncFor returns the custom mask function registered for a field, if any
+func (l *Logger) maskFuncFor(fieldName string) MaskFunc {
+	if l.maskFuncs == nil {
+		return nil
+	}
+	return l.maskFuncs.lookup(fieldName)
+}
+
+// RegisterMaskFunc registers a custom masking function for a field name or pattern.
+// Registered functions take precedence over the default PII and sensitive masking.
+// Passing a nil function removes a previous registration.
+func (l *Logger) RegisterMaskFunc(pattern string, fn MaskFunc) {
+	registryInitMu.Lock()
+	if l.maskFuncs == nil {
+		l.maskFuncs = newMaskFuncRegistry()
+	}
+	registryInitMu.Unlock()
+
+	l.maskFuncs.register(foldFieldName(pattern), fn)
+}
+
+// RegisterMaskFunc registers a custom masking function on the default logger
+func RegisterMaskFunc(pattern string, fn MaskFunc) {
+	if logger := Default(); logger != nil {
+		logger.RegisterMaskFunc(pattern, fn)
+	}
+}
@@ -0,0 +1,87 @@
+package emit
+
+import "regexp"
+
+// MaskOption customizes how MaskMap masks a field map
+type MaskOption func(*Logger)
+
+// MaskWithString sets the string used to mask sensitive fields
+func MaskWithString(mask string) MaskOption {
+	return func(l *Logger) {
+		l.maskString = mask
+	}
+}
+
+// MaskWithPIIString sets the string used to mask PII fields
+func MaskWithPIIString(mask string) MaskOption {
+	return func(l *Logger) {
+		l.piiMaskString = mask
+	}
+}
+
+// MaskWithPartial enables partial masking for sensitive fields
+func MaskWithPartial(mask PartialMasking) MaskOption {
+	return func(l *Logger) {
+		l.partialMask = mask
+	}
+}
+
+// MaskWithPIIPartial enables partial masking for PII fields
+func MaskWithPIIPartial(mask PartialMasking) MaskOption {
+	return func(l *Logger) {
+		l.piiPartialMask = mask
+	}
+}
+
+// MaskWithHash replaces sensitive values with salted hashes instead of the mask string
+func MaskWithHash(salt []byte) MaskOption {
+	return func(l *Logger) {
+		l.sensitiveMode = HASH_SENSITIVE
+		l.SetHashSalt(salt)
+	}
+}
+
+// MaskWithValuePatterns enables value-content detection with the given patterns
+func MaskWithValuePatterns(patterns ...*regexp.Regexp) MaskOption {
+	return func(l *Logger) {
+		for _, pattern := range patterns {
+			l.AddValuePattern(pattern)
+		}
+		l.SetValuePatternDetection(true)
+	}
+}
+
+// MaskWithoutPII leaves PII fields visible and only masks sensitive fields
+func MaskWithoutPII() MaskOption {
+	return func(l *Logger) {
+		l.piiMode = SHOW_PII
+	}
+}
+
+// MaskWithoutSensitive leaves sensitive fields visible and only masks PII fields
+func MaskWithoutSensitive() MaskOption {
+	return func(l *Logger) {
+		l.sensitiveMode = SHOW_SENSITIVE
+	}
+}
+
+// MaskMap returns a masked copy of fields using the same detection rules as the
+// logger, without writing any log output. The global detection lists and caches
+// are shared with all loggers, so it is suitable for sanitizing configuration
+// dumps or payloads sent to third-party telemetry.
+func MaskMap(fields map[string]any, opts ...MaskOption) map[string]any {
+	masker := Logger{
+		sensitiveMode:   MASK_SENSITIVE,
+		piiMode:         MASK_PII,
+		sensitiveFields: defaultSensitiveFields,
+		piiFields:       defaultPIIFields,
+		maskString:      defaultMaskString,
+		piiMaskString:   defaultPIIMaskString,
+	}
+
+	for _, opt := range opts {
+		opt(&masker)
+	}
+
+	return masker.maskSensitiveFieldsFast(fields)
+}
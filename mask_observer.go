@@ -0,0 +1,58 @@
+package emit
+
+// MaskCategory identifies why a field was masked
+type MaskCategory int
+
+const (
+	MaskCategorySensitive MaskCategory = iota // Sensitive field name, value pattern, mask path or `log:"mask"` tag
+	MaskCategoryPII                           // PII field name
+	MaskCategoryCustom                        // Custom mask function
+	MaskCategoryFinancial                     // Financial field name, with WithMaskCategory
+	MaskCategoryHealth                        // Health field name, with WithMaskCategory
+)
+
+// String returns the category name
+func (c MaskCategory) String() string {
+	switch c {
+	case MaskCategorySensitive:
+		return "sensitive"
+	case MaskCategoryPII:
+		return "pii"
+	case MaskCategoryCustom:
+		return "custom"
+	case MaskCategoryFinancial:
+		return "financial"
+	case MaskCategoryHealth:
+		return "health"
+	default:
+		return "unknown"
+	}
+}
+
+// MaskObserver is called with the key of each field that is masked or dropped
+type MaskObserver func(fieldName string, category MaskCategory)
+
+// WithMaskObserver reports every masked field, for example to count them by
+// name and find code paths that log secrets by accident:
+//
+//	emit.WithMaskObserver(func(field string, category emit.MaskCategory) {
+//		maskedFields.WithLabelValues(field, category.String()).Inc()
+//	})
+//
+// The observer runs synchronously while entries are encoded, possibly from
+// many goroutines at once, so it must be fast and safe for concurrent use.
+// Nested fields report their own key.
+func WithMaskObserver(observer MaskObserver) Option {
+	return func(l *Logger) error {
+		l.maskObserver = observer
+		return nil
+	}
+}
+
+// observeMask counts a masked field and notifies the mask observer, if any
+func (l *Logger) observeMask(fieldName string, category MaskCategory) {
+	l.stats.countMasked()
+	if l.maskObserver != nil {
+		l.maskObserver(fieldName, category)
+	}
+}
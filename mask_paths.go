@@ -0,0 +1,196 @@
+package emit
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maskPaths holds the registered paths split into case-folded segments. The
+// slice is replaced, never mutated, so readers load it without locking.
+var (
+	maskPaths   atomic.Pointer[[][]string]
+	maskPathsMu sync.Mutex
+)
+
+// AddMaskPath masks the values at dotted paths into nested fields, masking
+// "user.payment.card" without touching a top-level "card_hint". A "*"
+// segment matches any array index or key, as in "orders.*.card"; numeric
+// segments match a single index. Path matches are masked as sensitive data.
+// Paths apply to every logger.
+func AddMaskPath(paths ...string) {
+	updateMaskPaths(func(current [][]string) [][]string {
+		for _, path := range paths {
+			segments := splitMaskPath(path)
+			if segments == nil || containsMaskPath(current, segments) {
+				continue
+			}
+			current = append(current, segments)
+		}
+		return current
+	})
+}
+
+// RemoveMaskPath removes paths added with AddMaskPath
+func RemoveMaskPath(paths ...string) {
+	updateMaskPaths(func(current [][]string) [][]string {
+		return slices.DeleteFunc(current, func(segments []string) bool {
+			for _, path := range paths {
+				if slices.Equal(segments, splitMaskPath(path)) {
+					return true
+				}
+			}
+			return false
+		})
+	})
+}
+
+// updateMaskPaths publishes a modified copy of the path list
+func updateMaskPaths(update func([][]string) [][]string) {
+	maskPathsMu.Lock()
+	defer maskPathsMu.Unlock()
+
+	var current [][]string
+	if loaded := maskPaths.Load(); loaded != nil {
+		current = slices.Clone(*loaded)
+	}
+	updated := update(current)
+	maskPaths.Store(&updated)
+}
+
+// splitMaskPath splits a dotted path into case-folded segments
+func splitMaskPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if segment == "" {
+			return nil
+		}
+		segments[i] = foldFieldName(segment)
+	}
+	return segments
+}
+
+func containsMaskPath(paths [][]string, segments []string) bool {
+	return slices.ContainsFunc(paths, func(p []string) bool { return slices.Equal(p, segments) })
+}
+
+// hasMaskPaths reports whether any mask path is registered
+func hasMaskPaths() bool {
+	paths := maskPaths.Load()
+	return paths != nil && len(*paths) > 0
+}
+
+// applyMaskPaths marks values at registered paths for masking. The field map
+// and nested containers are copied only along matching paths.
+func (l *Logger) applyMaskPaths(fields map[string]any) map[string]any {
+	paths := maskPaths.Load()
+	if paths == nil || len(*paths) == 0 || len(fields) == 0 || l.sensitiveMode == SHOW_SENSITIVE {
+		return fields
+	}
+	masked, _ := maskPathsInMap(fields, *paths)
+	return masked
+}
+
+// maskPathsInMap applies paths to the members of a map
+func maskPathsInMap(fields map[string]any, paths [][]string) (map[string]any, bool) {
+	var result map[string]any
+	for key, value := range fields {
+		next, changed := maskPathsAt(value, paths, foldFieldName(key), "")
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = maps.Clone(fields)
+		}
+		result[key] = next
+	}
+	if result == nil {
+		return fields, false
+	}
+	return result, true
+}
+
+// maskPathsAt applies the paths whose first segment matches key (or index)
+// to value, returning the replacement when something was masked
+func maskPathsAt(value any, paths [][]string, key, index string) (any, bool) {
+	var rest [][]string
+	for _, path := range paths {
+		segment := path[0]
+		if segment != "*" && segment != key && segment != index {
+			continue
+		}
+		if len(path) == 1 {
+			return taggedMask{value: value}, true
+		}
+		rest = append(rest, path[1:])
+	}
+	if rest == nil {
+		return value, false
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return maskPathsInMap(v, rest)
+	case Fields:
+		masked, changed := maskPathsInMap(v, rest)
+		return Fields(masked), changed
+	case []any:
+		var result []any
+		for i, element := range v {
+			next, changed := maskPathsAt(element, rest, "", strconv.Itoa(i))
+			if !changed {
+				continue
+			}
+			if result == nil {
+				result = slices.Clone(v)
+			}
+			result[i] = next
+		}
+		if result == nil {
+			return value, false
+		}
+		return result, true
+	case []map[string]any:
+		var result []map[string]any
+		for i, element := range v {
+			next, changed := maskPathsInMap(element, pathsForIndex(rest, i))
+			if !changed {
+				continue
+			}
+			if result == nil {
+				result = slices.Clone(v)
+			}
+			result[i] = next
+		}
+		if result == nil {
+			return value, false
+		}
+		return result, true
+	}
+	return value, false
+}
+
+// pathsForIndex returns the tails of paths whose first segment matches index
+// i. A path ending at the element itself cannot be represented in a
+// []map[string]any, so those match every key of the element instead.
+func pathsForIndex(paths [][]string, i int) [][]string {
+	index := strconv.Itoa(i)
+	var rest [][]string
+	for _, path := range paths {
+		if path[0] != "*" && path[0] != index {
+			continue
+		}
+		if len(path) == 1 {
+			rest = append(rest, []string{"*"})
+			continue
+		}
+		rest = append(rest, path[1:])
+	}
+	return rest
+}
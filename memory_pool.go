@@ -1,6 +1,7 @@
 package emit
 
 import (
+	"bytes"
 	"sync"
 	"time"
 )
@@ -14,8 +15,65 @@ var (
 			return make(map[string]any, 8) // Pre-allocate for common field count
 		},
 	}
+
+	// Pool for byte buffers used when encoding log lines
+	lineBufferPool = sync.Pool{
+		New: func() any {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}
 )
 
+// maxPooledBufferSize keeps unusually large buffers from being retained by the pool
+const maxPooledBufferSize = 64 * 1024
+
+// getLineBuffer gets an empty buffer from the pool
+func getLineBuffer() *bytes.Buffer {
+	buf := lineBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putLineBuffer returns a buffer to the pool
+func putLineBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		lineBufferPool.Put(buf)
+	}
+}
+
+// BufferRetainer is implemented by writers that keep the slice passed to Write
+// after the call returns, for example by queueing it for a background
+// goroutine. Log lines are encoded into pooled buffers that are reused as soon
+// as Write returns, so writers reporting true receive a private copy instead.
+type BufferRetainer interface {
+	RetainsBuffer() bool
+}
+
+// writeLine writes an encoded log line, copying it first when the writer
+// retains buffers past the Write call or the line is queued for async writing.
+// Lines logged inside Batch are held until the batch is written.
+func (l *Logger) writeLine(level LogLevel, line []byte) {
+	if l.prettyJSON && l.encodesJSON() {
+		pretty := getLineBuffer()
+		defer putLineBuffer(pretty)
+		line = prettyLine(pretty, line)
+	}
+	l.stats.countEmitted(level)
+	if l.batch != nil {
+		l.batch.add(l.route(level), level, line)
+		return
+	}
+	w := l.output(level)
+	if l.async != nil {
+		l.async.enqueue(l, w, level, append([]byte(nil), line...))
+		return
+	}
+	if r, ok := w.(BufferRetainer); ok && r.RetainsBuffer() {
+		line = append([]byte(nil), line...)
+	}
+	l.writeOutput(w, level, line)
+}
+
 // getFieldMap gets a map from the pool
 func getFieldMap() map[string]any {
 	m := fieldMapPool.Get().(map[string]any)
@@ -0,0 +1,181 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a log entry recorded by a MemorySink or passed to an Encoder.
+// Fields hold the values as they were written, after masking; JSON numbers
+// decode as float64. Time, Component and Version are zero when the line
+// does not carry them.
+type Entry struct {
+	Level     LogLevel
+	Message   string
+	Fields    map[string]any
+	Time      time.Time
+	Component string
+	Version   string
+}
+
+// MemorySink records entries in memory so tests can assert on them:
+//
+//	sink := emit.NewMemorySink()
+//	logger, _ := emit.New(emit.WithOutput(sink))
+//	logger.Info("login", "password", "hunter2")
+//	if entry, _ := sink.LastEntry(); entry.Fields["password"] != "***MASKED***" { ... }
+//
+// It decodes the default JSON format; lines that are not JSON are recorded
+// with the whole line as the message.
+type MemorySink struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemorySink returns an empty MemorySink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Write records a line, taking the level from the encoded entry
+func (s *MemorySink) Write(p []byte) (int, error) {
+	entries := decodeEntries(p)
+	for i := range entries {
+		if level, err := ParseLevel(entries[i].levelName); err == nil {
+			entries[i].Level = level
+		}
+	}
+	s.append(entries)
+	return len(p), nil
+}
+
+// WriteLevel records a line at the level it was logged with
+func (s *MemorySink) WriteLevel(level LogLevel, p []byte) (int, error) {
+	entries := decodeEntries(p)
+	for i := range entries {
+		entries[i].Level = level
+	}
+	s.append(entries)
+	return len(p), nil
+}
+
+// Entries returns a copy of the recorded entries in the order they were written
+func (s *MemorySink) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// LastEntry returns the most recent entry and false if none was recorded
+func (s *MemorySink) LastEntry() (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return Entry{}, false
+	}
+	return s.entries[len(s.entries)-1], true
+}
+
+// Contains reports whether an entry at level has a message containing substr
+func (s *MemorySink) Contains(level LogLevel, substr string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.entries {
+		if entry.Level == level && strings.Contains(entry.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards the recorded entries
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+func (s *MemorySink) append(decoded []decodedEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range decoded {
+		s.entries = append(s.entries, entry.Entry)
+	}
+}
+
+// decodedEntry carries the level name until it is resolved
+type decodedEntry struct {
+	Entry
+	levelName string
+}
+
+// entryMetadataKeys are the top-level keys that are not entry fields
+var entryMetadataKeys = map[string]bool{
+	defaultTimeKey: true, "level": true, "message": true,
+	"component": true, "version": true, "file": true, "line": true, "function": true,
+}
+
+// decodeEntries parses a write holding one or more JSON entries, as written
+// by Logger.Batch, on one line each or indented by WithPrettyJSON. Anything
+// else is recorded as a single entry.
+func decodeEntries(p []byte) []decodedEntry {
+	var decoded []decodedEntry
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return []decodedEntry{decodeEntry(p)}
+		}
+		decoded = append(decoded, decodeEntry(raw))
+	}
+	if len(decoded) <= 1 {
+		return []decodedEntry{decodeEntry(p)}
+	}
+	return decoded
+}
+
+// decodeEntry parses an encoded JSON line. Fields are read from the "fields"
+// object, and from the top level for lines written by earlier versions.
+func decodeEntry(p []byte) decodedEntry {
+	line := bytes.TrimSpace(p)
+
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return decodedEntry{Entry: Entry{Message: string(line)}}
+	}
+
+	return entryFromMap(raw)
+}
+
+// entryFromMap builds an entry from a decoded JSON object
+func entryFromMap(raw map[string]any) decodedEntry {
+	decoded := decodedEntry{}
+	decoded.Message, _ = raw["message"].(string)
+	decoded.levelName, _ = raw["level"].(string)
+	decoded.Component, _ = raw["component"].(string)
+	decoded.Version, _ = raw["version"].(string)
+	if ts, ok := raw[defaultTimeKey].(string); ok {
+		decoded.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+
+	if fields, ok := raw["fields"].(map[string]any); ok {
+		decoded.Fields = fields
+		return decoded
+	}
+	for key, value := range raw {
+		if entryMetadataKeys[key] {
+			continue
+		}
+		if decoded.Fields == nil {
+			decoded.Fields = make(map[string]any, len(raw))
+		}
+		decoded.Fields[key] = value
+	}
+	return decoded
+}
@@ -0,0 +1,103 @@
+package emit
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// loggerNameKey is the field carrying a named logger's dotted name
+const loggerNameKey = "logger"
+
+// prefixLevels maps logger name prefixes to levels. The map is replaced, never
+// mutated, so readers load it without locking.
+var (
+	prefixLevels   atomic.Pointer[map[string]LogLevel]
+	prefixLevelsMu sync.Mutex
+)
+
+// Named returns a child logger whose entries carry a "logger" field. Names
+// nest with dots: root.Named("http").Named("auth") logs as "http.auth".
+// The child inherits its parent's configuration; its level can be overridden
+// by namespace with SetLevelForPrefix.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	if l.tee != nil {
+		return l.teeMap(func(child *Logger) *Logger { return child.Named(name) })
+	}
+	child := l.derive()
+	if l.name != "" {
+		child.name = l.name + "." + name
+	} else {
+		child.name = name
+	}
+
+	baseFields := make(map[string]any, len(l.baseFields)+1)
+	maps.Copy(baseFields, l.baseFields)
+	baseFields[loggerNameKey] = child.name
+	child.baseFields = baseFields
+
+	return child
+}
+
+// Name returns the logger's dotted name, empty for unnamed loggers
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Named returns a named child of the default logger
+func Named(name string) *Logger {
+	return Default().Named(name)
+}
+
+// SetLevelForPrefix sets the level for named loggers whose name is prefix or
+// starts with prefix followed by a dot ("http" covers "http.auth" but not
+// "https"). The longest matching prefix wins and the change applies to
+// existing loggers on their next entry.
+func SetLevelForPrefix(prefix string, level LogLevel) {
+	updatePrefixLevels(func(levels map[string]LogLevel) {
+		levels[prefix] = level
+	})
+}
+
+// ClearLevelForPrefix removes a level set with SetLevelForPrefix
+func ClearLevelForPrefix(prefix string) {
+	updatePrefixLevels(func(levels map[string]LogLevel) {
+		delete(levels, prefix)
+	})
+}
+
+// updatePrefixLevels publishes a modified copy of the prefix registry
+func updatePrefixLevels(update func(map[string]LogLevel)) {
+	prefixLevelsMu.Lock()
+	defer prefixLevelsMu.Unlock()
+
+	levels := make(map[string]LogLevel)
+	if current := prefixLevels.Load(); current != nil {
+		maps.Copy(levels, *current)
+	}
+	update(levels)
+	prefixLevels.Store(&levels)
+}
+
+// levelForName returns the registry level for the longest prefix of name
+func levelForName(name string) (LogLevel, bool) {
+	current := prefixLevels.Load()
+	if current == nil || len(*current) == 0 {
+		return 0, false
+	}
+	levels := *current
+	for {
+		if level, ok := levels[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
@@ -0,0 +1,229 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Option configures a Logger created with New or updated with Configure
+type Option func(*Logger) error
+
+// Default replacements for masked field values
+const (
+	defaultMaskString    = "***MASKED***"
+	defaultPIIMaskString = "***PII***"
+)
+
+// newLogger creates a logger with the package defaults
+func newLogger() *Logger {
+	return &Logger{
+		level:           INFO,
+		writer:          os.Stdout,
+		showCaller:      false,
+		format:          JSON_FORMAT,    // JSON is default
+		sensitiveMode:   MASK_SENSITIVE, // Mask sensitive data by default
+		piiMode:         MASK_PII,       // Mask PII data by default
+		sensitiveFields: defaultSensitiveFields,
+		piiFields:       defaultPIIFields,
+		maskString:      defaultMaskString,
+		piiMaskString:   defaultPIIMaskString,
+		maskFuncs:       newMaskFuncRegistry(),
+		valuePatterns:   &valuePatternSet{},
+		hashSalt:        &hashSaltValue{},
+		fieldRules:      newLoggerFieldRules(),
+		state:           &loggerState{},
+		stats:           &loggerStats{},
+	}
+}
+
+// New creates a logger with the package defaults (JSON to stdout, masking
+// enabled) and applies the given options in order
+func New(opts ...Option) (*Logger, error) {
+	l := newLogger()
+	if err := l.apply(opts...); err != nil {
+		return nil, err
+	}
+	l.workers().start(workerSet{})
+	return l, nil
+}
+
+// apply runs options against the logger, stopping at the first error
+func (l *Logger) apply(opts ...Option) error {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(l); err != nil {
+			return err
+		}
+	}
+	// Default fields are masked after every option that affects masking
+	l.maskDefaultFields()
+	if l.strictMasking {
+		return l.checkStrictMasking(l.unmaskedDefaults)
+	}
+	return nil
+}
+
+// Configure applies options to the default logger
+func Configure(opts ...Option) error {
+	logger := Default()
+	if logger == nil {
+		return nil
+	}
+	prev := logger.workers()
+	err := logger.apply(opts...)
+	next := logger.workers()
+	next.start(prev)
+	prev.stop(next)
+	return err
+}
+
+// workerSet holds the background workers a configuration runs
+type workerSet struct {
+	async *asyncWriter
+	dedup *deduplicator
+}
+
+// workers returns the logger's background workers
+func (l *Logger) workers() workerSet {
+	return workerSet{async: l.async, dedup: l.dedup}
+}
+
+// start starts the workers options created in place of those in prev.
+// Options only build workers, so a failed New or Reconfigure leaves nothing
+// running.
+func (w workerSet) start(prev workerSet) {
+	if w.async != nil && w.async != prev.async {
+		go w.async.run()
+	}
+	if w.dedup != nil && w.dedup != prev.dedup {
+		go w.dedup.run()
+	}
+}
+
+// stop stops the workers that next replaced
+func (w workerSet) stop(next workerSet) {
+	if w.async != nil && w.async != next.async {
+		w.async.close()
+	}
+	if w.dedup != nil && w.dedup != next.dedup {
+		w.dedup.close()
+	}
+}
+
+// WithOutput sets the writer log entries are written to
+func WithOutput(writer io.Writer) Option {
+	return func(l *Logger) error {
+		if writer == nil {
+			return errors.New("emit: output writer must not be nil")
+		}
+		l.writer = writer
+		return nil
+	}
+}
+
+// WithLevel sets the minimum level that is logged
+func WithLevel(level LogLevel) Option {
+	return func(l *Logger) error {
+		l.SetLevel(level)
+		return nil
+	}
+}
+
+// WithComponent sets the component name added to every entry
+func WithComponent(component string) Option {
+	return func(l *Logger) error {
+		l.component = component
+		return nil
+	}
+}
+
+// WithVersion sets the version added to every entry
+func WithVersion(version string) Option {
+	return func(l *Logger) error {
+		l.version = version
+		return nil
+	}
+}
+
+// WithFormat sets the output format (FormatJSON, FormatPlain, FormatLogfmt,
+// FormatConsole, FormatECS or FormatGCP)
+func WithFormat(format OutputFormat) Option {
+	return func(l *Logger) error {
+		switch format {
+		case JSON_FORMAT, PLAIN_FORMAT, LOGFMT_FORMAT, CONSOLE_FORMAT, ECS_FORMAT, GCP_FORMAT:
+			l.format = format
+			return nil
+		default:
+			return fmt.Errorf("emit: unknown output format %d", format)
+		}
+	}
+}
+
+// WithMaskString sets the replacement for sensitive field values, e.g.
+// "[REDACTED]". An empty string is allowed and keeps the key with an empty value.
+func WithMaskString(mask string) Option {
+	return func(l *Logger) error {
+		l.maskString = mask
+		return nil
+	}
+}
+
+// WithPIIMaskString sets the replacement for PII field values, e.g. "[PII]".
+// An empty string is allowed and keeps the key with an empty value.
+func WithPIIMaskString(mask string) Option {
+	return func(l *Logger) error {
+		l.piiMaskString = mask
+		return nil
+	}
+}
+
+// WithSensitiveMode sets how sensitive field values are written:
+// MASK_SENSITIVE (the default), HASH_SENSITIVE, DROP_SENSITIVE or
+// SHOW_SENSITIVE. Unknown modes are rejected. HASH_SENSITIVE uses a random
+// per-process salt unless WithHashSalt sets one.
+func WithSensitiveMode(mode SensitiveDataMode) Option {
+	return func(l *Logger) error {
+		switch mode {
+		case MASK_SENSITIVE, SHOW_SENSITIVE, HASH_SENSITIVE, DROP_SENSITIVE:
+			l.sensitiveMode = mode
+			return nil
+		}
+		return fmt.Errorf("emit: unknown sensitive data mode %d", mode)
+	}
+}
+
+// WithPIIMode sets how PII field values are written: MASK_PII (the
+// default), DROP_PII or SHOW_PII. Unknown modes are rejected.
+func WithPIIMode(mode PIIDataMode) Option {
+	return func(l *Logger) error {
+		switch mode {
+		case MASK_PII, SHOW_PII, DROP_PII:
+			l.piiMode = mode
+			return nil
+		}
+		return fmt.Errorf("emit: unknown PII data mode %d", mode)
+	}
+}
+
+// WithSensitiveDrop omits sensitive fields from entries instead of masking
+// them, for compliance regimes where the key must not appear at all. Nested
+// keys are dropped too.
+func WithSensitiveDrop() Option {
+	return func(l *Logger) error {
+		l.sensitiveMode = DROP_SENSITIVE
+		return nil
+	}
+}
+
+// WithPIIDrop omits PII fields from entries instead of masking them. Nested
+// keys are dropped too.
+func WithPIIDrop() Option {
+	return func(l *Logger) error {
+		l.piiMode = DROP_PII
+		return nil
+	}
+}
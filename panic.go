@@ -0,0 +1,110 @@
+package emit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+// loggedPanic holds the value of the last panic raised by Panic, which
+// LogPanic recognizes so the entry is not written twice
+var loggedPanic atomic.Pointer[any]
+
+// WithPanicValue sets the value Panic raises, by default the message. Return
+// an error to let recover sites inspect it with errors.Is or errors.As:
+//
+//	emit.WithPanicValue(func(msg string) any { return fmt.Errorf("%w: %s", ErrInvariant, msg) })
+func WithPanicValue(value func(message string) any) Option {
+	return func(l *Logger) error {
+		if value == nil {
+			return errors.New("emit: panic value function must not be nil")
+		}
+		l.panicValue = value
+		return nil
+	}
+}
+
+// Panic logs a message at PANIC level, flushes the logger as Sync does, then
+// panics with the message or the value set with WithPanicValue. The panic
+// happens even when PANIC is filtered out. Unlike Fatal, deferred functions
+// run and the panic can be recovered; LogPanic does not log it again.
+func (l *Logger) Panic(message string, fields ...any) {
+	l.logContext(context.Background(), PANIC, message, fields...)
+	l.panic(message)
+}
+
+// PanicContext logs a panic message with fields from ctx, then panics as Panic does
+func (l *Logger) PanicContext(ctx context.Context, message string, fields ...any) {
+	l.logContext(ctx, PANIC, message, fields...)
+	l.panic(message)
+}
+
+// Panicf logs a formatted panic message, then panics as Panic does with the
+// formatted message
+func (l *Logger) Panicf(format string, args ...any) {
+	message, fields := splitFormatArgs(format, args)
+	l.logContext(context.Background(), PANIC, message, fields...)
+	l.panic(message)
+}
+
+// Panic logs a panic message on the default logger, then panics
+func Panic(message string, fields ...any) {
+	logger := Default()
+	if logger == nil {
+		panic(message)
+	}
+	logger.logContext(context.Background(), PANIC, message, fields...)
+	logger.panic(message)
+}
+
+// PanicContext logs a panic message on the default logger with fields from ctx, then panics
+func PanicContext(ctx context.Context, message string, fields ...any) {
+	logger := Default()
+	if logger == nil {
+		panic(message)
+	}
+	logger.logContext(ctx, PANIC, message, fields...)
+	logger.panic(message)
+}
+
+// Panicf logs a formatted panic message on the default logger, then panics
+func Panicf(format string, args ...any) {
+	message, fields := splitFormatArgs(format, args)
+	logger := Default()
+	if logger == nil {
+		panic(message)
+	}
+	logger.logContext(context.Background(), PANIC, message, fields...)
+	logger.panic(message)
+}
+
+// panic flushes and panics. A tee uses the panic value of its first logger.
+func (l *Logger) panic(message string) {
+	config := l
+	if len(l.tee) > 0 {
+		config = l.tee[0]
+	}
+	var value any = message
+	if config = config.resolve(); config.panicValue != nil {
+		value = config.panicValue(message)
+	}
+
+	_ = l.Sync()
+	if value != nil && reflect.ValueOf(value).Comparable() {
+		loggedPanic.Store(&value)
+	}
+	panic(value)
+}
+
+// takeLoggedPanic reports whether recovered was raised by Panic, clearing
+// the record so a later panic with an equal value is logged. Values that
+// cannot be compared are never recorded, so they count as not yet logged.
+func takeLoggedPanic(recovered any) bool {
+	last := loggedPanic.Load()
+	if last == nil || recovered == nil || reflect.TypeOf(recovered) != reflect.TypeOf(*last) ||
+		!reflect.ValueOf(recovered).Comparable() || *last != recovered {
+		return false
+	}
+	return loggedPanic.CompareAndSwap(last, nil)
+}
@@ -0,0 +1,76 @@
+package emit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ParseEntry parses one line written in the default JSON format back into
+// an Entry, as MemorySink records it. Fields come from the "fields" object;
+// top-level fields are still read so lines written by earlier versions, whose
+// structured field methods wrote them there, decode as well. Numbers decode
+// as float64. The timestamp, component and version fill Time, Component and
+// Version, caller keys are dropped and other unknown keys are kept as fields.
+// Registered level names are recognized, names set with WithLevelNames are
+// not.
+func ParseEntry(line []byte) (Entry, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, fmt.Errorf("emit: malformed entry: %w", err)
+	}
+	levelName, ok := raw["level"].(string)
+	if !ok {
+		return Entry{}, errors.New("emit: malformed entry: no level")
+	}
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return Entry{}, err
+	}
+	decoded := entryFromMap(raw)
+	decoded.Level = level
+	return decoded.Entry, nil
+}
+
+// ParseReader parses the JSON lines read from r, such as a log file, yielding
+// each entry in order. A line that does not parse is yielded with its error,
+// which includes the line number, and parsing continues with the next; an
+// error reading r is yielded last. Blank lines are skipped:
+//
+//	for entry, err := range emit.ParseReader(file) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Output indented with WithPrettyJSON spans several lines and is not
+// supported.
+func ParseReader(r io.Reader) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		reader := bufio.NewReader(r)
+		for lineNumber := 1; ; lineNumber++ {
+			line, readErr := reader.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				entry, err := ParseEntry(line)
+				if err != nil {
+					err = fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+				if !yield(entry, err) {
+					return
+				}
+			}
+			if readErr == io.EOF {
+				return
+			}
+			if readErr != nil {
+				yield(Entry{}, readErr)
+				return
+			}
+		}
+	}
+}
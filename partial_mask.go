@@ -0,0 +1,65 @@
+package emit
+
+// MaskEnd selects which end of a value is preserved by partial masking
+type MaskEnd int
+
+const (
+	KeepStart MaskEnd = iota // Keep leading characters: "sk_l..."
+	KeepEnd                  // Keep trailing characters: "...3456"
+	KeepBoth                 // Keep leading and trailing characters: "sk_l...3456"
+)
+
+// partialMaskEllipsis separates the preserved characters from the hidden part
+const partialMaskEllipsis = "..."
+
+// PartialMasking describes how many characters of a masked value remain visible
+type PartialMasking struct {
+	Keep int     // Number of characters kept at each selected end (0 disables partial masking)
+	From MaskEnd // Which end(s) of the value to keep
+}
+
+// PartialMask creates a partial masking configuration
+func PartialMask(keep int, from MaskEnd) PartialMasking {
+	if keep < 0 {
+		keep = 0
+	}
+	return PartialMasking{Keep: keep, From: from}
+}
+
+// enabled reports whether partial masking is configured
+func (p PartialMasking) enabled() bool {
+	return p.Keep > 0
+}
+
+// apply partially masks string values; non-string values and values too short
+// to hide anything meaningful are replaced with the full mask string
+func (p PartialMasking) apply(value any, mask string) any {
+	if !p.enabled() {
+		return mask
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return mask
+	}
+
+	runes := []rune(s)
+	visible := p.Keep
+	if p.From == KeepBoth {
+		visible *= 2
+	}
+
+	// Never reveal the whole value
+	if len(runes) <= visible {
+		return mask
+	}
+
+	switch p.From {
+	case KeepEnd:
+		return partialMaskEllipsis + string(runes[len(runes)-p.Keep:])
+	case KeepBoth:
+		return string(runes[:p.Keep]) + partialMaskEllipsis + string(runes[len(runes)-p.Keep:])
+	default:
+		return string(runes[:p.Keep]) + partialMaskEllipsis
+	}
+}
@@ -0,0 +1,101 @@
+package emit
+
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// prettyIndent is the indentation used by WithPrettyJSON
+const prettyIndent = "  "
+
+// WithPrettyJSON writes each JSON, ECS or GCP entry indented over several
+// lines, with keys sorted as by WithSortedKeys, for reading logs locally:
+//
+//	emit.WithPrettyJSON(os.Getenv("ENV") == "dev")
+//
+// Compact newline-delimited JSON, the default, is what log shippers expect,
+// so keep pretty output out of production. Only the layout changes: masking,
+// field placement and every other option behave the same in both modes.
+func WithPrettyJSON(enabled bool) Option {
+	return func(l *Logger) error {
+		l.prettyJSON = enabled
+		return nil
+	}
+}
+
+// WithSortedKeys writes field keys in alphabetical order, recursively in
+// nested maps, so the same entry always encodes to the same bytes and log
+// diffs and snapshot tests stay stable. The timestamp, level, message and
+// the other reserved keys still come first; ECS and GCP entries, which are
+// encoded as maps, are sorted throughout. Sorting costs a key slice and a
+// sort per map in the streaming and structured encoders, which otherwise
+// follow map and call order; the default JSON encoder sorts field maps
+// anyway, and logfmt and console output is always sorted.
+func WithSortedKeys() Option {
+	return func(l *Logger) error {
+		l.sortedKeys = true
+		return nil
+	}
+}
+
+// encodesJSON reports whether the format writes JSON lines
+func (l *Logger) encodesJSON() bool {
+	return l.encoder == nil && (l.format == JSON_FORMAT || l.format == ECS_FORMAT || l.format == GCP_FORMAT)
+}
+
+// sortsKeys reports whether field keys are written in sorted order
+func (l *Logger) sortsKeys() bool {
+	return l.sortedKeys || l.prettyJSON
+}
+
+// prettyLine indents an encoded JSON line into buf, returning the line
+// unchanged if it does not parse
+func prettyLine(buf *bytes.Buffer, line []byte) []byte {
+	if err := json.Indent(buf, bytes.TrimSuffix(line, []byte("\n")), "", prettyIndent); err != nil {
+		return line
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// orderedFields iterates fields in key order when keys are sorted and in map
+// order otherwise
+func (l *Logger) orderedFields(fields map[string]any) iter.Seq2[string, any] {
+	if !l.sortsKeys() {
+		return maps.All(fields)
+	}
+	return func(yield func(string, any) bool) {
+		for _, key := range slices.Sorted(maps.Keys(fields)) {
+			if !yield(key, fields[key]) {
+				return
+			}
+		}
+	}
+}
+
+// sortedZFields returns fields ordered by key. It is only called with the
+// field types the hot path encodes inline.
+func sortedZFields(fields []ZField) []ZField {
+	return slices.SortedStableFunc(slices.Values(fields), func(a, b ZField) int {
+		return strings.Compare(zfieldKey(a), zfieldKey(b))
+	})
+}
+
+// zfieldKey returns the key of an inline-encoded field
+func zfieldKey(field ZField) string {
+	switch f := field.(type) {
+	case StringZField:
+		return f.Key
+	case IntZField:
+		return f.Key
+	case Float64ZField:
+		return f.Key
+	case BoolZField:
+		return f.Key
+	}
+	return ""
+}
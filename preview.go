@@ -0,0 +1,238 @@
+package emit
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+)
+
+// MaskDecision explains how Preview treated one field
+type MaskDecision struct {
+	Field    string       // Path of the field, such as "user.email" or "items[0].token"
+	Masked   bool         // Whether the value was masked, hashed or dropped
+	Dropped  bool         // Whether the field was left out of the entry
+	Category MaskCategory // Why the field was masked, when Masked is true
+	Rule     string       // The field or value pattern that matched, or what decided the field
+}
+
+// Preview masks fields with the logger's rules without logging anything and
+// explains the decision taken for each field, to check a masking
+// configuration against a sample payload before deploying it:
+//
+//	masked, decisions := emit.Preview(logger, sample)
+//	for _, d := range decisions {
+//		fmt.Printf("%-20s masked=%-5v %s %q\n", d.Field, d.Masked, d.Category, d.Rule)
+//	}
+//
+// Nested maps and slices are explained field by field, with decisions sorted
+// by path. Rule names the matching field pattern or value pattern, or says
+// why a field was left visible, for example "exempt" or "no rule matched".
+// Mask observers and Stats are not notified. A tee previews its first
+// logger and a nil logger the default one.
+func Preview(logger *Logger, fields map[string]any) (map[string]any, []MaskDecision) {
+	if logger == nil {
+		logger = Default()
+	}
+	if logger == nil {
+		return fields, nil
+	}
+	if len(logger.tee) > 0 {
+		logger = logger.tee[0]
+	}
+	preview := *logger.resolve()
+	preview.maskObserver = nil
+	preview.stats = nil
+	preview.scanBudget = nil
+
+	var decisions []MaskDecision
+	for key, value := range preview.applyMaskPaths(fields) {
+		decisions = preview.explainField(decisions, key, key, value, false, 0)
+	}
+	slices.SortFunc(decisions, func(a, b MaskDecision) int {
+		return cmp.Compare(a.Field, b.Field)
+	})
+	return preview.maskFields(fields), decisions
+}
+
+// explainField appends the decision for a field or slice element and, when
+// it is a container left unmasked, for its members
+func (l *Logger) explainField(decisions []MaskDecision, path, key string, value any, element bool, depth int) []MaskDecision {
+	var decision MaskDecision
+	if element {
+		decision = explainElement(value)
+	} else {
+		decision = l.explainMask(key, value)
+	}
+	decision.Field = path
+	if decision.Masked || depth >= l.nestingLimit() {
+		return append(decisions, decision)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return l.explainMap(decisions, path, v, depth)
+	case Fields:
+		return l.explainMap(decisions, path, v, depth)
+	case []map[string]any:
+		for i, nested := range v {
+			decisions = l.explainField(decisions, path+"["+strconv.Itoa(i)+"]", "", nested, true, depth+1)
+		}
+		return decisions
+	case []any:
+		for i, nested := range v {
+			decisions = l.explainField(decisions, path+"["+strconv.Itoa(i)+"]", "", nested, true, depth+1)
+		}
+		return decisions
+	}
+	if _, text := textValue(value); !text {
+		if fields, _, ok := l.structAsMap(value); ok {
+			return l.explainMap(decisions, path, fields, depth)
+		}
+	}
+	return append(decisions, decision)
+}
+
+// explainMap appends the decisions for the members of a nested map
+func (l *Logger) explainMap(decisions []MaskDecision, path string, fields map[string]any, depth int) []MaskDecision {
+	for key, value := range fields {
+		decisions = l.explainField(decisions, path+"."+key, key, value, false, depth+1)
+	}
+	return decisions
+}
+
+// explainElement reports the decision for a slice element, which only
+// Secret and PII mark for masking
+func explainElement(value any) MaskDecision {
+	switch value.(type) {
+	case SecretValue:
+		return MaskDecision{Masked: true, Category: MaskCategorySensitive, Rule: "Secret value"}
+	case PIIValue:
+		return MaskDecision{Masked: true, Category: MaskCategoryPII, Rule: "PII value"}
+	}
+	return MaskDecision{Rule: "no rule matched"}
+}
+
+// explainMask reports the decision maskFieldValue takes for a field, checking
+// the rules in the same order
+func (l *Logger) explainMask(key string, value any) MaskDecision {
+	switch value.(type) {
+	case StackTrace:
+		return MaskDecision{Rule: "stack trace"}
+	case requestID:
+		return MaskDecision{Rule: "request ID"}
+	case SecretValue:
+		return l.sensitiveDecision("Secret value")
+	case PIIValue:
+		return MaskDecision{Masked: true, Dropped: l.piiMode == DROP_PII, Category: MaskCategoryPII, Rule: "PII value"}
+	}
+
+	if l.maskFuncFor(key) != nil {
+		return MaskDecision{Masked: true, Category: MaskCategoryCustom, Rule: "mask function"}
+	}
+	if _, ok := value.(taggedMask); ok {
+		if l.sensitiveMode == SHOW_SENSITIVE {
+			return MaskDecision{Rule: "sensitive data shown"}
+		}
+		return l.sensitiveDecision("mask tag or path")
+	}
+	if rule := l.categoryFor(key); rule != nil {
+		return MaskDecision{
+			Masked:   true,
+			Dropped:  rule.mode == DROP_SENSITIVE,
+			Category: rule.category,
+			Rule:     piiPatternMatch(rule.patterns, key),
+		}
+	}
+	if l.isPIIFieldFast(key) {
+		return MaskDecision{Masked: true, Dropped: l.piiMode == DROP_PII, Category: MaskCategoryPII, Rule: l.fieldPattern(key, true)}
+	}
+	if l.isSensitiveFieldFast(key) {
+		return l.sensitiveDecision(l.fieldPattern(key, false))
+	}
+	if s, ok := value.(string); ok && l.isJSONField(key) {
+		return l.explainJSON(key, s)
+	}
+
+	exempt := l.isMaskExempt(key)
+	if pattern := l.valuePatternMatch(value); pattern != "" && !exempt {
+		return l.sensitiveDecision(pattern)
+	}
+	if l.matchesEntropy(value) && !exempt {
+		return l.sensitiveDecision("high entropy value")
+	}
+
+	switch {
+	case exempt:
+		return MaskDecision{Rule: "exempt"}
+	case l.piiMode == SHOW_PII && l.fieldPattern(key, true) != "":
+		return MaskDecision{Rule: "PII shown"}
+	case l.sensitiveMode == SHOW_SENSITIVE && l.fieldPattern(key, false) != "":
+		return MaskDecision{Rule: "sensitive data shown"}
+	}
+	return MaskDecision{Rule: "no rule matched"}
+}
+
+// sensitiveDecision returns the decision for a field masked as sensitive
+func (l *Logger) sensitiveDecision(rule string) MaskDecision {
+	return MaskDecision{Masked: true, Dropped: l.sensitiveMode == DROP_SENSITIVE, Category: MaskCategorySensitive, Rule: rule}
+}
+
+// explainJSON reports whether anything inside a JSON document field is masked
+func (l *Logger) explainJSON(key, value string) MaskDecision {
+	decision := MaskDecision{Rule: "JSON document"}
+	counting := *l
+	counting.maskObserver = func(_ string, category MaskCategory) {
+		if !decision.Masked {
+			decision.Masked = true
+			decision.Category = category
+		}
+	}
+	_, decision.Dropped = counting.redactJSON(key, value).(droppedField)
+	return decision
+}
+
+// fieldPattern returns the PII or sensitive field pattern key matches in the
+// lists the logger uses, or ""
+func (l *Logger) fieldPattern(key string, pii bool) string {
+	if l.fieldRules != nil {
+		l.fieldRules.mu.RLock()
+		patterns := l.fieldRules.sensitiveFields
+		if pii {
+			patterns = l.fieldRules.piiFields
+		}
+		l.fieldRules.mu.RUnlock()
+		if patterns != nil {
+			return matchFieldPattern(patterns, key, pii)
+		}
+	}
+
+	initializeFieldMaps()
+	fieldMapsMu.RLock()
+	defer fieldMapsMu.RUnlock()
+	if pii {
+		return matchFieldPattern(piiFieldsMap, key, true)
+	}
+	return matchFieldPattern(sensitiveFieldsMap, key, false)
+}
+
+// matchFieldPattern matches key as a PII or a sensitive field name
+func matchFieldPattern(patterns map[string]bool, key string, pii bool) string {
+	if pii {
+		return piiPatternMatch(patterns, key)
+	}
+	return sensitivePatternMatch(patterns, foldFieldName(key))
+}
+
+// valuePatternMatch returns the value pattern a value matches, or ""
+func (l *Logger) valuePatternMatch(value any) string {
+	if !l.matchesValuePattern(value) {
+		return ""
+	}
+	s := value.(string)
+	for _, pattern := range l.valuePatterns.load() {
+		if pattern.MatchString(s) {
+			return pattern.String()
+		}
+	}
+	return ""
+}
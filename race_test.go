@@ -1,6 +1,9 @@
 package emit
 
 import (
+	"encoding/json"
+	"io"
+	"regexp"
 	"strconv"
 	"sync"
 	"testing"
@@ -74,3 +77,276 @@ func TestConcurrentMixedLogging(t *testing.T) {
 	wg.Wait()
 	t.Log("Mixed concurrent logging completed successfully")
 }
+
+// TestConcurrentSetLevel tests changing the level while other goroutines log
+func TestConcurrentSetLevel(t *testing.T) {
+	testLogger, err := New()
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	SetOutputToDiscard()
+	testLogger.writer = Default().writer
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(goroutineID int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				testLogger.Info("Level change test", "goroutine", goroutineID)
+				if j%10 == 0 {
+					testLogger.SetLevel(LogLevel(j % 4))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	testLogger.SetLevel(LevelWarn)
+	if testLogger.GetLevel() != LevelWarn {
+		t.Errorf("Expected level warn, got %v", testLogger.GetLevel())
+	}
+	if testLogger.Enabled(LevelInfo) {
+		t.Errorf("Expected info to be disabled at warn level")
+	}
+}
+
+// retainingWriter keeps every slice passed to Write, like a queueing sink would
+type retainingWriter struct {
+	mu    sync.Mutex
+	lines [][]byte
+}
+
+func (w *retainingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.lines = append(w.lines, p)
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+func (w *retainingWriter) RetainsBuffer() bool { return true }
+
+// TestPooledBuffersNotShared tests that retaining writers never see reused pooled buffers
+func TestPooledBuffersNotShared(t *testing.T) {
+	writer := &retainingWriter{}
+	testLogger, err := New(WithOutput(writer))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(goroutineID int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				testLogger.Info("Pooled buffer test", "goroutine", goroutineID, "iteration", j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, len(writer.lines))
+	for _, line := range writer.lines {
+		if !json.Valid(line) {
+			t.Fatalf("Corrupted line: %s", line)
+		}
+		if seen[string(line)] {
+			t.Fatalf("Duplicate line indicates a reused buffer: %s", line)
+		}
+		seen[string(line)] = true
+	}
+	if len(seen) != 500 {
+		t.Errorf("Expected 500 distinct lines, got %d", len(seen))
+	}
+}
+
+// TestReconfigure checks that entries never mix two configurations
+func TestReconfigure(t *testing.T) {
+	sink := NewMemorySink()
+	logger, err := New(WithOutput(sink), WithMaskString("[v0]"), WithDefaultFields(map[string]any{"config": "v0"}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	child := logger.WithFields(map[string]any{"request_id": "r-1"})
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					child.Info("tick", "password", "hunter2")
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				logger.SetLevel(INFO)
+			}
+		}
+	}()
+	for i := 1; i <= 50; i++ {
+		version := "v" + strconv.Itoa(i)
+		if err := logger.Reconfigure(WithMaskString("["+version+"]"), WithDefaultFields(map[string]any{"config": version})); err != nil {
+			t.Fatalf("Unexpected error reconfiguring: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	child.Info("final", "password", "hunter2")
+	for _, entry := range sink.Entries() {
+		if entry.Fields["password"] != "["+entry.Fields["config"].(string)+"]" || entry.Fields["request_id"] != "r-1" {
+			t.Fatalf("Expected one configuration per entry, got %v", entry.Fields)
+		}
+	}
+	if last, _ := sink.LastEntry(); last.Fields["config"] != "v50" {
+		t.Errorf("Expected the child to use the latest configuration, got %v", last.Fields)
+	}
+
+	if err := logger.Reconfigure(WithMaskString("[broken]"), WithOutput(nil)); err == nil {
+		t.Error("Expected an error for an invalid option")
+	}
+	logger.Info("after failure", "password", "hunter2")
+	if last, _ := sink.LastEntry(); last.Fields["password"] != "[v50]" {
+		t.Errorf("Expected a failed Reconfigure to change nothing, got %v", last.Fields)
+	}
+
+	child.SetLevel(ERROR)
+	if logger.Enabled(INFO) {
+		t.Error("Expected reconfigured loggers to share one level")
+	}
+}
+
+// TestConcurrentMaskFuncRegistration tests that lookups racing a registration
+// never cache a stale result
+func TestConcurrentMaskFuncRegistration(t *testing.T) {
+	registry := newMaskFuncRegistry()
+	registry.register("unused", func(any) any { return "" })
+
+	for i := range 50 {
+		key := "field_" + strconv.Itoa(i)
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				registry.lookup(key)
+			}()
+		}
+		registry.register(key, func(any) any { return "masked" })
+		wg.Wait()
+
+		if registry.lookup(key) == nil {
+			t.Fatalf("Expected the function registered for %s to be found", key)
+		}
+	}
+}
+
+// TestConcurrentValuePatterns tests adding value patterns while entries are
+// being masked
+func TestConcurrentValuePatterns(t *testing.T) {
+	testLogger, err := New(WithOutput(io.Discard))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.SetValuePatternDetection(true)
+	child := testLogger.WithFields(map[string]any{"worker": true})
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				if i == 0 {
+					testLogger.AddValuePattern(regexp.MustCompile("tok_" + strconv.Itoa(j)))
+					continue
+				}
+				child.Info("tick", "note", "tok_"+strconv.Itoa(j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(child.valuePatterns.load()); got != 50 {
+		t.Errorf("Expected 50 patterns shared with the child, got %d", got)
+	}
+}
+
+func TestConcurrentHashSalt(t *testing.T) {
+	testLogger, err := New(WithOutput(io.Discard), WithSensitiveMode(HASH_SENSITIVE))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	child := testLogger.WithFields(map[string]any{"worker": true})
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				if i == 0 {
+					testLogger.SetHashSalt([]byte("salt-" + strconv.Itoa(j)))
+					continue
+				}
+				child.Info("tick", "password", "hunter2")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := child.hashValue("hunter2"), testLogger.hashValue("hunter2"); got != want {
+		t.Errorf("Expected the salt to be shared with the child, got %v and %v", got, want)
+	}
+}
+
+func TestConcurrentResetFieldLists(t *testing.T) {
+	testLogger, err := New(WithOutput(io.Discard))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	original := Default()
+	SetDefault(testLogger)
+	t.Cleanup(func() {
+		SetDefault(original)
+		ResetFieldLists()
+	})
+	testLogger.AddSensitiveField("vault_ref")
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if i == 0 {
+					ResetFieldLists()
+					testLogger.AddSensitiveField("vault_ref")
+					continue
+				}
+				testLogger.Info("tick", "vault_ref", "v-1")
+			}
+		}()
+	}
+	wg.Wait()
+
+	ResetFieldLists()
+	if masked := testLogger.maskSensitiveFieldsFast(map[string]any{"vault_ref": "v-1"}); masked["vault_ref"] != "v-1" {
+		t.Errorf("Expected the reset to drop the logger's own fields, got %v", masked)
+	}
+}
@@ -0,0 +1,128 @@
+package emit
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Reconfigure applies options to a copy of the logger's current configuration
+// and installs the result atomically, so references held by call sites pick
+// up the change without being recreated:
+//
+//	http.HandleFunc("/admin/logging", func(w http.ResponseWriter, r *http.Request) {
+//		if err := logger.Reconfigure(emit.WithFormat(emit.FormatConsole), emit.WithMaskString("[hidden]")); err != nil {
+//			http.Error(w, err.Error(), http.StatusBadRequest)
+//		}
+//	})
+//
+// Every option can be swapped: level, format, output, sinks and level
+// routing, masking modes, mask strings and field lists, hooks, sampling and
+// the rest. Each entry is encoded entirely with the configuration that was
+// current when the logging call started. If an option fails, nothing changes.
+//
+// The change applies to the logger and to every logger derived from it with
+// WithFields or Named, which keep their own fields and names. After the first
+// Reconfigure they also share one level, so SetLevel on any of them sets it
+// for all. Package setters such as SetComponent or SetMaskString change the
+// logger in place and have no effect once it has been reconfigured. Writers
+// that are replaced are not closed.
+func (l *Logger) Reconfigure(opts ...Option) error {
+	if l.tee != nil {
+		return l.teeEach(func(child *Logger) error { return child.Reconfigure(opts...) })
+	}
+	if l.state == nil {
+		return errors.New("emit: Reconfigure requires a logger created with New")
+	}
+	l.state.reconfigureMu.Lock()
+	defer l.state.reconfigureMu.Unlock()
+
+	var next Logger
+	if current := l.state.config.Load(); current != nil {
+		next = *current
+	} else {
+		// SetLevel takes levelMu too, so copying l does not race with it
+		l.state.levelMu.Lock()
+		next = *l
+		l.state.levelMu.Unlock()
+		next.baseFields = nil
+		next.name = ""
+		next.batch = nil
+		next.callerPC = 0
+		next.groups = nil
+		next.resolveCache = nil
+	}
+	next.level = l.GetLevel()
+	next.resolved = true
+	prev := next.workers()
+
+	if err := next.apply(opts...); err != nil {
+		return err
+	}
+	// Side effects run only once the new configuration is certain
+	next.workers().start(prev)
+	l.state.level.Store(int32(next.level))
+	l.state.config.Store(&next)
+	prev.stop(next.workers())
+	return nil
+}
+
+// derive returns a copy of l for a new logger handle, with its own resolve cache
+func (l *Logger) derive() *Logger {
+	child := *l
+	child.resolveCache = &resolveCache{}
+	return &child
+}
+
+// resolveCache keeps the copy of the configuration a derived logger last
+// resolved to, so it copies the configuration once per Reconfigure rather
+// than once per entry
+type resolveCache struct {
+	entry atomic.Pointer[resolvedConfig]
+}
+
+// resolvedConfig is a configuration copied for one logger
+type resolvedConfig struct {
+	owner  *Logger
+	config *Logger
+	logger *Logger
+}
+
+// resolve returns the logger to encode an entry with: l itself until
+// Reconfigure is first called, then the current configuration, which is
+// never modified. Loggers with their own fields, name, batch, groups or
+// caller get a copy of it carrying those, made once per configuration.
+func (l *Logger) resolve() *Logger {
+	if l.resolved || l.state == nil {
+		return l
+	}
+	current := l.state.config.Load()
+	if current == nil {
+		return l
+	}
+	if len(l.baseFields) == 0 && l.name == "" && l.batch == nil && l.callerPC == 0 && len(l.groups) == 0 && l.recordTime == nil {
+		return current
+	}
+
+	cache := l.resolveCache
+	if cache != nil {
+		if cached := cache.entry.Load(); cached != nil && cached.owner == l && cached.config == current {
+			return cached.logger
+		}
+	}
+	resolved := *current
+	resolved.baseFields = l.baseFields
+	resolved.name = l.name
+	resolved.batch = l.batch
+	resolved.callerPC = l.callerPC
+	resolved.groups = l.groups
+	resolved.recordTime = l.recordTime
+	if cache != nil {
+		cache.entry.Store(&resolvedConfig{owner: l, config: current, logger: &resolved})
+	}
+	return &resolved
+}
+
+// reconfigured reports whether Reconfigure has installed a configuration
+func (l *Logger) reconfigured() bool {
+	return l.state != nil && l.state.config.Load() != nil
+}
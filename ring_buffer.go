@@ -0,0 +1,138 @@
+package emit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// RingBuffer keeps the most recent encoded lines in memory, returned by
+// RingBufferSink
+type RingBuffer struct {
+	mu    sync.Mutex
+	lines [][]byte
+	next  int
+	full  bool
+}
+
+// RingBufferSink returns a writer that retains the last capacity lines, so
+// recent context can be dumped when a process crashes:
+//
+//	ring := emit.RingBufferSink(500)
+//	logger, _ := emit.New(emit.WithSinks(
+//		emit.Sink{Writer: os.Stdout, MinLevel: emit.INFO},
+//		emit.Sink{Writer: ring}, // debug entries too
+//	))
+//	defer emit.LogPanic(logger, ring)
+//
+// Each slot reuses its buffer, so once the ring is full, writing does not
+// allocate unless a line outgrows its slot. A capacity below 1 keeps one line.
+func RingBufferSink(capacity int) *RingBuffer {
+	return &RingBuffer{lines: make([][]byte, max(capacity, 1))}
+}
+
+// Write retains each line of p, evicting the oldest lines when full
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for rest := p; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line, rest = rest[:i+1], rest[i+1:]
+		} else {
+			rest = nil
+		}
+		r.lines[r.next] = append(r.lines[r.next][:0], line...)
+		r.next++
+		if r.next == len(r.lines) {
+			r.next = 0
+			r.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Len returns the number of retained lines
+func (r *RingBuffer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count()
+}
+
+// count returns the number of retained lines; r.mu must be held
+func (r *RingBuffer) count() int {
+	if r.full {
+		return len(r.lines)
+	}
+	return r.next
+}
+
+// Dump writes the retained lines to w, oldest first. Writes are blocked
+// while it runs.
+func (r *RingBuffer) Dump(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := 0
+	if r.full {
+		start = r.next
+	}
+	for i := range r.count() {
+		if _, err := w.Write(r.lines[(start+i)%len(r.lines)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset discards the retained lines, keeping their buffers
+func (r *RingBuffer) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.lines {
+		r.lines[i] = r.lines[i][:0]
+	}
+	r.next = 0
+	r.full = false
+}
+
+// panicDumpWriter receives ring buffer dumps written by LogPanic
+var panicDumpWriter io.Writer = os.Stderr
+
+// LogPanic logs a recovered panic at error level with its stack trace,
+// writes the lines retained by the given ring buffers to stderr, then panics
+// again with the same value so the program still fails. Call it deferred:
+//
+//	defer emit.LogPanic(logger, ring)
+//
+// It does nothing when the goroutine is not panicking. Panics raised by
+// Logger.Panic were logged already, so only the ring buffers are written.
+func LogPanic(logger *Logger, rings ...*RingBuffer) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	if logger != nil && !takeLoggedPanic(recovered) {
+		fields := []any{"panic", fmt.Sprint(recovered), stackTraceKey, captureStackTrace(logger.maxStackDepth())}
+		if err, ok := recovered.(error); ok {
+			fields = append(fields, Err(err))
+		}
+		logger.Error("panic recovered", fields...)
+		_ = logger.Sync()
+	}
+
+	for _, ring := range rings {
+		if ring == nil {
+			continue
+		}
+		fmt.Fprintf(panicDumpWriter, "--- last %d log lines ---\n", ring.Len())
+		_ = ring.Dump(panicDumpWriter)
+		fmt.Fprintln(panicDumpWriter, "--- end of log lines ---")
+	}
+
+	panic(recovered)
+}
@@ -0,0 +1,58 @@
+package emit
+
+import "os"
+
+// Field keys written by WithRuntimeInfo
+const (
+	hostKey = "host"
+	pidKey  = "pid"
+)
+
+// RuntimeInfoOption configures WithRuntimeInfo
+type RuntimeInfoOption func(*runtimeInfo)
+
+// runtimeInfo selects the process fields WithRuntimeInfo writes
+type runtimeInfo struct {
+	host bool
+	pid  bool
+}
+
+// WithRuntimeInfo writes the hostname as "host" and the process ID as "pid"
+// on every entry. Both are resolved once, when the option is applied, and
+// behave like default fields except that they are never masked, even though
+// host names can look like personal data. Default fields with the same keys
+// take precedence. The hostname is omitted when it cannot be resolved.
+func WithRuntimeInfo(opts ...RuntimeInfoOption) Option {
+	return func(l *Logger) error {
+		info := &runtimeInfo{host: true, pid: true}
+		for _, opt := range opts {
+			opt(info)
+		}
+
+		fields := make(map[string]any, 2)
+		if info.host {
+			if host, err := os.Hostname(); err == nil && host != "" {
+				fields[hostKey] = host
+			}
+		}
+		if info.pid {
+			fields[pidKey] = os.Getpid()
+		}
+		l.runtimeFields = fields
+		return nil
+	}
+}
+
+// RuntimeOmitHost leaves the "host" field out
+func RuntimeOmitHost() RuntimeInfoOption {
+	return func(info *runtimeInfo) {
+		info.host = false
+	}
+}
+
+// RuntimeOmitPID leaves the "pid" field out
+func RuntimeOmitPID() RuntimeInfoOption {
+	return func(info *runtimeInfo) {
+		info.pid = false
+	}
+}
@@ -0,0 +1,205 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// samplerSlots is the number of counters keys are hashed into. Keys that
+// collide share a counter, trading exactness for a fixed memory footprint.
+const samplerSlots = 4096
+
+// defaultSamplingInterval is how often per-key counters reset
+const defaultSamplingInterval = time.Second
+
+// SamplingOption customizes sampling configured with WithSampling
+type SamplingOption func(*sampler)
+
+// sampler limits repeated entries to the first N per interval, then every Mth
+type sampler struct {
+	first      uint64
+	thereafter uint64
+	interval   int64
+	keyField   string
+	counters   [samplerSlots]samplerCounter
+}
+
+// samplerCounter counts entries for one hash slot within the current interval
+type samplerCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// WithSampling protects against log floods: within each interval (one second
+// by default) the first entries for a key are logged, then only every
+// thereafter-th entry. A thereafter of 0 drops everything past first.
+// Entries are keyed by level and message unless SampleByField is used.
+func WithSampling(first, thereafter int, opts ...SamplingOption) Option {
+	return func(l *Logger) error {
+		if first < 0 || thereafter < 0 {
+			return errors.New("emit: sampling counts must not be negative")
+		}
+		s := &sampler{
+			first:      uint64(first),
+			thereafter: uint64(thereafter),
+			interval:   int64(defaultSamplingInterval),
+		}
+		for _, opt := range opts {
+			opt(s)
+		}
+		if s.interval <= 0 {
+			return errors.New("emit: sampling interval must be positive")
+		}
+		l.sampler = s
+		return nil
+	}
+}
+
+// SampleEvery sets how often sampling counters reset
+func SampleEvery(interval time.Duration) SamplingOption {
+	return func(s *sampler) {
+		s.interval = int64(interval)
+	}
+}
+
+// SampleByField keys sampling by the value of a field (plus the level)
+// instead of the message, e.g. to sample per "error_code". Entries without
+// the field fall back to the message.
+func SampleByField(key string) SamplingOption {
+	return func(s *sampler) {
+		s.keyField = key
+	}
+}
+
+// allow reports whether an entry should be written and records it
+func (s *sampler) allow(level LogLevel, message string, fields map[string]any) bool {
+	hash := fnvAddByte(fnvOffset, byte(level))
+	if value, ok := fields[s.keyField]; ok && s.keyField != "" {
+		hash = fnvAddValue(hash, value)
+	} else {
+		hash = fnvAddString(hash, message)
+	}
+
+	counter := &s.counters[hash%samplerSlots]
+	now := time.Now().UnixNano()
+	if resetAt := counter.resetAt.Load(); now >= resetAt {
+		if counter.resetAt.CompareAndSwap(resetAt, now+s.interval) {
+			counter.count.Store(0)
+		}
+	}
+
+	n := counter.count.Add(1)
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// FNV-1a parameters used for allocation-free key hashing
+const (
+	fnvOffset uint64 = 14695981039346656037
+	fnvPrime  uint64 = 1099511628211
+)
+
+func fnvAddByte(hash uint64, b byte) uint64 {
+	return (hash ^ uint64(b)) * fnvPrime
+}
+
+func fnvAddString(hash uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		hash = fnvAddByte(hash, s[i])
+	}
+	return hash
+}
+
+// fnvAddValue hashes common scalar field values without formatting them
+func fnvAddValue(hash uint64, value any) uint64 {
+	switch v := value.(type) {
+	case string:
+		return fnvAddString(hash, v)
+	case int:
+		return fnvAddUint(hash, uint64(v))
+	case int64:
+		return fnvAddUint(hash, uint64(v))
+	case bool:
+		if v {
+			return fnvAddByte(hash, 1)
+		}
+		return fnvAddByte(hash, 0)
+	default:
+		return fnvAddString(hash, fmt.Sprint(value))
+	}
+}
+
+func fnvAddUint(hash uint64, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		hash = fnvAddByte(hash, byte(v>>(8*i)))
+	}
+	return hash
+}
+
+// traceIDKey is the field trace sampling reads, set by WithOTelTrace
+const traceIDKey = "trace_id"
+
+// WithTraceSampling keeps a fixed fraction of traces, deciding from the
+// "trace_id" field so every service sampling at the same rate logs the same
+// traces in full or not at all:
+//
+//	emit.WithOTelTrace(extractor), emit.WithTraceSampling(0.1) // 10% of traces
+//
+// An entry is written when the 64-bit FNV-1a hash of its trace ID, mixed
+// with the MurmurHash3 fmix64 finalizer, is below rate × 2^64, which other
+// implementations can reproduce. Entries without a trace ID are always written. It runs before
+// WithSampling, which still limits the traces that are kept.
+func WithTraceSampling(rate float64) Option {
+	return func(l *Logger) error {
+		if !(rate >= 0 && rate <= 1) {
+			return fmt.Errorf("emit: trace sampling rate %v must be between 0 and 1", rate)
+		}
+		l.traceSampler = &traceSampler{all: rate == 1, threshold: uint64(rate * (1 << 64))}
+		return nil
+	}
+}
+
+// traceSampler keeps traces whose ID hashes below threshold
+type traceSampler struct {
+	all       bool
+	threshold uint64
+}
+
+// allow reports whether an entry with these fields belongs to a kept trace
+func (s *traceSampler) allow(fields map[string]any) bool {
+	if s.all {
+		return true
+	}
+	traceID, ok := fields[traceIDKey]
+	if !ok {
+		return true
+	}
+	var id string
+	switch v := traceID.(type) {
+	case string:
+		id = v
+	case fmt.Stringer:
+		id = v.String()
+	default:
+		id = fmt.Sprint(v)
+	}
+	if id == "" {
+		return true
+	}
+	return fmix64(fnvAddString(fnvOffset, id)) < s.threshold
+}
+
+// fmix64 spreads every input bit across the hash, as FNV-1a alone leaves
+// the high bits of similar IDs close together
+func fmix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
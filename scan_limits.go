@@ -0,0 +1,91 @@
+package emit
+
+import "errors"
+
+// scanLimit bounds the value scanning done for one entry
+type scanLimit struct {
+	maxFields int
+	maxBytes  int
+}
+
+// scanBudget tracks the scanning done for the entry being written. It
+// belongs to a per-entry copy of the logger and is not shared.
+type scanBudget struct {
+	limit     *scanLimit
+	fields    int
+	bytes     int
+	truncated bool
+}
+
+// WithScanLimit bounds the value-content detectors, value patterns and
+// WithEntropyMasking, to maxFields string values or maxBytes of them per
+// entry, so a huge field map cannot make writing one entry arbitrarily slow:
+//
+//	emit.WithScanLimit(64, 64<<10)
+//
+// A limit of zero means no limit on that count. Once either limit would be
+// exceeded, the rest of the entry's values are not scanned; field-name
+// detection, which is cheap, still applies to every field. Entries cut
+// short are followed by a debug entry giving the fields and bytes scanned,
+// written only when DEBUG is enabled. Values that are never scanned, such as
+// those of sensitive fields, do not count.
+func WithScanLimit(maxFields, maxBytes int) Option {
+	return func(l *Logger) error {
+		if maxFields < 0 || maxBytes < 0 || (maxFields == 0 && maxBytes == 0) {
+			return errors.New("emit: scan limits must not be negative and one must be set")
+		}
+		l.scanLimit = &scanLimit{maxFields: maxFields, maxBytes: maxBytes}
+		return nil
+	}
+}
+
+// withScanBudget returns a copy of the logger with a fresh budget for one entry
+func (l *Logger) withScanBudget() *Logger {
+	scoped := *l
+	scoped.scanBudget = &scanBudget{limit: l.scanLimit}
+	return &scoped
+}
+
+// allowValueScan reports whether value may be scanned, counting it against
+// the entry's budget
+func (l *Logger) allowValueScan(value any) bool {
+	b := l.scanBudget
+	if b == nil {
+		return true
+	}
+	s, ok := value.(string)
+	if !ok || s == "" || !l.scansValues() {
+		return true
+	}
+	if b.truncated {
+		return false
+	}
+	if (b.limit.maxFields > 0 && b.fields >= b.limit.maxFields) ||
+		(b.limit.maxBytes > 0 && b.bytes+len(s) > b.limit.maxBytes) {
+		b.truncated = true
+		return false
+	}
+	b.fields++
+	b.bytes += len(s)
+	return true
+}
+
+// scansValues reports whether any value-content detector is active
+func (l *Logger) scansValues() bool {
+	if l.sensitiveMode == SHOW_SENSITIVE {
+		return false
+	}
+	return l.detectsValues() || l.entropy != nil
+}
+
+// noteScanTruncated writes a debug entry when the entry's scanning was cut short
+func (l *Logger) noteScanTruncated() {
+	b := l.scanBudget
+	if !b.truncated || !l.Enabled(DEBUG) {
+		return
+	}
+	l.log(DEBUG, "emit: value scanning truncated", map[string]any{
+		"scanned_fields": b.fields,
+		"scanned_bytes":  b.bytes,
+	})
+}
@@ -0,0 +1,247 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrSchemaViolation is wrapped by the errors WithSchema reports
+var ErrSchemaViolation = errors.New("emit: schema violation")
+
+// FieldType is the expected type of a field declared with WithSchema
+type FieldType int
+
+const (
+	FieldAny      FieldType = iota // Any value
+	FieldString                    // Strings
+	FieldInt                       // Signed and unsigned integers
+	FieldFloat                     // Floats, or integers
+	FieldBool                      // Booleans
+	FieldTime                      // time.Time
+	FieldDuration                  // time.Duration
+	FieldObject                    // Maps and structs
+	FieldArray                     // Slices and arrays
+)
+
+// String returns the name of the field type
+func (t FieldType) String() string {
+	switch t {
+	case FieldAny:
+		return "any"
+	case FieldString:
+		return "string"
+	case FieldInt:
+		return "int"
+	case FieldFloat:
+		return "float"
+	case FieldBool:
+		return "bool"
+	case FieldTime:
+		return "time"
+	case FieldDuration:
+		return "duration"
+	case FieldObject:
+		return "object"
+	case FieldArray:
+		return "array"
+	}
+	return "FieldType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// SchemaOption customizes validation configured with WithSchema
+type SchemaOption func(*schema)
+
+// schema holds the declared fields and what to do with violations
+type schema struct {
+	fields      map[string]FieldType
+	coerce      bool
+	dropUnknown bool
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// WithSchema declares the fields entries may carry and their types, so
+// accidental new fields and wrong-typed values are caught before they reach
+// the pipeline:
+//
+//	emit.WithSchema(map[string]emit.FieldType{
+//		"order_id": emit.FieldString,
+//		"amount":   emit.FieldFloat,
+//		"attempt":  emit.FieldInt,
+//	}, emit.SchemaCoerce())
+//
+// Each undeclared field and each value of the wrong type is reported to the
+// error handler with an error wrapping ErrSchemaViolation, and the entry is
+// still written as logged unless SchemaCoerce or SchemaDropUnknown is given.
+// Fields are checked before masking; default fields and the fields the
+// logger adds itself, such as the caller and stack trace, are not checked.
+// Without WithSchema any field is accepted.
+func WithSchema(fields map[string]FieldType, opts ...SchemaOption) Option {
+	return func(l *Logger) error {
+		for key, fieldType := range fields {
+			if fieldType < FieldAny || fieldType > FieldArray {
+				return fmt.Errorf("emit: unknown field type %d for %q", fieldType, key)
+			}
+		}
+		s := &schema{fields: maps.Clone(fields)}
+		for _, opt := range opts {
+			opt(s)
+		}
+		l.schema = s
+		return nil
+	}
+}
+
+// SchemaCoerce converts values of the wrong type where possible, such as the
+// string "42" for an int field or 3.0 for an int field, instead of writing
+// them unchanged. Values that cannot be converted are reported and written
+// as they are.
+func SchemaCoerce() SchemaOption {
+	return func(s *schema) {
+		s.coerce = true
+	}
+}
+
+// SchemaDropUnknown removes undeclared fields from the entry after reporting
+// them
+func SchemaDropUnknown() SchemaOption {
+	return func(s *schema) {
+		s.dropUnknown = true
+	}
+}
+
+// validateSchema reports fields that do not match the schema, returning the
+// fields to log. The map is copied before it is changed.
+func (l *Logger) validateSchema(fields map[string]any) map[string]any {
+	var result map[string]any
+	change := func(key string, value any, drop bool) {
+		if result == nil {
+			result = maps.Clone(fields)
+		}
+		if drop {
+			delete(result, key)
+			return
+		}
+		result[key] = value
+	}
+
+	for key, value := range fields {
+		fieldType, declared := l.schema.fields[key]
+		if !declared {
+			l.reportError(fmt.Errorf("%w: field %q is not declared", ErrSchemaViolation, key))
+			if l.schema.dropUnknown {
+				change(key, nil, true)
+			}
+			continue
+		}
+		if matchesFieldType(value, fieldType) {
+			continue
+		}
+		if l.schema.coerce {
+			if coerced, ok := coerceFieldValue(value, fieldType); ok {
+				change(key, coerced, false)
+				continue
+			}
+		}
+		l.reportError(fmt.Errorf("%w: field %q is %T, want %s", ErrSchemaViolation, key, value, fieldType))
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// matchesFieldType reports whether value has the declared type. Nil matches
+// every type.
+func matchesFieldType(value any, fieldType FieldType) bool {
+	if value == nil || fieldType == FieldAny {
+		return true
+	}
+	t := reflect.TypeOf(value)
+	switch fieldType {
+	case FieldString:
+		return t.Kind() == reflect.String
+	case FieldInt:
+		return isIntKind(t.Kind())
+	case FieldFloat:
+		return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 || isIntKind(t.Kind())
+	case FieldBool:
+		return t.Kind() == reflect.Bool
+	case FieldTime:
+		return t == timeType
+	case FieldDuration:
+		return t == durationType
+	case FieldObject:
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		return t.Kind() == reflect.Map || (t.Kind() == reflect.Struct && t != timeType)
+	case FieldArray:
+		return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+	}
+	return false
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Int64) || (kind >= reflect.Uint && kind <= reflect.Uint64)
+}
+
+// coerceFieldValue converts value to the declared type
+func coerceFieldValue(value any, fieldType FieldType) (any, bool) {
+	s, isString := value.(string)
+	switch fieldType {
+	case FieldString:
+		if text, ok := textValue(value); ok {
+			return text, true
+		}
+		return fmt.Sprint(value), true
+	case FieldInt:
+		if isString {
+			n, err := strconv.ParseInt(s, 10, 64)
+			return n, err == nil
+		}
+		if f, ok := asFloat(value); ok && f == float64(int64(f)) {
+			return int64(f), true
+		}
+	case FieldFloat:
+		if isString {
+			f, err := strconv.ParseFloat(s, 64)
+			return f, err == nil
+		}
+	case FieldBool:
+		if isString {
+			b, err := strconv.ParseBool(s)
+			return b, err == nil
+		}
+	case FieldTime:
+		if isString {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			return t, err == nil
+		}
+	case FieldDuration:
+		if isString {
+			d, err := time.ParseDuration(s)
+			return d, err == nil
+		}
+	}
+	return nil, false
+}
+
+// asFloat returns a float value as float64
+func asFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	return 0, false
+}
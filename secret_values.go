@@ -0,0 +1,96 @@
+package emit
+
+import "encoding/json"
+
+// SecretValue is a value marked sensitive at the call site with Secret
+type SecretValue struct {
+	value any
+}
+
+// PIIValue is a value marked as personal data at the call site with PII
+type PIIValue struct {
+	value any
+}
+
+// Secret marks a value as sensitive whatever the field it is logged under,
+// for values the field lists cannot recognize:
+//
+//	logger.Info("token refreshed", "tmp", emit.Secret(token))
+//
+// The value is masked with the sensitive mask string, or hashed or dropped
+// under HASH_SENSITIVE and DROP_SENSITIVE. It stays masked when the logger
+// shows sensitive data, as the call site asked for it explicitly.
+func Secret(v any) SecretValue {
+	return SecretValue{value: v}
+}
+
+// PII marks a value as personal data whatever the field it is logged under:
+//
+//	logger.Info("invite sent", "to", emit.PII(address))
+//
+// The value is masked with the PII mask string, or dropped under DROP_PII,
+// and stays masked when the logger shows PII.
+func PII(v any) PIIValue {
+	return PIIValue{value: v}
+}
+
+// String returns the default mask string so the value never leaks through
+// fmt
+func (s SecretValue) String() string { return defaultMaskString }
+
+// MarshalJSON encodes the default mask string so the value never leaks
+// through encoding/json
+func (s SecretValue) MarshalJSON() ([]byte, error) { return json.Marshal(defaultMaskString) }
+
+// String returns the default PII mask string so the value never leaks
+// through fmt
+func (p PIIValue) String() string { return defaultPIIMaskString }
+
+// MarshalJSON encodes the default PII mask string so the value never leaks
+// through encoding/json
+func (p PIIValue) MarshalJSON() ([]byte, error) { return json.Marshal(defaultPIIMaskString) }
+
+// maskMarkedValue masks values wrapped with Secret or PII. ok is false for
+// other values.
+func (l *Logger) maskMarkedValue(key string, value any) (masked any, ok bool) {
+	switch v := value.(type) {
+	case SecretValue:
+		if l.sensitiveMode == SHOW_SENSITIVE {
+			l.observeMask(key, MaskCategorySensitive)
+			return l.partialMask.apply(v.value, l.maskString), true
+		}
+		return l.maskSensitive(key, v.value), true
+	case PIIValue:
+		l.observeMask(key, MaskCategoryPII)
+		if l.piiMode == DROP_PII {
+			return droppedField{}, true
+		}
+		return l.piiPartialMask.apply(v.value, l.piiMaskString), true
+	}
+	return nil, false
+}
+
+// maskMarkedElement masks a Secret or PII slice element, which is masked
+// rather than dropped under the drop modes
+func (l *Logger) maskMarkedElement(value any) any {
+	masked, _ := l.maskMarkedValue("", value)
+	if _, dropped := masked.(droppedField); !dropped {
+		return masked
+	}
+	if _, ok := value.(PIIValue); ok {
+		return l.piiMaskString
+	}
+	return l.maskString
+}
+
+// hasMarkedValues reports whether any top-level field is wrapped with Secret
+// or PII
+func hasMarkedValues(fields map[string]any) bool {
+	for _, value := range fields {
+		switch value.(type) {
+		case SecretValue, PIIValue:
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,13 @@
 package emit
 
 import (
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Default sensitive field patterns (case-insensitive)
@@ -45,37 +50,86 @@ var (
 		sensitiveCache: make(map[string]bool, 100),
 	}
 
-	// Pre-built lookup maps for O(1) field checking
+	// Pre-built lookup maps for O(1) field checking, guarded by fieldMapsMu.
+	// The maps are replaced (never mutated) when patterns change at runtime.
 	piiFieldsMap       map[string]bool
 	sensitiveFieldsMap map[string]bool
+	fieldMapsMu        sync.RWMutex
 	onceInit           sync.Once
+
+	// globalFieldsChanged is set once the global field lists are modified, so
+	// the fixed lists of the structured hot path no longer apply
+	globalFieldsChanged atomic.Bool
 )
 
+// buildFieldMap creates a lookup map containing each pattern and its uppercase variant
+func buildFieldMap(patterns []string) map[string]bool {
+	fieldMap := make(map[string]bool, len(patterns)*2)
+	for _, pattern := range patterns {
+		pattern = foldFieldName(pattern)
+		fieldMap[pattern] = true
+		fieldMap[strings.ToUpper(pattern)] = true // Add uppercase variant
+	}
+	return fieldMap
+}
+
 // initializeFieldMaps builds lookup maps for O(1) field pattern matching
 func initializeFieldMaps() {
 	onceInit.Do(func() {
-		// Build PII fields map
-		piiFieldsMap = make(map[string]bool, len(defaultPIIFields)*2)
-		for _, pattern := range defaultPIIFields {
-			piiFieldsMap[pattern] = true
-			piiFieldsMap[strings.ToUpper(pattern)] = true // Add uppercase variant
-		}
+		fieldMapsMu.Lock()
+		defer fieldMapsMu.Unlock()
 
-		// Build sensitive fields map
-		sensitiveFieldsMap = make(map[string]bool, len(defaultSensitiveFields)*2)
-		for _, pattern := range defaultSensitiveFields {
-			sensitiveFieldsMap[pattern] = true
-			sensitiveFieldsMap[strings.ToUpper(pattern)] = true // Add uppercase variant
-		}
+		piiFieldsMap = buildFieldMap(defaultPIIFields)
+		sensitiveFieldsMap = buildFieldMap(defaultSensitiveFields)
 	})
 }
 
+// updateGlobalFieldMap replaces a global lookup map with a modified copy and invalidates the cache
+func updateGlobalFieldMap(target *map[string]bool, patterns []string, add bool) {
+	initializeFieldMaps()
+
+	fieldMapsMu.Lock()
+	defer fieldMapsMu.Unlock()
+	globalFieldsChanged.Store(true)
+
+	updated := make(map[string]bool, len(*target)+len(patterns)*2)
+	for pattern := range *target {
+		updated[pattern] = true
+	}
+
+	for _, pattern := range patterns {
+		pattern = foldFieldName(pattern)
+		if pattern == "" {
+			continue
+		}
+		if add {
+			updated[pattern] = true
+			updated[strings.ToUpper(pattern)] = true
+		} else {
+			delete(updated, pattern)
+			delete(updated, strings.ToUpper(pattern))
+		}
+	}
+
+	*target = updated
+
+	// Cached results may reflect the old patterns
+	ClearFieldCache()
+}
+
 // Fast PII field checking with caching
 func (l *Logger) isPIIFieldFast(fieldName string) bool {
-	if l.piiMode == SHOW_PII {
+	if l.piiMode == SHOW_PII || l.isMaskExempt(fieldName) {
 		return false
 	}
 
+	// Per-logger field lists override the global maps when set
+	if l.fieldRules != nil {
+		if isPII, handled := l.fieldRules.isPII(fieldName); handled {
+			return isPII
+		}
+	}
+
 	initializeFieldMaps()
 
 	// Check cache first
@@ -86,29 +140,12 @@ func (l *Logger) isPIIFieldFast(fieldName string) bool {
 	}
 	fieldCache.mu.RUnlock()
 
-	// Fast lookup in pre-built map
-	lowerFieldName := strings.ToLower(fieldName)
-	isPII := piiFieldsMap[lowerFieldName]
-
-	if !isPII {
-		// Fallback to substring search only if direct lookup fails
-		// Check if field name contains the pattern as a word or suffix/prefix
-		for pattern := range piiFieldsMap {
-			if strings.Contains(lowerFieldName, pattern) {
-				// Additional check to avoid false positives like "description" matching "ip"
-				// Only match if the pattern is at word boundaries or is a significant portion
-				if len(pattern) >= 3 || lowerFieldName == pattern ||
-					strings.HasPrefix(lowerFieldName, pattern+"_") ||
-					strings.HasSuffix(lowerFieldName, "_"+pattern) ||
-					strings.Contains(lowerFieldName, "_"+pattern+"_") ||
-					strings.HasPrefix(lowerFieldName, pattern) && len(pattern) >= len(lowerFieldName)/2 ||
-					strings.HasSuffix(lowerFieldName, pattern) && len(pattern) >= len(lowerFieldName)/2 {
-					isPII = true
-					break
-				}
-			}
-		}
-	}
+	// Hold the map lock until the result is cached so a concurrent
+	// pattern update cannot leave a stale cache entry behind
+	fieldMapsMu.RLock()
+	defer fieldMapsMu.RUnlock()
+
+	isPII := matchPIIPattern(piiFieldsMap, fieldName)
 
 	// Cache the result
 	fieldCache.mu.Lock()
@@ -118,12 +155,168 @@ func (l *Logger) isPIIFieldFast(fieldName string) bool {
 	return isPII
 }
 
+// matchPIIPattern checks a field name against a PII lookup map.
+// Patterns only match whole tokens so "description" never matches "ip".
+func matchPIIPattern(patterns map[string]bool, fieldName string) bool {
+	return piiPatternMatch(patterns, fieldName) != ""
+}
+
+// piiPatternMatch returns the pattern in a PII lookup map that fieldName
+// matches, or "" if none does
+func piiPatternMatch(patterns map[string]bool, fieldName string) string {
+	// Fast lookup in pre-built map
+	if folded := foldFieldName(fieldName); patterns[folded] {
+		return folded
+	}
+
+	// Tokenize the original name so camelCase boundaries are preserved
+	fieldTokens := tokenizeFieldName(fieldName)
+	if len(fieldTokens) < 2 {
+		// A single token can only match a pattern exactly, which the direct lookup covered
+		if len(fieldTokens) == 1 && patterns[fieldTokens[0]] {
+			return fieldTokens[0]
+		}
+		return ""
+	}
+
+	for pattern := range patterns {
+		if containsTokenSequence(fieldTokens, tokenizeFieldName(pattern)) {
+			return pattern
+		}
+	}
+
+	return ""
+}
+
+// foldFieldName case-folds a field name or pattern so that matching does not
+// depend on how the name was cased. ASCII names are lower-cased; otherwise
+// every rune maps to the lower case of its case-folding orbit, so "ſ" and "s"
+// or the Kelvin sign and "k" compare equal, and the Turkish dotted and dotless
+// I both become "i" ("İBAN", "ıban" and "IBAN" all fold to "iban").
+func foldFieldName(name string) string {
+	ascii := true
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return strings.ToLower(name)
+	}
+
+	var b strings.Builder
+	b.Grow(len(name))
+	prev := rune(-1)
+	for _, r := range name {
+		switch r {
+		case 'İ', 'ı':
+			r = 'i'
+		case '\u0307':
+			// Combining dot above left behind when "İ" was lower-cased elsewhere
+			if prev == 'i' {
+				continue
+			}
+		default:
+			r = foldRune(r)
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// foldRune returns the lower case of the smallest rune in r's folding orbit
+func foldRune(r rune) rune {
+	smallest := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < smallest {
+			smallest = f
+		}
+	}
+	return unicode.ToLower(smallest)
+}
+
+// tokenizeFieldName splits a field name into case-folded word tokens on
+// underscores, non-alphanumeric characters, camelCase and letter/digit boundaries
+func tokenizeFieldName(fieldName string) []string {
+	var tokens []string
+	runes := []rune(fieldName)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				tokens = append(tokens, foldFieldName(string(runes[start:i])))
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 && isTokenBoundary(runes, i) {
+			tokens = append(tokens, foldFieldName(string(runes[start:i])))
+			start = i
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, foldFieldName(string(runes[start:])))
+	}
+
+	return tokens
+}
+
+// isTokenBoundary reports whether a new token starts at runes[i]
+func isTokenBoundary(runes []rune, i int) bool {
+	prev, curr := runes[i-1], runes[i]
+
+	switch {
+	case unicode.IsDigit(prev) != unicode.IsDigit(curr):
+		// "address2" -> "address", "2"
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(curr):
+		// "clientIp" -> "client", "Ip"
+		return true
+	case unicode.IsUpper(prev) && unicode.IsUpper(curr) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+		// "HTTPServer" -> "HTTP", "Server"
+		return true
+	default:
+		return false
+	}
+}
+
+// containsTokenSequence reports whether pattern appears as contiguous whole tokens in tokens
+func containsTokenSequence(tokens, pattern []string) bool {
+	if len(pattern) == 0 || len(pattern) > len(tokens) {
+		return false
+	}
+
+	for i := 0; i+len(pattern) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(pattern)], pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Fast sensitive field checking with caching
 func (l *Logger) isSensitiveFieldFast(fieldName string) bool {
-	if l.sensitiveMode == SHOW_SENSITIVE {
+	if l.sensitiveMode == SHOW_SENSITIVE || l.isMaskExempt(fieldName) {
 		return false
 	}
 
+	// Per-logger field lists override the global maps when set
+	if l.fieldRules != nil {
+		if isSensitive, handled := l.fieldRules.isSensitive(fieldName); handled {
+			return isSensitive
+		}
+	}
+
 	initializeFieldMaps()
 
 	// Check cache first
@@ -134,19 +327,10 @@ func (l *Logger) isSensitiveFieldFast(fieldName string) bool {
 	}
 	fieldCache.mu.RUnlock()
 
-	// Fast lookup in pre-built map
-	lowerFieldName := strings.ToLower(fieldName)
-	isSensitive := sensitiveFieldsMap[lowerFieldName]
-
-	if !isSensitive {
-		// Fallback to substring search only if direct lookup fails
-		for pattern := range sensitiveFieldsMap {
-			if strings.Contains(lowerFieldName, pattern) {
-				isSensitive = true
-				break
-			}
-		}
-	}
+	fieldMapsMu.RLock()
+	defer fieldMapsMu.RUnlock()
+
+	isSensitive := matchSensitivePattern(sensitiveFieldsMap, foldFieldName(fieldName))
 
 	// Cache the result
 	fieldCache.mu.Lock()
@@ -156,32 +340,341 @@ func (l *Logger) isSensitiveFieldFast(fieldName string) bool {
 	return isSensitive
 }
 
-// Optimized field masking with pre-allocated map and minimal allocations
+// matchSensitivePattern checks a lower-cased field name against a sensitive lookup map
+func matchSensitivePattern(patterns map[string]bool, lowerFieldName string) bool {
+	return sensitivePatternMatch(patterns, lowerFieldName) != ""
+}
+
+// sensitivePatternMatch returns the pattern in a sensitive lookup map that
+// lowerFieldName matches, or "" if none does
+func sensitivePatternMatch(patterns map[string]bool, lowerFieldName string) string {
+	// Fast lookup in pre-built map
+	if patterns[lowerFieldName] {
+		return lowerFieldName
+	}
+
+	// Fallback to substring search only if direct lookup fails
+	for pattern := range patterns {
+		if strings.Contains(lowerFieldName, pattern) {
+			return pattern
+		}
+	}
+
+	return ""
+}
+
+// Optimized field masking with pre-allocated map and minimal allocations. When
+// anything is masked the result shares no maps or slices with fields, so it
+// can be changed freely; when masking is off fields may be returned as is.
 func (l *Logger) maskSensitiveFieldsFast(fields map[string]any) map[string]any {
-	if (l.sensitiveMode == SHOW_SENSITIVE && l.piiMode == SHOW_PII) || len(fields) == 0 {
-		return fields
+	if len(l.defaultFields) > 0 {
+		return l.mergeDefaultFields(fields)
+	}
+	return l.maskFields(fields)
+}
+
+// maskFields masks a field map without adding default fields
+func (l *Logger) maskFields(fields map[string]any) map[string]any {
+	if l.skipsMasking(fields) || len(fields) == 0 {
+		return textFields(fields)
+	}
+
+	return l.maskFieldMap(l.applyMaskPaths(fields), nil)
+}
+
+// skipsMasking reports whether fields can be written without masking: they
+// were masked before the hooks ran, or both modes show data, no Secret or PII value or rule that masks whatever the
+// mode is present, and no container needs its cycles broken
+func (l *Logger) skipsMasking(fields map[string]any) bool {
+	return l.premasked || l.sensitiveMode == SHOW_SENSITIVE && l.piiMode == SHOW_PII && !hasMarkedValues(fields) &&
+		!l.hasMaskFuncs() && !l.detectsValues() && !l.hasJSONFields() &&
+		len(l.maskCategories) == 0 && !hasContainers(fields)
+}
+
+// hasContainers reports whether any top-level field holds a map, slice,
+// struct or pointer, which can nest too deep or reference itself
+func hasContainers(fields map[string]any) bool {
+	for _, value := range fields {
+		switch value.(type) {
+		case nil, string, bool, int, int64, float64:
+			continue
+		}
+		if _, ok := textValue(value); ok {
+			continue
+		}
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer, reflect.Interface:
+			return true
+		}
 	}
+	return false
+}
+
+// circularReferenceMarker replaces containers that reference one of their ancestors
+const circularReferenceMarker = "[circular]"
+
+// maskVisitKey identifies a map or slice already on the current recursion path
+type maskVisitKey struct {
+	ptr    uintptr
+	length int
+}
 
+// maskFieldMap masks a single map level, tracking visited containers to break cycles
+func (l *Logger) maskFieldMap(fields map[string]any, visited map[maskVisitKey]bool) map[string]any {
 	// Pre-allocate with exact capacity to avoid map growth
 	maskedFields := make(map[string]any, len(fields))
+	l.maskFieldsInto(maskedFields, fields, visited)
+	return maskedFields
+}
 
+// maskFieldsInto writes the masked form of each field into dst
+func (l *Logger) maskFieldsInto(dst, fields map[string]any, visited map[maskVisitKey]bool) map[maskVisitKey]bool {
 	for key, value := range fields {
-		// Fast path: check PII first (more specific), then sensitive data
-		if l.isPIIFieldFast(key) {
-			maskedFields[key] = l.piiMaskString
-		} else if l.isSensitiveFieldFast(key) {
-			maskedFields[key] = l.maskString
-		} else {
-			// Handle nested maps recursively
-			if nestedMap, ok := value.(map[string]any); ok {
-				maskedFields[key] = l.maskSensitiveFieldsFast(nestedMap)
-			} else {
-				maskedFields[key] = value
+		if masked, final := l.maskFieldValue(key, value); final {
+			if _, dropped := masked.(droppedField); !dropped {
+				dst[key] = masked
 			}
+			continue
 		}
+		dst[key], visited = l.maskNestedValue(value, visited)
 	}
+	return visited
+}
 
-	return maskedFields
+// droppedField is returned by maskFieldValue for fields omitted by DROP_PII or
+// DROP_SENSITIVE
+type droppedField struct{}
+
+// maskFieldValue decides how a single field is emitted. When final is true the
+// returned value replaces the field as-is, or is a droppedField when the field is
+// omitted; otherwise the original value is kept and nested maps or slices
+// inside it still need to be masked.
+func (l *Logger) maskFieldValue(key string, value any) (masked any, final bool) {
+	// Stack traces are diagnostic data and are never masked
+	if _, ok := value.(StackTrace); ok {
+		return value, true
+	}
+	// Request IDs are correlation data and are never masked
+	if id, ok := value.(requestID); ok {
+		return string(id), true
+	}
+
+	// Values wrapped with Secret or PII are masked whatever their name
+	if masked, ok := l.maskMarkedValue(key, value); ok {
+		return masked, true
+	}
+
+	// Custom mask functions take precedence over default masking
+	if maskFunc := l.maskFuncFor(key); maskFunc != nil {
+		if tagged, ok := value.(taggedMask); ok {
+			value = tagged.value
+		}
+		l.observeMask(key, MaskCategoryCustom)
+		return maskFunc(value), true
+	}
+
+	// Struct fields tagged `log:"mask"` and mask paths are sensitive whatever their name
+	if tagged, ok := value.(taggedMask); ok {
+		if l.sensitiveMode == SHOW_SENSITIVE {
+			return tagged.value, true
+		}
+		return l.maskSensitive(key, tagged.value), true
+	}
+
+	// Fast path: check the categories, then PII (more specific), then sensitive data
+	if category := l.categoryFor(key); category != nil {
+		return category.mask(l, key, value), true
+	}
+	if l.isPIIFieldFast(key) {
+		l.observeMask(key, MaskCategoryPII)
+		if l.piiMode == DROP_PII {
+			return droppedField{}, true
+		}
+		return l.piiPartialMask.apply(value, l.piiMaskString), true
+	}
+	if l.isSensitiveFieldFast(key) {
+		return l.maskSensitive(key, value), true
+	}
+	// JSON documents are masked inside before value patterns see the whole string
+	if s, ok := value.(string); ok && l.isJSONField(key) {
+		return l.redactJSON(key, s), true
+	}
+	if !l.allowValueScan(value) {
+		return nil, false
+	}
+	if l.matchesValuePattern(value) && !l.isMaskExempt(key) {
+		return l.maskSensitive(key, value), true
+	}
+	if l.matchesEntropy(value) && !l.isMaskExempt(key) {
+		return l.maskSensitive(key, value), true
+	}
+
+	return nil, false
+}
+
+// maskSensitive masks or drops a detected sensitive value
+func (l *Logger) maskSensitive(key string, value any) any {
+	l.observeMask(key, MaskCategorySensitive)
+	if l.sensitiveMode == DROP_SENSITIVE {
+		return droppedField{}
+	}
+	return l.maskSensitiveValue(value)
+}
+
+// maskNestedValue descends into nested maps and slices so their fields are masked too
+func (l *Logger) maskNestedValue(value any, visited map[maskVisitKey]bool) (any, map[maskVisitKey]bool) {
+	var visitKey maskVisitKey
+
+	switch v := value.(type) {
+	case map[string]any:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer()}
+	case Fields:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer()}
+	case []map[string]any:
+		if len(v) == 0 {
+			return value, visited
+		}
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer(), length: len(v)}
+	case []any:
+		if len(v) == 0 {
+			return value, visited
+		}
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer(), length: len(v)}
+	case SecretValue, PIIValue:
+		return l.maskMarkedElement(value), visited
+	case nil, string, bool, int, int64, float64:
+		return value, visited
+	default:
+		if text, ok := textValue(value); ok {
+			return text, visited
+		}
+		fields, ptr, ok := l.structAsMap(value)
+		if !ok {
+			return copyContainer(value, visited)
+		}
+		if ptr == 0 {
+			return l.maskFieldMap(fields, visited), visited
+		}
+		// Pointers are tracked so self-referencing structs terminate
+		visitKey = maskVisitKey{ptr: ptr}
+		value = fields
+	}
+
+	// Only allocate the visited set once nesting is actually encountered
+	if visited == nil {
+		visited = make(map[maskVisitKey]bool, 4)
+	}
+	if visited[visitKey] {
+		return circularReferenceMarker, visited
+	}
+	// The visited set holds exactly the containers above this one
+	if len(visited) >= l.nestingLimit() {
+		return maxDepthMarker, visited
+	}
+	visited[visitKey] = true
+	defer delete(visited, visitKey)
+
+	switch v := value.(type) {
+	case map[string]any:
+		return l.maskFieldMap(v, visited), visited
+	case Fields:
+		return Fields(l.maskFieldMap(v, visited)), visited
+	case []map[string]any:
+		maskedSlice := make([]map[string]any, len(v))
+		var markedSlice []any
+		for i, element := range v {
+			masked, _ := l.maskNestedValue(element, visited)
+			if markedSlice != nil {
+				markedSlice[i] = masked
+				continue
+			}
+			if maskedMap, ok := masked.(map[string]any); ok {
+				maskedSlice[i] = maskedMap
+				continue
+			}
+			// Cycle and depth markers are strings, so the slice is written as []any
+			markedSlice = make([]any, len(v))
+			for j := range i {
+				markedSlice[j] = maskedSlice[j]
+			}
+			markedSlice[i] = masked
+		}
+		if markedSlice != nil {
+			return markedSlice, visited
+		}
+		return maskedSlice, visited
+	default:
+		elements := value.([]any)
+		maskedSlice := make([]any, len(elements))
+		for i, element := range elements {
+			maskedSlice[i], _ = l.maskNestedValue(element, visited)
+		}
+		return maskedSlice, visited
+	}
+}
+
+// copyContainer deep-copies maps and slices that masking does not descend
+// into, such as []string or map[string]int, so the masked result never
+// shares them with the caller. Other values are returned as is.
+func copyContainer(value any, visited map[maskVisitKey]bool) (any, map[maskVisitKey]bool) {
+	rv := reflect.ValueOf(value)
+	if kind := rv.Kind(); kind != reflect.Map && kind != reflect.Slice {
+		return value, visited
+	}
+	if visited == nil {
+		visited = make(map[maskVisitKey]bool, 4)
+	}
+	return copyReflectValue(rv, visited).Interface(), visited
+}
+
+// copyReflectValue copies maps and slices recursively. A container already on
+// the current path is left shared rather than copied forever.
+func copyReflectValue(rv reflect.Value, visited map[maskVisitKey]bool) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		return copyReflectValue(rv.Elem(), visited)
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		visitKey := maskVisitKey{ptr: rv.Pointer()}
+		if visited[visitKey] {
+			return rv
+		}
+		visited[visitKey] = true
+		defer delete(visited, visitKey)
+
+		copied := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			copied.SetMapIndex(iter.Key(), copyReflectValue(iter.Value(), visited))
+		}
+		return copied
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		visitKey := maskVisitKey{ptr: rv.Pointer(), length: rv.Len()}
+		if visited[visitKey] {
+			return rv
+		}
+		visited[visitKey] = true
+		defer delete(visited, visitKey)
+
+		copied := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		switch rv.Type().Elem().Kind() {
+		case reflect.Map, reflect.Slice, reflect.Interface:
+			for i := range rv.Len() {
+				copied.Index(i).Set(copyReflectValue(rv.Index(i), visited))
+			}
+		default:
+			reflect.Copy(copied, rv)
+		}
+		return copied
+	}
+	return rv
 }
 
 // ClearFieldCache clears the field pattern cache (for testing or dynamic field updates)
@@ -192,3 +685,32 @@ func ClearFieldCache() {
 	fieldCache.piiCache = make(map[string]bool, 100)
 	fieldCache.sensitiveCache = make(map[string]bool, 100)
 }
+
+// ResetFieldLists restores the global PII and sensitive field lists to their
+// defaults, undoing AddPIIField, AddSensitiveField and their Remove
+// counterparts, and clears the field cache. The default logger's field lists
+// are reset as well, and mask paths, exemptions and JSON fields are removed.
+// It is intended for test teardown:
+//
+//	t.Cleanup(emit.ResetFieldLists)
+func ResetFieldLists() {
+	logger := Default()
+	initializeFieldMaps()
+
+	fieldMapsMu.Lock()
+	piiFieldsMap = buildFieldMap(defaultPIIFields)
+	sensitiveFieldsMap = buildFieldMap(defaultSensitiveFields)
+	globalFieldsChanged.Store(false)
+	fieldMapsMu.Unlock()
+
+	ClearFieldCache()
+	updateMaskPaths(func([][]string) [][]string { return nil })
+	updateMaskExemptions(func(exempt map[string]bool) { clear(exempt) })
+	updateJSONFields(func(names map[string]bool) { clear(names) })
+
+	if logger != nil {
+		logger.updateFieldList(true, func([]string) []string { return defaultPIIFields })
+		logger.updateFieldList(false, func([]string) []string { return defaultSensitiveFields })
+		logger.rules().reset()
+	}
+}
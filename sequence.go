@@ -0,0 +1,38 @@
+package emit
+
+import "maps"
+
+// sequenceKey is the field carrying the number set by WithSequence
+const sequenceKey = "seq"
+
+// WithSequence numbers every line in a seq field, 1, 2, 3 and so on, so a
+// pipeline can check that no line was lost or reordered downstream:
+//
+//	logger, _ := emit.New(emit.WithSequence())
+//	logger.Info("started") // "seq":1
+//
+// The counter is shared by the logger and the children created with
+// WithFields or Named, survives Reconfigure and is incremented atomically,
+// so lines logged concurrently get distinct numbers without locking, though
+// they may reach the writer in a different order. Numbers are assigned as
+// lines are written, after sampling, deduplication and hooks, so only lost
+// lines leave gaps. The counter lives in memory and starts again at 1 when
+// the process restarts; tell restarts apart with a field such as an instance ID.
+// A "seq" field passed at the call site is replaced.
+func WithSequence() Option {
+	return func(l *Logger) error {
+		l.sequence = true
+		return nil
+	}
+}
+
+// withSequence sets the next sequence number over any field of the same name
+func (l *Logger) withSequence(fields map[string]any) map[string]any {
+	if !l.sequence || l.state == nil {
+		return fields
+	}
+	withSequence := make(map[string]any, len(fields)+1)
+	maps.Copy(withSequence, fields)
+	withSequence[sequenceKey] = l.state.sequence.Add(1)
+	return withSequence
+}
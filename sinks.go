@@ -0,0 +1,116 @@
+package emit
+
+import (
+	"errors"
+	"io"
+	"math"
+	"os"
+)
+
+// Sink is a destination for encoded log lines. Entries below MinLevel are
+// not written to it; the zero value, DEBUG, accepts every predefined level.
+type Sink struct {
+	Writer   io.Writer
+	MinLevel LogLevel
+}
+
+// levelWriter is implemented by writers that route lines by level
+type levelWriter interface {
+	WriteLevel(level LogLevel, p []byte) (int, error)
+}
+
+// noLevel marks lines written without a level, which every sink accepts
+const noLevel = LogLevel(math.MinInt32)
+
+// writeLevel writes a line, passing the level along to writers that use it
+func writeLevel(w io.Writer, level LogLevel, line []byte) error {
+	if lw, ok := w.(levelWriter); ok {
+		_, err := lw.WriteLevel(level, line)
+		return err
+	}
+	_, err := w.Write(line)
+	return err
+}
+
+// MultiSink writes every line to each of its sinks
+type MultiSink struct {
+	sinks []Sink
+}
+
+// MultiWriter returns a writer that duplicates each line to all writers
+func MultiWriter(writers ...io.Writer) *MultiSink {
+	sinks := make([]Sink, 0, len(writers))
+	for _, w := range writers {
+		sinks = append(sinks, Sink{Writer: w})
+	}
+	return &MultiSink{sinks: sinks}
+}
+
+// WithSinks fans each entry out to several sinks, each with its own minimum
+// level. The logger's level still applies first.
+func WithSinks(sinks ...Sink) Option {
+	return func(l *Logger) error {
+		if len(sinks) == 0 {
+			return errors.New("emit: at least one sink is required")
+		}
+		for _, s := range sinks {
+			if s.Writer == nil {
+				return errors.New("emit: sink writer must not be nil")
+			}
+		}
+		l.writer = &MultiSink{sinks: append([]Sink(nil), sinks...)}
+		return nil
+	}
+}
+
+// Write writes p to every sink regardless of level
+func (m *MultiSink) Write(p []byte) (int, error) {
+	return m.WriteLevel(noLevel, p)
+}
+
+// WriteLevel writes p to every sink accepting level. A failing sink does not
+// stop delivery to the others; all errors are returned joined. Sinks are
+// written in order, so wrap a slow sink in its own WithAsync logger if it
+// must not delay the rest.
+func (m *MultiSink) WriteLevel(level LogLevel, p []byte) (int, error) {
+	var errs []error
+	for _, s := range m.sinks {
+		if level != noLevel && level < s.MinLevel {
+			continue
+		}
+		if err := writeLevel(s.Writer, level, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// RetainsBuffer reports whether any sink keeps written slices
+func (m *MultiSink) RetainsBuffer() bool {
+	for _, s := range m.sinks {
+		if r, ok := s.Writer.(BufferRetainer); ok && r.RetainsBuffer() {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync flushes every sink that supports it
+func (m *MultiSink) Sync() error {
+	var errs []error
+	for _, s := range m.sinks {
+		errs = append(errs, syncWriter(s.Writer))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every sink that implements io.Closer, except stdout and stderr
+func (m *MultiSink) Close() error {
+	var errs []error
+	for _, s := range m.sinks {
+		if c, ok := s.Writer.(io.Closer); ok && s.Writer != os.Stdout && s.Writer != os.Stderr {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,987 @@
+package emit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileSinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := FileSink(path, WithMaxSize(256), WithMaxBackups(2), WithCompress())
+	if err != nil {
+		t.Fatalf("Unexpected error opening file sink: %v", err)
+	}
+
+	testLogger, err := New(WithOutput(sink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				testLogger.Info("rotation test", "iteration", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := testLogger.Close(); err != nil {
+		t.Fatalf("Unexpected error closing logger: %v", err)
+	}
+	if _, err := sink.Write([]byte("late\n")); err == nil {
+		t.Errorf("Expected write after Close to fail")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reading dir: %v", err)
+	}
+	var backups int
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == "app.log" {
+			continue
+		}
+		backups++
+		if !strings.HasPrefix(name, "app-") || !strings.HasSuffix(name, ".log.gz") {
+			t.Errorf("Unexpected backup name %q", name)
+		}
+	}
+	if backups == 0 || backups > 2 {
+		t.Errorf("Expected 1-2 compressed backups, got %d", backups)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading log file: %v", err)
+	}
+	if len(data) == 0 || len(data) > 256 {
+		t.Errorf("Expected current file within the size limit, got %d bytes", len(data))
+	}
+}
+
+func TestFileSinkFailedRotation(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := FileSink(path, WithMaxSize(16))
+	if err != nil {
+		t.Fatalf("Unexpected error opening file sink: %v", err)
+	}
+	defer sink.Close()
+
+	if _, err := sink.Write([]byte("first entry\n")); err != nil {
+		t.Fatalf("Unexpected error writing: %v", err)
+	}
+	// Removing the directory makes renaming the file to a backup fail
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Unexpected error removing dir: %v", err)
+	}
+	if _, err := sink.Write([]byte("second entry\n")); err == nil {
+		t.Errorf("Expected the failed rotation to be reported")
+	}
+	if _, err := sink.Write([]byte("third entry\n")); err != nil {
+		t.Fatalf("Expected writes to recover after a failed rotation, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading log file: %v", err)
+	}
+	if string(data) != "third entry\n" {
+		t.Errorf("Expected the reopened file to hold the next entry, got %q", data)
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := SyslogSink("udp", conn.LocalAddr().String(), "billing")
+	if err != nil {
+		t.Fatalf("Unexpected error creating syslog sink: %v", err)
+	}
+	defer sink.Close()
+
+	testLogger, err := New(WithOutput(sink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Error("payment failed", "card_number", "4111111111111111")
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	packet := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(packet)
+	if err != nil {
+		t.Fatalf("Unexpected error reading syslog packet: %v", err)
+	}
+	msg := string(packet[:n])
+
+	if !strings.HasPrefix(msg, "<11>1 ") {
+		t.Errorf("Expected user.err priority header, got %q", msg)
+	}
+	if !strings.Contains(msg, " billing ") || !strings.Contains(msg, ` - - {"timestamp":`) {
+		t.Errorf("Expected tag and JSON payload, got %q", msg)
+	}
+	if strings.Contains(msg, "4111111111111111") {
+		t.Errorf("Expected payload to be masked, got %q", msg)
+	}
+}
+
+func TestSyslogSinkFallback(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var fallback bytes.Buffer
+	sink, err := SyslogSink("tcp", addr, "app", SyslogFallback(&fallback))
+	if err != nil {
+		t.Fatalf("Expected fallback to absorb dial failure, got %v", err)
+	}
+	defer sink.Close()
+
+	if _, err := sink.WriteLevel(WARN, []byte("{\"message\":\"degraded\"}\n")); err != nil {
+		t.Fatalf("Unexpected error writing to fallback: %v", err)
+	}
+	if !strings.Contains(fallback.String(), "degraded") {
+		t.Errorf("Expected line in fallback writer, got %q", fallback.String())
+	}
+
+	if _, err := SyslogSink("tcp", addr, "app"); err == nil {
+		t.Errorf("Expected dial error without fallback")
+	}
+}
+
+func TestSyslogSinkReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var fallback bytes.Buffer
+	sink, err := SyslogSink("tcp", addr, "app", SyslogFallback(&fallback))
+	if err != nil {
+		t.Fatalf("Expected fallback to absorb dial failure, got %v", err)
+	}
+	defer sink.Close()
+
+	listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("Cannot listen on %s again: %v", addr, err)
+	}
+	defer listener.Close()
+
+	if _, err := sink.Write([]byte("{\"message\":\"early\"}\n")); err != nil {
+		t.Fatalf("Unexpected error writing to fallback: %v", err)
+	}
+	if !strings.Contains(fallback.String(), "early") {
+		t.Errorf("Expected the redial to wait out its backoff, got fallback %q", fallback.String())
+	}
+
+	time.Sleep(2 * syslogMinBackoff)
+	if _, err := sink.Write([]byte("{\"message\":\"recovered\"}\n")); err != nil {
+		t.Fatalf("Unexpected error writing after backoff: %v", err)
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("Unexpected error accepting: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line := make([]byte, 4096)
+	n, err := conn.Read(line)
+	if err != nil || !strings.Contains(string(line[:n]), "recovered") {
+		t.Errorf("Expected the line on the redialed connection, got %q (%v)", line[:n], err)
+	}
+}
+
+func TestSyslogSinkLocalReconnect(t *testing.T) {
+	dir, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatalf("Unexpected error creating dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log")
+	original := localSyslogPaths
+	localSyslogPaths = []string{path}
+	defer func() { localSyslogPaths = original }()
+
+	listen := func() net.PacketConn {
+		conn, err := net.ListenPacket("unixgram", path)
+		if err != nil {
+			t.Skipf("unixgram not available: %v", err)
+		}
+		return conn
+	}
+	read := func(conn net.PacketConn) string {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		packet := make([]byte, 4096)
+		n, _, err := conn.ReadFrom(packet)
+		if err != nil {
+			t.Fatalf("Unexpected error reading syslog packet: %v", err)
+		}
+		return string(packet[:n])
+	}
+
+	server := listen()
+	var fallback bytes.Buffer
+	sink, err := SyslogSink("", "", "app", SyslogFallback(&fallback))
+	if err != nil {
+		t.Fatalf("Unexpected error creating syslog sink: %v", err)
+	}
+	defer sink.Close()
+	sink.Write([]byte("{\"message\":\"first\"}\n"))
+	if msg := read(server); !strings.Contains(msg, "first") {
+		t.Errorf("Expected the first line on the local socket, got %q", msg)
+	}
+
+	// Restart the server: the dropped connection is redialed on the same path
+	server.Close()
+	os.Remove(path)
+	sink.Write([]byte("{\"message\":\"lost\"}\n"))
+	server = listen()
+	defer server.Close()
+	time.Sleep(2 * syslogMinBackoff)
+	sink.Write([]byte("{\"message\":\"recovered\"}\n"))
+	if msg := read(server); !strings.Contains(msg, "recovered") {
+		t.Errorf("Expected the line on the redialed socket, got %q", msg)
+	}
+}
+
+func TestMemorySink(t *testing.T) {
+	sink := NewMemorySink()
+
+	testLogger, err := New(WithOutput(sink), WithLevel(DEBUG))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Debug("starting")
+	testLogger.Info("user login", "email", "john@example.com", "password", "hunter2", "attempt", 2)
+	testLogger.ErrorStructured("login failed", ZString("user_id", "u-1"))
+
+	entries := sink.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Level != DEBUG || entries[0].Message != "starting" || entries[0].Fields != nil {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+
+	login := entries[1]
+	if login.Fields["password"] != "***MASKED***" || login.Fields["email"] != "***PII***" {
+		t.Errorf("Expected masked fields to be recorded, got %v", login.Fields)
+	}
+	if login.Fields["attempt"] != float64(2) {
+		t.Errorf("Expected attempt=2, got %v", login.Fields["attempt"])
+	}
+
+	if !sink.Contains(ERROR, "failed") || sink.Contains(INFO, "failed") {
+		t.Errorf("Contains did not match by level and message")
+	}
+	if last, ok := sink.LastEntry(); !ok || last.Message != "login failed" || last.Fields["user_id"] != "u-1" {
+		t.Errorf("Unexpected last entry: %+v", last)
+	}
+
+	sink.Reset()
+	if _, ok := sink.LastEntry(); ok {
+		t.Errorf("Expected no entries after Reset")
+	}
+}
+
+func TestLevelRouting(t *testing.T) {
+	stdout := NewMemorySink()
+	warnings := NewMemorySink()
+	errorsSink := NewMemorySink()
+
+	testLogger, err := New(
+		WithLevelRouting(map[LogLevel]io.Writer{WARN: warnings, ERROR: errorsSink}),
+		WithOutput(stdout),
+		WithLevel(DEBUG),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Debug("debugging")
+	testLogger.Info("started")
+	testLogger.Warn("slow")
+	testLogger.ErrorStructured("failed", ZString("user_id", "u-1"))
+	testLogger.log(ERROR+1, "down", nil)
+
+	if got := len(stdout.Entries()); got != 2 || !stdout.Contains(INFO, "started") {
+		t.Errorf("Expected DEBUG and INFO on the fallback writer, got %+v", stdout.Entries())
+	}
+	if got := len(warnings.Entries()); got != 1 || !warnings.Contains(WARN, "slow") {
+		t.Errorf("Expected only WARN on the warning writer, got %+v", warnings.Entries())
+	}
+	if got := len(errorsSink.Entries()); got != 2 || !errorsSink.Contains(ERROR+1, "down") {
+		t.Errorf("Expected ERROR and above on the error writer, got %+v", errorsSink.Entries())
+	}
+
+	if _, err := New(WithLevelRouting(map[LogLevel]io.Writer{ERROR: nil})); err == nil {
+		t.Error("Expected an error for a nil routing writer")
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestErrorHandler(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	handler := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+
+	testLogger, err := New(WithOutput(failingWriter{}), WithErrorHandler(handler))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("lost", "user_id", "u-1")
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "disk full") {
+		t.Fatalf("Expected the write error to be reported, got %v", reported)
+	}
+
+	release := make(chan struct{})
+	blocked := writerFunc(func(p []byte) (int, error) {
+		<-release
+		return len(p), nil
+	})
+	asyncLogger, err := New(WithOutput(blocked), WithAsync(1, DropNewest), WithErrorHandler(handler))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	for range 3 {
+		asyncLogger.Info("queued")
+	}
+	close(release)
+	asyncLogger.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) < 2 || !errors.Is(reported[len(reported)-1], ErrAsyncOverflow) {
+		t.Errorf("Expected an async overflow to be reported, got %v", reported)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	var mu sync.Mutex
+	var writes []string
+	writer := writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		writes = append(writes, string(p))
+		return len(p), nil
+	})
+
+	testLogger, err := New(WithOutput(writer), WithLevel(INFO))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Batch(func(b *Batcher) {
+		b.Info("transfer started", "transfer_id", "t-1")
+		b.Debug("filtered")
+		b.Warn("login", "password", "hunter2")
+		b.Error("transfer failed")
+	})
+
+	if len(writes) != 1 {
+		t.Fatalf("Expected one write for the batch, got %d: %q", len(writes), writes)
+	}
+	lines := strings.Split(strings.TrimSpace(writes[0]), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "transfer started") || !strings.Contains(lines[2], "transfer failed") {
+		t.Errorf("Expected the enabled entries in order, got %q", lines)
+	}
+	if strings.Contains(writes[0], "hunter2") {
+		t.Errorf("Expected batched entries to be masked, got %s", writes[0])
+	}
+
+	sink := NewMemorySink()
+	sinkLogger, err := New(WithOutput(sink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				sinkLogger.Info("noise")
+			}
+		}()
+	}
+	for range 10 {
+		sinkLogger.Batch(func(b *Batcher) {
+			b.Info("audit begin")
+			b.Info("audit step")
+			b.Info("audit end")
+		})
+	}
+	wg.Wait()
+
+	entries := sink.Entries()
+	if len(entries) != 110 {
+		t.Fatalf("Expected 110 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Message != "audit begin" {
+			continue
+		}
+		if i+2 >= len(entries) || entries[i+1].Message != "audit step" || entries[i+2].Message != "audit end" {
+			t.Fatalf("Expected batch entries to be contiguous at %d", i)
+		}
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		sinkLogger.Batch(func(b *Batcher) {
+			b.Info("before panic")
+			panic("boom")
+		})
+	}()
+	if !sink.Contains(INFO, "before panic") {
+		t.Error("Expected entries logged before a panic to be written")
+	}
+}
+
+func TestTee(t *testing.T) {
+	jsonSink := NewMemorySink()
+	jsonLogger, err := New(WithOutput(jsonSink), WithLevel(INFO), WithCaller())
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	var console bytes.Buffer
+	consoleLogger, err := New(WithOutput(&console), WithFormat(FormatLogfmt), WithLevel(DEBUG), WithMaskString("[hidden]"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	logger := Tee(jsonLogger, nil, consoleLogger).WithFields(map[string]any{"service": "checkout"})
+	logger.Info("charged", "password", "hunter2", "order_id", "A-1")
+	logger.Debug("cache miss")
+	logger.InfoStructured("refunded", ZString("order_id", "A-2"))
+
+	entries := jsonSink.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected the info entries only in the JSON logger, got %d", len(entries))
+	}
+	if entries[0].Fields["password"] != defaultMaskString || entries[0].Fields["service"] != "checkout" {
+		t.Errorf("Expected masked fields with base fields, got %v", entries[0].Fields)
+	}
+	if want, _ := runnerCaller(0); entries[0].Fields["caller"] != want {
+		t.Errorf("Expected the caller to be the first frame outside the package, %s, got %v", want, entries[0].Fields["caller"])
+	}
+
+	output := console.String()
+	for _, want := range []string{"password=[hidden]", "service=checkout", "cache miss", "order_id=A-2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in console output, got %s", want, output)
+		}
+	}
+	if strings.Contains(output, "hunter2") {
+		t.Errorf("Expected the password to be masked, got %s", output)
+	}
+
+	if logger.GetLevel() != DEBUG || !logger.Enabled(DEBUG) {
+		t.Error("Expected the tee to accept the lowest child level")
+	}
+	logger.SetLevel(ERROR)
+	if logger.Enabled(WARN) || !logger.Enabled(ERROR) {
+		t.Error("Expected SetLevel to change every child")
+	}
+
+	failing, err := New(WithOutput(failingSyncer{}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	if err := Tee(jsonLogger, failing).Sync(); !errors.Is(err, errSyncFailed) {
+		t.Errorf("Expected the child Sync error, got %v", err)
+	}
+
+	// The copies share each original's state
+	originalSink := NewMemorySink()
+	original, err := New(WithOutput(originalSink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	shared := Tee(original)
+	original.SetLevel(ERROR)
+	if !shared.Enabled(INFO) {
+		t.Error("Expected SetLevel on an original to leave the tee's copy alone")
+	}
+	replacementSink := NewMemorySink()
+	if err := original.Reconfigure(WithOutput(replacementSink)); err != nil {
+		t.Fatalf("Unexpected error reconfiguring: %v", err)
+	}
+	shared.Error("after reconfigure")
+	if len(replacementSink.Entries()) != 1 || len(originalSink.Entries()) != 0 {
+		t.Errorf("Expected Reconfigure on an original to reach the tee, got %d and %d entries", len(replacementSink.Entries()), len(originalSink.Entries()))
+	}
+	original.SetLevel(WARN)
+	if shared.Enabled(INFO) || !shared.Enabled(WARN) {
+		t.Error("Expected SetLevel on a reconfigured original to reach the tee")
+	}
+	if err := shared.Close(); err != nil {
+		t.Fatalf("Unexpected error closing the tee: %v", err)
+	}
+	original.Error("after close")
+	if len(replacementSink.Entries()) != 1 {
+		t.Error("Expected closing the tee to close the original")
+	}
+}
+
+var errSyncFailed = errors.New("sync failed")
+
+type failingSyncer struct{}
+
+func (failingSyncer) Write(p []byte) (int, error) { return len(p), nil }
+func (failingSyncer) Sync() error                 { return errSyncFailed }
+
+func TestRingBufferSink(t *testing.T) {
+	ring := RingBufferSink(3)
+	testLogger, err := New(WithOutput(ring))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	for i := range 5 {
+		testLogger.Info("step", "n", i)
+	}
+
+	var dump bytes.Buffer
+	if err := ring.Dump(&dump); err != nil {
+		t.Fatalf("Unexpected dump error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(dump.String(), "\n"), "\n")
+	if ring.Len() != 3 || len(lines) != 3 {
+		t.Fatalf("Expected the last 3 lines, got %q", dump.String())
+	}
+	for i, want := range []string{`"n":2`, `"n":3`, `"n":4`} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("Expected %s in line %d, got %s", want, i, lines[i])
+		}
+	}
+
+	// Multi-line writes are split, and full slots are reused
+	line := []byte(`{"message":"alloc"}` + "\n")
+	ring.Write(append(append([]byte{}, line...), line...))
+	if allocs := testing.AllocsPerRun(100, func() { ring.Write(line) }); allocs != 0 {
+		t.Errorf("Expected writes to a full ring not to allocate, got %v", allocs)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				testLogger.Info("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+	if ring.Len() != 3 {
+		t.Errorf("Expected 3 retained lines, got %d", ring.Len())
+	}
+
+	ring.Reset()
+	if ring.Len() != 0 {
+		t.Errorf("Expected an empty ring after Reset, got %d lines", ring.Len())
+	}
+}
+
+func TestLogPanic(t *testing.T) {
+	var dump bytes.Buffer
+	original := panicDumpWriter
+	panicDumpWriter = &dump
+	defer func() { panicDumpWriter = original }()
+
+	ring := RingBufferSink(10)
+	sink := NewMemorySink()
+	testLogger, err := New(WithOutput(MultiWriter(ring, sink)))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	recovered := func() (value any) {
+		defer func() { value = recover() }()
+		defer LogPanic(testLogger, ring)
+		testLogger.Info("loading order")
+		panic("nil order")
+	}()
+
+	if recovered != "nil order" {
+		t.Errorf("Expected the panic to be re-raised, got %v", recovered)
+	}
+	entry, _ := sink.LastEntry()
+	if entry.Level != ERROR || entry.Fields["panic"] != "nil order" || entry.Fields["stacktrace"] == nil {
+		t.Errorf("Expected an error entry with the panic and stack trace, got %+v", entry)
+	}
+	if !strings.Contains(dump.String(), "loading order") || !strings.Contains(dump.String(), "panic recovered") {
+		t.Errorf("Expected the ring buffer to be dumped, got %s", dump.String())
+	}
+
+	func() {
+		defer LogPanic(testLogger, ring)
+	}()
+
+	// Values that cannot be compared are logged rather than crashing LogPanic
+	type uncomparable struct{ s []int }
+	sink.Reset()
+	recovered = func() (value any) {
+		defer func() { value = recover() }()
+		defer LogPanic(testLogger)
+		panic(uncomparable{s: []int{1}})
+	}()
+	if _, ok := recovered.(uncomparable); !ok {
+		t.Errorf("Expected the uncomparable value to be re-raised, got %v", recovered)
+	}
+	if !sink.Contains(ERROR, "panic recovered") {
+		t.Errorf("Expected the uncomparable panic to be logged")
+	}
+
+	type holder struct{ v any }
+	holderLogger, err := New(WithOutput(sink), WithPanicValue(func(string) any { return holder{v: []int{1}} }))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	recovered = func() (value any) {
+		defer func() { value = recover() }()
+		defer LogPanic(holderLogger)
+		holderLogger.Panic("invariant broken")
+		return nil
+	}()
+	if _, ok := recovered.(holder); !ok {
+		t.Errorf("Expected the panic value to be re-raised, got %v", recovered)
+	}
+}
+
+func TestGzipWriter(t *testing.T) {
+	var buf bytes.Buffer
+	sink, err := GzipWriter(&buf, GzipFlushInterval(time.Hour), GzipLevel(gzip.BestCompression))
+	if err != nil {
+		t.Fatalf("Unexpected error creating gzip writer: %v", err)
+	}
+	testLogger, err := New(WithOutput(sink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("first", "password", "hunter2")
+
+	// Sync flushes a decodable prefix before the stream is finished
+	if err := testLogger.Sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Expected a gzip header after Sync: %v", err)
+	}
+	if partial, _ := io.ReadAll(zr); !bytes.Contains(partial, []byte(`"message":"first"`)) {
+		t.Errorf("Expected the first line after Sync, got %q", partial)
+	}
+
+	testLogger.Info("second")
+	if err := testLogger.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+	zr, err = gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Invalid gzip stream: %v", err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("Expected a complete gzip stream after Close: %v", err)
+	}
+	if lines := strings.Count(string(plain), "\n"); lines != 2 || strings.Contains(string(plain), "hunter2") {
+		t.Errorf("Expected 2 masked lines, got %q", plain)
+	}
+	if _, err := sink.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected os.ErrClosed after Close, got %v", err)
+	}
+
+	if _, err := GzipWriter(io.Discard, GzipLevel(42)); err == nil {
+		t.Error("Expected an error for an invalid compression level")
+	}
+	if _, err := GzipWriter(io.Discard, GzipFlushInterval(-time.Second)); err == nil {
+		t.Error("Expected an error for a negative flush interval")
+	}
+}
+
+func TestGzipWriterFlushInterval(t *testing.T) {
+	var mu sync.Mutex
+	var compressed int
+	w := writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		compressed += len(p)
+		return len(p), nil
+	})
+	sink, err := GzipWriter(w, GzipFlushInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error creating gzip writer: %v", err)
+	}
+	defer sink.Close()
+
+	sink.Write([]byte(`{"message":"tick"}` + "\n"))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		n := compressed
+		mu.Unlock()
+		// The 10-byte gzip header is written with the first line
+		if n > 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the line to be flushed within the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type recordingProducer struct {
+	mu      sync.Mutex
+	batches [][]KafkaMessage
+	err     error
+}
+
+func (p *recordingProducer) Produce(topic string, messages []KafkaMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if topic != "logs" {
+		return errors.New("unexpected topic " + topic)
+	}
+	p.batches = append(p.batches, messages)
+	return p.err
+}
+
+func TestKafkaSink(t *testing.T) {
+	producer := &recordingProducer{}
+	sink, err := KafkaSink(producer, "logs", KafkaKeyField("tenant_id"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating sink: %v", err)
+	}
+	var reported []error
+	testLogger, err := New(WithOutput(sink), WithErrorHandler(func(err error) { reported = append(reported, err) }))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	testLogger.Info("created", "tenant_id", "acme")
+	testLogger.Info("no tenant")
+	if len(producer.batches) != 2 {
+		t.Fatalf("Expected one publish per line, got %d", len(producer.batches))
+	}
+	first := producer.batches[0][0]
+	if string(first.Key) != "acme" || !bytes.HasPrefix(first.Value, []byte("{")) || bytes.HasSuffix(first.Value, []byte("\n")) {
+		t.Errorf("Unexpected message: key %q value %q", first.Key, first.Value)
+	}
+	if producer.batches[1][0].Key != nil {
+		t.Errorf("Expected no key without the field, got %q", producer.batches[1][0].Key)
+	}
+
+	producer.err = errors.New("broker unavailable")
+	testLogger.Info("lost")
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "broker unavailable") {
+		t.Errorf("Expected the publish error to reach the error handler, got %v", reported)
+	}
+
+	batched := &recordingProducer{}
+	batchSink, err := KafkaSink(batched, "logs", KafkaBatch(3, time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error creating sink: %v", err)
+	}
+	batchLogger, err := New(WithOutput(batchSink))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	for range 4 {
+		batchLogger.Info("batched")
+	}
+	if len(batched.batches) != 1 || len(batched.batches[0]) != 3 {
+		t.Fatalf("Expected one full batch, got %v", batched.batches)
+	}
+	if err := batchLogger.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+	if len(batched.batches) != 2 || len(batched.batches[1]) != 1 {
+		t.Errorf("Expected Close to publish the partial batch, got %d batches", len(batched.batches))
+	}
+
+	// A failed linger publish is reported by Sync, not by the next queued line
+	lingering := &recordingProducer{err: errors.New("broker unavailable")}
+	lingerSink, err := KafkaSink(lingering, "logs", KafkaBatch(10, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error creating sink: %v", err)
+	}
+	defer lingerSink.Close()
+	if _, err := lingerSink.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Unexpected error queueing a line: %v", err)
+	}
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		lingering.mu.Lock()
+		published := len(lingering.batches)
+		lingering.mu.Unlock()
+		if published > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the linger timer to publish the batch")
+		}
+	}
+	if n, err := lingerSink.Write([]byte("second\n")); n != 7 || err != nil {
+		t.Errorf("Expected the queued line to be accepted, got %d, %v", n, err)
+	}
+	if err := lingerSink.Sync(); err == nil || !strings.Contains(err.Error(), "broker unavailable") {
+		t.Errorf("Expected Sync to report the linger failure, got %v", err)
+	}
+
+	if _, err := KafkaSink(batched, "logs", KafkaBatch(10, 0)); err == nil {
+		t.Error("Expected an error for batching without linger")
+	}
+}
+
+// blockingWriter blocks every write until release is closed
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	lines   int
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines++
+	return len(p), nil
+}
+
+func TestWriteTimeout(t *testing.T) {
+	writer := &blockingWriter{release: make(chan struct{})}
+	var reported atomic.Int32
+	testLogger, err := New(
+		WithOutput(writer),
+		WithWriteTimeout(20*time.Millisecond),
+		WithErrorHandler(func(err error) {
+			if errors.Is(err, ErrWriteTimeout) {
+				reported.Add(1)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+
+	start := time.Now()
+	testLogger.Info("first")
+	testLogger.Info("second")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Expected logging not to block on a hung writer, took %v", elapsed)
+	}
+	if got := testLogger.Stats().TimedOut; got != 2 || reported.Load() != 2 {
+		t.Errorf("Expected 2 timed-out lines reported, got %d counted and %d reported", got, reported.Load())
+	}
+
+	// Once the stuck write returns, lines are written again
+	close(writer.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		writer.mu.Lock()
+		lines := writer.lines
+		writer.mu.Unlock()
+		if lines == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the abandoned write to finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for testLogger.writeGuard.blocked.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	testLogger.Info("third")
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if writer.lines != 2 || testLogger.Stats().TimedOut != 2 {
+		t.Errorf("Expected the next line to be written, got %d lines and %d timeouts", writer.lines, testLogger.Stats().TimedOut)
+	}
+
+	if _, err := New(WithWriteTimeout(0)); err == nil {
+		t.Error("Expected a zero write timeout to be rejected")
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	var buf bytes.Buffer
+	testLogger, err := New(WithOutput(&buf), WithComponent("billing"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating logger: %v", err)
+	}
+	testLogger.Info("charge created", "amount", 12.5, "password", "hunter2")
+	testLogger.ErrorStructured("charge failed", ZString("reason", "declined"))
+	buf.WriteString("\nnot json\n")
+	buf.WriteString(`{"level":"warn","message":"retrying","region":"eu","attempt":2}` + "\n")
+
+	var entries []Entry
+	var errs []error
+	for entry, err := range ParseReader(&buf) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "line 4") {
+		t.Errorf("Expected the malformed line to be reported with its number, got %v", errs)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Level != INFO || entries[0].Message != "charge created" || entries[0].Fields["amount"] != 12.5 || entries[0].Fields["password"] != defaultMaskString {
+		t.Errorf("Expected the info entry with its fields, got %+v", entries[0])
+	}
+	if entries[1].Level != ERROR || entries[1].Fields["reason"] != "declined" {
+		t.Errorf("Expected the structured entry with top-level fields, got %+v", entries[1])
+	}
+	if _, ok := entries[1].Fields["component"]; ok {
+		t.Errorf("Expected metadata keys not to be fields, got %+v", entries[1])
+	}
+	if entries[2].Level != WARN || entries[2].Fields["region"] != "eu" || entries[2].Fields["attempt"] != float64(2) {
+		t.Errorf("Expected unknown keys to be kept as fields, got %+v", entries[2])
+	}
+
+	if _, err := ParseEntry([]byte(`{"message":"no level"}`)); err == nil {
+		t.Error("Expected an entry without a level to be rejected")
+	}
+}
@@ -0,0 +1,185 @@
+package emit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that writes records through a Logger, so
+// log/slog call sites get the logger's masking, format and sinks
+type SlogHandler struct {
+	logger *Logger
+	fields map[string]any // attrs added with WithAttrs, nested by group
+	groups []string       // open groups new attrs are added under
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// NewSlogHandler returns a slog.Handler backed by logger (the default logger if nil):
+//
+//	slog.SetDefault(slog.New(emit.NewSlogHandler(logger)))
+//
+// Attribute groups become nested field maps, and entries carry the record's
+// time, or no timestamp when it is zero.
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	if logger == nil {
+		logger = Default()
+	}
+	return &SlogHandler{logger: logger}
+}
+
+// slogLevel maps a slog level to the nearest emit level at or below it
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARN
+	default:
+		return ERROR
+	}
+}
+
+// Enabled reports whether the logger accepts records at level
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Enabled(slogLevel(level))
+}
+
+// Handle converts the record's attrs to fields and logs it
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := copyFieldTree(h.fields)
+	if r.NumAttrs() > 0 {
+		if fields == nil {
+			fields = make(map[string]any, r.NumAttrs())
+		}
+		target := groupMap(fields, h.groups)
+		r.Attrs(func(a slog.Attr) bool {
+			addSlogAttr(target, a)
+			return true
+		})
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// The record was made by slog's caller, at its own time, which is zero
+	// when the caller wants no timestamp
+	logger := h.logger.withRecordTime(r.Time)
+	if r.PC != 0 {
+		logger = logger.withCallerPC(r.PC)
+	}
+	logger.logContext(ctx, slogLevel(r.Level), r.Message, fields)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := copyFieldTree(h.fields)
+	if fields == nil {
+		fields = make(map[string]any, len(attrs))
+	}
+	target := groupMap(fields, h.groups)
+	for _, a := range attrs {
+		addSlogAttr(target, a)
+	}
+	return &SlogHandler{logger: h.logger, fields: fields, groups: h.groups}
+}
+
+// WithGroup returns a handler that nests subsequent attrs under name
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	return &SlogHandler{logger: h.logger, fields: h.fields, groups: append(groups, name)}
+}
+
+// addSlogAttr stores a resolved attr in fields, expanding groups into maps
+func addSlogAttr(fields map[string]any, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		target := fields
+		if a.Key != "" {
+			nested, ok := fields[a.Key].(map[string]any)
+			if !ok {
+				nested = make(map[string]any, len(attrs))
+				fields[a.Key] = nested
+			}
+			target = nested
+		}
+		for _, ga := range attrs {
+			addSlogAttr(target, ga)
+		}
+		return
+	}
+
+	if a.Key == "" {
+		return
+	}
+	fields[a.Key] = slogValue(a.Value)
+}
+
+// slogValue converts a resolved non-group value to a plain Go value
+func slogValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration()
+	case slog.KindTime:
+		return v.Time()
+	default:
+		return v.Any()
+	}
+}
+
+// groupMap returns the nested map for the group path, creating it as needed
+func groupMap(fields map[string]any, groups []string) map[string]any {
+	for _, g := range groups {
+		nested, ok := fields[g].(map[string]any)
+		if !ok {
+			nested = make(map[string]any)
+			fields[g] = nested
+		}
+		fields = nested
+	}
+	return fields
+}
+
+// copyFieldTree copies a map and the nested maps built from groups so
+// handlers derived from the same parent never share them
+func copyFieldTree(fields map[string]any) map[string]any {
+	if fields == nil {
+		return nil
+	}
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if nested, ok := v.(map[string]any); ok {
+			v = copyFieldTree(nested)
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package emit
+
+import (
+	"errors"
+	"maps"
+)
+
+// sourceKey is the field carrying the library name set with WithSource
+const sourceKey = "source"
+
+// WithSource tags every entry with source set to name, for libraries that
+// log through emit:
+//
+//	// In package mylib
+//	logger, _ := emit.New(emit.WithSource("mylib"))
+//
+// Operators can then filter a library's entries without knowing its logger
+// names. Unlike Named, which structures an application's own loggers and
+// nests, the source identifies who wrote the entry: children created with
+// WithFields or Named keep it, and a "source" field passed at the call site
+// or in WithFields is replaced. Application code should use Named and
+// WithComponent instead, leaving source to libraries.
+func WithSource(name string) Option {
+	return func(l *Logger) error {
+		if name == "" {
+			return errors.New("emit: source must not be empty")
+		}
+		l.source = name
+		return nil
+	}
+}
+
+// withSource sets the source field over any field of the same name
+func (l *Logger) withSource(fields map[string]any) map[string]any {
+	if l.source == "" {
+		return fields
+	}
+	withSource := make(map[string]any, len(fields)+1)
+	maps.Copy(withSource, fields)
+	withSource[sourceKey] = l.source
+	return withSource
+}
@@ -0,0 +1,124 @@
+package emit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Field keys written by spans
+const (
+	spanKey       = "span"
+	spanIDKey     = "span_id"
+	durationMsKey = "duration_ms"
+)
+
+// Span times an operation and logs its start and finish with a shared
+// span_id, as returned by Logger.StartSpan
+type Span struct {
+	logger *Logger
+	name   string
+	id     string
+	start  time.Time
+
+	mu       sync.Mutex
+	fields   map[string]any
+	err      error
+	finished bool
+}
+
+// StartSpan logs a "span started" entry and returns a span whose Finish logs
+// "span finished" with the elapsed time as duration_ms:
+//
+//	span := logger.StartSpan("db.query", "table", "orders")
+//	defer span.Finish()
+//	rows, err := db.Query(q)
+//	if err != nil {
+//		span.Fail(err)
+//	}
+//	span.SetField("rows", len(rows))
+//
+// Both entries carry the span name as "span", a random "span_id" and the
+// given fields, which take the same forms as Logger.Info. This is timing,
+// not distributed tracing: spans do not nest or propagate.
+func (l *Logger) StartSpan(name string, fields ...any) *Span {
+	span := &Span{
+		logger: l,
+		name:   name,
+		id:     newSpanID(),
+		start:  l.now(),
+		fields: maps.Clone(collectFields(fields...)),
+	}
+	if l.Enabled(INFO) {
+		l.logContext(context.Background(), INFO, "span started", span.entryFields(nil)...)
+	}
+	return span
+}
+
+// SetField adds a field to the finish entry
+func (s *Span) SetField(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fields == nil {
+		s.fields = make(map[string]any)
+	}
+	s.fields[key] = value
+}
+
+// Fail marks the span as failed, so Finish logs at error level with err
+func (s *Span) Fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// ID returns the span's span_id
+func (s *Span) ID() string {
+	return s.id
+}
+
+// Finish logs the "span finished" entry with duration_ms. Only the first
+// call logs.
+func (s *Span) Finish() {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	err := s.err
+	fields := maps.Clone(s.fields)
+	s.mu.Unlock()
+
+	level := INFO
+	if err != nil {
+		level = ERROR
+	}
+	if !s.logger.Enabled(level) {
+		return
+	}
+	duration := float64(s.logger.now().Sub(s.start)) / float64(time.Millisecond)
+	extra := []any{durationMsKey, duration}
+	if err != nil {
+		extra = append(extra, Err(err))
+	}
+	s.logger.logContext(context.Background(), level, "span finished", s.entryFields(fields, extra...)...)
+}
+
+// entryFields returns the span's identity and fields, followed by extra
+func (s *Span) entryFields(fields map[string]any, extra ...any) []any {
+	if fields == nil {
+		fields = s.fields
+	}
+	return append([]any{Fields(fields), spanKey, s.name, spanIDKey, s.id}, extra...)
+}
+
+// newSpanID returns 8 random bytes as hex
+func newSpanID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,199 @@
+package emit
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultStackTraceDepth is the maximum number of frames captured by default
+const defaultStackTraceDepth = 32
+
+// stackTraceKey is the field name used for captured stack traces
+const stackTraceKey = "stacktrace"
+
+// StackFrame describes a single frame of a captured stack trace
+type StackFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// StackTrace is a list of frames, innermost first. Stack traces are never masked.
+type StackTrace []StackFrame
+
+// emitPackagePrefix identifies frames inside this package so they can be skipped
+var emitPackagePrefix = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(newLogger).Pointer()).Name()
+	return name[:strings.LastIndex(name, ".")+1]
+}()
+
+// ErrorZField represents an error field that carries its stack trace when available
+type ErrorZField struct {
+	Key string
+	Err error
+}
+
+func (f ErrorZField) WriteToEncoder(enc *ZeroAllocEncoder) {
+	if f.Err == nil {
+		enc.writeStringField(f.Key, "")
+		return
+	}
+	enc.writeStringField(f.Key, f.Err.Error())
+}
+
+func (f ErrorZField) IsSensitive() bool { return false }
+func (f ErrorZField) IsPII() bool       { return false }
+
+// Err creates an error field named "error". If the error (or any error it wraps)
+// exposes a StackTrace() method, such as errors created with pkg/errors, its
+// frames are logged in a "stacktrace" field.
+func Err(err error) ErrorZField {
+	return ErrorZField{Key: "error", Err: err}
+}
+
+// NamedErr creates an error field with a custom key
+func NamedErr(key string, err error) ErrorZField {
+	return ErrorZField{Key: key, Err: err}
+}
+
+// WithStackTrace captures the caller's stack on entries at or above minLevel
+// that do not already carry a stack trace from their error
+func WithStackTrace(minLevel LogLevel) Option {
+	return func(l *Logger) error {
+		l.stackTraceEnabled = true
+		l.stackTraceLevel = minLevel
+		return nil
+	}
+}
+
+// WithStackTraceDepth limits the number of frames kept in stack traces
+func WithStackTraceDepth(depth int) Option {
+	return func(l *Logger) error {
+		l.stackTraceDepth = depth
+		return nil
+	}
+}
+
+// maxStackDepth returns the configured stack depth or the default
+func (l *Logger) maxStackDepth() int {
+	if l.stackTraceDepth > 0 {
+		return l.stackTraceDepth
+	}
+	return defaultStackTraceDepth
+}
+
+// addStackTrace captures the current stack when the level requires it
+func (l *Logger) addStackTrace(level LogLevel, fields map[string]any) map[string]any {
+	if len(fields) > 0 {
+		if trace, exists := fields[stackTraceKey]; exists {
+			// Traces extracted from errors are truncated to the logger's depth
+			if trace, ok := trace.(StackTrace); ok && len(trace) > l.maxStackDepth() {
+				truncated := maps.Clone(fields)
+				truncated[stackTraceKey] = trace[:l.maxStackDepth()]
+				return truncated
+			}
+			return fields
+		}
+	}
+
+	if !l.stackTraceEnabled || level < l.stackTraceLevel {
+		return fields
+	}
+
+	withStack := make(map[string]any, len(fields)+1)
+	maps.Copy(withStack, fields)
+	withStack[stackTraceKey] = captureStackTrace(l.maxStackDepth())
+	return withStack
+}
+
+// captureStackTrace records the current goroutine's stack, skipping frames
+// inside this package so the trace starts at the logging call site
+func captureStackTrace(maxDepth int) StackTrace {
+	pcs := make([]uintptr, maxDepth+16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	trace := make(StackTrace, 0, maxDepth)
+	skipping := true
+	for {
+		frame, more := frames.Next()
+		if skipping && strings.HasPrefix(frame.Function, emitPackagePrefix) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skipping = false
+
+		trace = append(trace, StackFrame{File: frame.File, Line: frame.Line, Function: frame.Function})
+		if len(trace) >= maxDepth || !more {
+			break
+		}
+	}
+	return trace
+}
+
+// errorStackTrace extracts frames from the first error in the chain that has a
+// StackTrace() method returning program counters (pkg/errors style)
+func errorStackTrace(err error, maxDepth int) StackTrace {
+	for err != nil {
+		if pcs := stackTracePCs(err); len(pcs) > 0 {
+			if len(pcs) > maxDepth {
+				pcs = pcs[:maxDepth]
+			}
+			frames := runtime.CallersFrames(pcs)
+			trace := make(StackTrace, 0, len(pcs))
+			for {
+				frame, more := frames.Next()
+				trace = append(trace, StackFrame{File: frame.File, Line: frame.Line, Function: frame.Function})
+				if !more || len(trace) >= maxDepth {
+					break
+				}
+			}
+			return trace
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// stackTracePCs calls err.StackTrace() via reflection so emit does not depend
+// on any particular errors package; the result must be a slice of uintptr-like frames
+func stackTracePCs(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	result := method.Call(nil)[0]
+	if result.Kind() != reflect.Slice || result.Type().Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	pcs := make([]uintptr, result.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(result.Index(i).Uint())
+	}
+	return pcs
+}
+
+// String formats the trace like a Go panic: the function on one line and its
+// file:line indented below it
+func (s StackTrace) String() string {
+	var b strings.Builder
+	for i, frame := range s {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package emit
+
+import "sync/atomic"
+
+// Levels in [-statsLevelOffset, statsLevelSlots-statsLevelOffset) are counted
+// separately, which covers the predefined levels and custom levels around
+// them; levels outside are counted in the nearest slot
+const (
+	statsLevelSlots  = 64
+	statsLevelOffset = 16
+)
+
+// loggerStats holds the counters behind Stats. It is shared by a logger and
+// its children, and every update is a single atomic add.
+type loggerStats struct {
+	emitted      [statsLevelSlots]atomic.Uint64
+	sampled      atomic.Uint64
+	deduplicated atomic.Uint64
+	masked       atomic.Uint64
+}
+
+// Stats is a snapshot of a logger's counters, for exporting to a metrics
+// system without tying the logger to one
+type Stats struct {
+	Emitted      map[LogLevel]uint64 // Entries written or queued, by level
+	Sampled      uint64              // Entries dropped by sampling
+	Deduplicated uint64              // Repeats folded into a "suppressed" count
+	AsyncDropped uint64              // Entries discarded by the async overflow policy
+	TimedOut     uint64              // Entries dropped by WithWriteTimeout
+	MaskedFields uint64              // Fields masked or dropped by masking
+}
+
+// Stats returns the logger's counters. Children created with WithFields or
+// Named share their parent's counters.
+func (l *Logger) Stats() Stats {
+	stats := Stats{Emitted: make(map[LogLevel]uint64)}
+	if l.stats != nil {
+		for i := range l.stats.emitted {
+			if n := l.stats.emitted[i].Load(); n > 0 {
+				stats.Emitted[LogLevel(i-statsLevelOffset)] = n
+			}
+		}
+		stats.Sampled = l.stats.sampled.Load()
+		stats.Deduplicated = l.stats.deduplicated.Load()
+		stats.MaskedFields = l.stats.masked.Load()
+	}
+	if l.async != nil {
+		stats.AsyncDropped = l.async.dropped.Load()
+	}
+	if l.writeGuard != nil {
+		stats.TimedOut = l.writeGuard.dropped.Load()
+	}
+	return stats
+}
+
+// GetStats returns the default logger's counters
+func GetStats() Stats {
+	logger := Default()
+	if logger == nil {
+		return Stats{Emitted: make(map[LogLevel]uint64)}
+	}
+	return logger.Stats()
+}
+
+// countEmitted records an entry handed to the writer
+func (s *loggerStats) countEmitted(level LogLevel) {
+	if s == nil {
+		return
+	}
+	slot := min(max(int(level)+statsLevelOffset, 0), statsLevelSlots-1)
+	s.emitted[slot].Add(1)
+}
+
+// countMasked records a masked field
+func (s *loggerStats) countMasked() {
+	if s != nil {
+		s.masked.Add(1)
+	}
+}
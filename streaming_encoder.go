@@ -0,0 +1,248 @@
+package emit
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// WithStreamingEncoder writes JSON entries field by field into a pooled buffer,
+// masking each key as it is encountered instead of building a masked copy of
+// the field map first. Field order within "fields" follows map iteration order.
+func WithStreamingEncoder() Option {
+	return func(l *Logger) error {
+		l.streamingEncoder = true
+		return nil
+	}
+}
+
+// logJSONStreaming writes a JSON formatted log entry without intermediate maps
+func (l *Logger) logJSONStreaming(level LogLevel, message string, fields map[string]any) {
+	buf := getLineBuffer()
+	defer putLineBuffer(buf)
+
+	buf.WriteByte('{')
+	if !l.omitsTimestamp() {
+		l.writeJSONTimestamp(buf)
+		buf.WriteByte(',')
+	}
+	buf.WriteString(`"level":`)
+	writeJSONString(buf, l.levelName(level), l.escapeHTML)
+	buf.WriteString(`,"message":`)
+	writeJSONString(buf, message, l.escapeHTML)
+
+	if l.component != "" {
+		buf.WriteString(`,"component":`)
+		writeJSONString(buf, l.component, l.escapeHTML)
+	}
+
+	if l.version != "" {
+		buf.WriteString(`,"version":`)
+		writeJSONString(buf, l.version, l.escapeHTML)
+	}
+
+	if l.showCaller {
+		if pc, file, line, ok := runtime.Caller(callerSkip + l.extraCallerSkip); ok {
+			buf.WriteString(`,"file":`)
+			writeJSONString(buf, file, l.escapeHTML)
+			buf.WriteString(`,"line":`)
+			buf.WriteString(strconv.Itoa(line))
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				buf.WriteString(`,"function":`)
+				writeJSONString(buf, fn.Name(), l.escapeHTML)
+			}
+		}
+	}
+
+	fields = l.applyMaskPaths(fields)
+	if len(l.defaultFields) > 0 {
+		buf.WriteString(`,"fields":`)
+		l.writeWithDefaultFields(buf, fields)
+	} else if len(fields) > 0 {
+		buf.WriteString(`,"fields":`)
+		l.writeMaskedFields(buf, fields, nil)
+	}
+
+	buf.WriteString("}\n")
+
+	l.writeLine(level, buf.Bytes())
+}
+
+// writeMaskedFields encodes a field map as a JSON object, masking each key inline
+func (l *Logger) writeMaskedFields(buf *bytes.Buffer, fields map[string]any, visited map[maskVisitKey]bool) map[maskVisitKey]bool {
+	masking := !l.skipsMasking(fields)
+
+	buf.WriteByte('{')
+	first := true
+	for key, value := range l.orderedFields(fields) {
+		var written bool
+		visited, written = l.writeMaskedMember(buf, key, value, !first, masking, visited)
+		if written {
+			first = false
+		}
+	}
+	buf.WriteByte('}')
+
+	return visited
+}
+
+// writeMaskedMember encodes one "key":value member, masking the value and
+// preceding it with a comma when sep is set. Dropped fields write nothing.
+func (l *Logger) writeMaskedMember(buf *bytes.Buffer, key string, value any, sep, masking bool, visited map[maskVisitKey]bool) (map[maskVisitKey]bool, bool) {
+	writeKey := func() {
+		if sep {
+			buf.WriteByte(',')
+		}
+		writeJSONString(buf, key, l.escapeHTML)
+		buf.WriteByte(':')
+	}
+
+	if !masking {
+		writeKey()
+		l.writeJSONValue(buf, value)
+		return visited, true
+	}
+
+	if masked, final := l.maskFieldValue(key, value); final {
+		if _, dropped := masked.(droppedField); dropped {
+			return visited, false
+		}
+		writeKey()
+		l.writeJSONValue(buf, masked)
+		return visited, true
+	}
+	writeKey()
+	return l.writeMaskedValue(buf, value, visited), true
+}
+
+// writeMaskedValue encodes a value whose key was not masked, descending into
+// nested maps and slices so their fields are masked too
+func (l *Logger) writeMaskedValue(buf *bytes.Buffer, value any, visited map[maskVisitKey]bool) map[maskVisitKey]bool {
+	var visitKey maskVisitKey
+
+	switch v := value.(type) {
+	case map[string]any:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer()}
+	case Fields:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer()}
+	case []map[string]any:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer(), length: len(v)}
+	case []any:
+		visitKey = maskVisitKey{ptr: reflect.ValueOf(v).Pointer(), length: len(v)}
+	case SecretValue, PIIValue:
+		l.writeJSONValue(buf, l.maskMarkedElement(value))
+		return visited
+	default:
+		fields, ptr, ok := l.structAsMap(value)
+		if !ok {
+			l.writeJSONValue(buf, value)
+			return visited
+		}
+		if ptr == 0 {
+			return l.writeMaskedFields(buf, fields, visited)
+		}
+		visitKey = maskVisitKey{ptr: ptr}
+		value = fields
+	}
+
+	if visited == nil {
+		visited = make(map[maskVisitKey]bool, 4)
+	}
+	if visited[visitKey] {
+		writeJSONString(buf, circularReferenceMarker, l.escapeHTML)
+		return visited
+	}
+	if len(visited) >= l.nestingLimit() {
+		writeJSONString(buf, maxDepthMarker, l.escapeHTML)
+		return visited
+	}
+	visited[visitKey] = true
+	defer delete(visited, visitKey)
+
+	switch v := value.(type) {
+	case map[string]any:
+		return l.writeMaskedFields(buf, v, visited)
+	case Fields:
+		return l.writeMaskedFields(buf, v, visited)
+	case []map[string]any:
+		buf.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			visited = l.writeMaskedValue(buf, element, visited)
+		}
+		buf.WriteByte(']')
+	default:
+		buf.WriteByte('[')
+		for i, element := range value.([]any) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			visited = l.writeMaskedValue(buf, element, visited)
+		}
+		buf.WriteByte(']')
+	}
+
+	return visited
+}
+
+// writeJSONValue encodes common value types directly and falls back to
+// encoding/json for everything else
+func (l *Logger) writeJSONValue(buf *bytes.Buffer, value any) {
+	var scratch [64]byte
+
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeJSONString(buf, v, l.escapeHTML)
+	case bool:
+		buf.Write(strconv.AppendBool(scratch[:0], v))
+	case int:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int8:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int16:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int32:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int64:
+		buf.Write(strconv.AppendInt(scratch[:0], v, 10))
+	case uint:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint8:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint16:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint32:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint64:
+		buf.Write(strconv.AppendUint(scratch[:0], v, 10))
+	case float32:
+		buf.Write(l.floatFormat.append(scratch[:0], float64(v), 32))
+	case float64:
+		buf.Write(l.floatFormat.append(scratch[:0], v, 64))
+	case time.Time:
+		writeJSONString(buf, l.formatTime(v), l.escapeHTML)
+	case time.Duration:
+		if l.durationFormat == DurationString {
+			writeJSONString(buf, v.String(), l.escapeHTML)
+		} else {
+			buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+		}
+	default:
+		if text, ok := textValue(v); ok {
+			writeJSONString(buf, text, l.escapeHTML)
+			return
+		}
+		// Encode writes nothing on error and ends with a newline on success
+		if err := l.encodeJSON(buf, v); err != nil {
+			writeJSONString(buf, marshalErrorPlaceholder(v, err), l.escapeHTML)
+			return
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+}
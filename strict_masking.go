@@ -0,0 +1,145 @@
+package emit
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// WithStrictMasking verifies every entry after masking and panics if a field
+// whose name is detected as sensitive or PII would be written with its
+// original value, for example because SHOW_SENSITIVE was left on or a custom
+// mask function returned its input. Default fields are verified when the
+// logger is configured and New or Configure returns an error instead.
+// Exempt fields (AddMaskExemption, WithNeverMask) are allowed. The check
+// masks each entry twice, so it is meant for tests and CI rather than
+// production.
+func WithStrictMasking() Option {
+	return func(l *Logger) error {
+		l.strictMasking = true
+		return nil
+	}
+}
+
+// checkStrictMasking returns an error naming the first detected field that
+// masking left unchanged
+func (l *Logger) checkStrictMasking(fields map[string]any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// Mask the way the entry will be, without counting or reporting it twice
+	quiet := *l
+	quiet.maskObserver = nil
+	quiet.stats = nil
+	masked := quiet.maskFields(fields)
+
+	// Detect names as if masking were on, whatever the configured modes
+	detector := *l
+	detector.sensitiveMode = MASK_SENSITIVE
+	detector.piiMode = MASK_PII
+
+	if path, ok := detector.findUnmasked(fields, masked, "", 0); ok {
+		return fmt.Errorf("emit: strict masking: field %q is not masked", path)
+	}
+	return nil
+}
+
+// findUnmasked walks the original and masked maps side by side
+func (l *Logger) findUnmasked(original, masked map[string]any, prefix string, depth int) (string, bool) {
+	if depth > jsonSafeMaxDepth {
+		return "", false
+	}
+	for key, value := range original {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		maskedValue, written := masked[key]
+		if !written {
+			continue
+		}
+		if l.categoryFor(key) != nil || l.isSensitiveFieldFast(key) || l.isPIIFieldFast(key) {
+			if !valueMasked(value, maskedValue) {
+				return path, true
+			}
+			continue
+		}
+
+		if path, ok := l.findUnmaskedNested(value, maskedValue, path, depth+1); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// findUnmaskedNested walks a nested map, or the elements of a slice, and its
+// masked counterpart side by side
+func (l *Logger) findUnmaskedNested(original, masked any, path string, depth int) (string, bool) {
+	if nested, ok := asFieldMap(original); ok {
+		if maskedNested, ok := asFieldMap(masked); ok {
+			return l.findUnmasked(nested, maskedNested, path, depth)
+		}
+		return "", false
+	}
+
+	elements, ok := asFieldSlice(original)
+	maskedElements, maskedOK := asFieldSlice(masked)
+	if !ok || !maskedOK || len(elements) != len(maskedElements) || depth > jsonSafeMaxDepth {
+		return "", false
+	}
+	for i, element := range elements {
+		if path, ok := l.findUnmaskedNested(element, maskedElements[i], path+"["+strconv.Itoa(i)+"]", depth+1); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// asFieldMap returns value as a field map when it is one
+func asFieldMap(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case Fields:
+		return v, true
+	}
+	return nil, false
+}
+
+// asFieldSlice returns the elements of a slice masking walks into
+func asFieldSlice(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case []map[string]any:
+		elements := make([]any, len(v))
+		for i, element := range v {
+			elements[i] = element
+		}
+		return elements, true
+	}
+	return nil, false
+}
+
+// valueMasked reports whether masking replaced original
+func valueMasked(original, masked any) bool {
+	if s, ok := masked.(string); ok {
+		if o, isString := original.(string); isString {
+			return s != o
+		}
+		// Errors and Stringers are written as their text, which is not masking
+		text, isText := textValue(original)
+		return !isText || s != text
+	}
+
+	ot, mt := reflect.TypeOf(original), reflect.TypeOf(masked)
+	if ot != mt {
+		return true
+	}
+	if mt == nil || !mt.Comparable() {
+		return false
+	}
+	return original != masked
+}
@@ -0,0 +1,152 @@
+package emit
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// WithStructMasking masks struct field values, which are otherwise encoded
+// as-is. Structs and pointers to structs are converted to maps while the
+// entry is encoded and their fields masked like map keys. The `log` struct
+// tag controls individual fields:
+//
+//	type User struct {
+//		Name   string
+//		Token  string `log:"mask"` // always masked as sensitive
+//		Secret string `log:"-"`    // never written
+//	}
+//
+// Untagged fields are detected by name (json tag name if set, otherwise the
+// Go field name). Types implementing json.Marshaler, encoding.TextMarshaler
+// or fmt.Stringer, such as time.Time, keep their own encoding. Reflection is
+// costly, so this is opt-in.
+func WithStructMasking() Option {
+	return func(l *Logger) error {
+		l.structMasking = true
+		return nil
+	}
+}
+
+// taggedMask wraps a struct field tagged `log:"mask"` so maskFieldValue masks
+// it regardless of its name
+type taggedMask struct {
+	value any
+}
+
+// structFieldInfo describes one encodable struct field
+type structFieldInfo struct {
+	index []int
+	name  string
+	mask  bool
+}
+
+// structFieldCache holds the parsed fields of each struct type
+var structFieldCache sync.Map // map[reflect.Type][]structFieldInfo
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	errorType         = reflect.TypeFor[error]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+)
+
+// structAsMap converts a struct or non-nil struct pointer to a field map when
+// struct masking is enabled. ptr is the pointer address, zero for struct
+// values, so pointer cycles can be detected.
+func (l *Logger) structAsMap(value any) (fields map[string]any, ptr uintptr, ok bool) {
+	if !l.structMasking || value == nil {
+		return nil, 0, false
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return nil, 0, false
+		}
+		ptr = rv.Pointer()
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || keepsOwnEncoding(rv.Type()) {
+		return nil, 0, false
+	}
+
+	infos := structFields(rv.Type())
+	fields = make(map[string]any, len(infos))
+	for _, info := range infos {
+		fv, err := rv.FieldByIndexErr(info.index)
+		if err != nil {
+			// Field inside a nil embedded pointer
+			continue
+		}
+		if info.mask {
+			fields[info.name] = taggedMask{value: fv.Interface()}
+		} else {
+			fields[info.name] = fv.Interface()
+		}
+	}
+	return fields, ptr, true
+}
+
+// keepsOwnEncoding reports whether a struct type defines its own encoding
+func keepsOwnEncoding(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	for _, iface := range []reflect.Type{jsonMarshalerType, textMarshalerType, errorType, stringerType} {
+		if t.Implements(iface) || pt.Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields returns the cached field list of a struct type
+func structFields(t reflect.Type) []structFieldInfo {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]structFieldInfo)
+	}
+	infos := appendStructFields(nil, t, nil)
+	structFieldCache.Store(t, infos)
+	return infos
+}
+
+// appendStructFields collects exported fields, flattening embedded structs
+// the way encoding/json does
+func appendStructFields(infos []structFieldInfo, t reflect.Type, index []int) []structFieldInfo {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		tag := field.Tag.Get("log")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				infos = appendStructFields(infos, embedded, fieldIndex)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		infos = append(infos, structFieldInfo{index: fieldIndex, name: name, mask: tag == "mask"})
+	}
+	return infos
+}
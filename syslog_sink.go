@@ -0,0 +1,271 @@
+package emit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Syslog facility used unless SyslogFacility is given (LOG_USER)
+const defaultSyslogFacility = 1
+
+// localSyslogPaths are the sockets probed when SyslogSink is given no network
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Reconnect backoff bounds after a failed dial or write
+const (
+	syslogMinBackoff = 100 * time.Millisecond
+	syslogMaxBackoff = 30 * time.Second
+)
+
+// RFC 5424 severities
+const (
+	syslogSeverityCrit    = 2
+	syslogSeverityErr     = 3
+	syslogSeverityWarning = 4
+	syslogSeverityNotice  = 5
+	syslogSeverityInfo    = 6
+	syslogSeverityDebug   = 7
+)
+
+// SyslogOption configures a SyslogWriter created with SyslogSink
+type SyslogOption func(*SyslogWriter)
+
+// SyslogWriter sends each log line as an RFC 5424 message whose payload is
+// the encoded entry. It is safe for concurrent use.
+type SyslogWriter struct {
+	mu       sync.Mutex
+	network  string
+	addr     string
+	tag      string
+	hostname string
+	facility int
+	fallback io.Writer
+
+	conn        net.Conn
+	backoff     time.Duration
+	nextAttempt time.Time
+	dialing     bool
+	closed      bool
+}
+
+// SyslogSink connects to a syslog server ("udp", "tcp" or "unix"/"unixgram";
+// an empty network uses the local syslog socket). Levels map to RFC 5424
+// severities: error→err, warn→warning, info→info, debug→debug. Lost
+// connections are redialed with exponential backoff; while the server is
+// unreachable lines go to the SyslogFallback writer, if any.
+func SyslogSink(network, addr, tag string, opts ...SyslogOption) (*SyslogWriter, error) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	if tag == "" {
+		tag = "-"
+	}
+
+	w := &SyslogWriter{
+		network:  network,
+		addr:     addr,
+		tag:      tag,
+		hostname: hostname,
+		facility: defaultSyslogFacility,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.mu.Lock()
+	err = w.reconnect()
+	w.mu.Unlock()
+	if err != nil && w.fallback == nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// SyslogFallback sets the writer used while syslog is unreachable
+func SyslogFallback(fallback io.Writer) SyslogOption {
+	return func(w *SyslogWriter) {
+		w.fallback = fallback
+	}
+}
+
+// SyslogFacility sets the syslog facility code (0-23, default 1 for user)
+func SyslogFacility(facility int) SyslogOption {
+	return func(w *SyslogWriter) {
+		if facility >= 0 && facility <= 23 {
+			w.facility = facility
+		}
+	}
+}
+
+// errSyslogUnavailable is reported while a redial waits out its backoff
+var errSyslogUnavailable = errors.New("emit: syslog unavailable")
+
+// reconnect redials the server once the backoff allows. It is called with
+// w.mu held and releases it while dialing, so concurrent writes go to the
+// fallback instead of waiting on the dial.
+func (w *SyslogWriter) reconnect() error {
+	if w.dialing || time.Now().Before(w.nextAttempt) {
+		return errSyslogUnavailable
+	}
+	w.dialing = true
+	w.mu.Unlock()
+	conn, network, addr, err := w.dial()
+	w.mu.Lock()
+	w.dialing = false
+
+	if err != nil {
+		w.scheduleRetry()
+		return err
+	}
+	if w.closed {
+		_ = conn.Close()
+		return os.ErrClosed
+	}
+	w.conn = conn
+	w.network = network
+	w.addr = addr
+	return nil
+}
+
+// scheduleRetry doubles the backoff before the next dial
+func (w *SyslogWriter) scheduleRetry() {
+	if w.backoff == 0 {
+		w.backoff = syslogMinBackoff
+	} else {
+		w.backoff = min(w.backoff*2, syslogMaxBackoff)
+	}
+	w.nextAttempt = time.Now().Add(w.backoff)
+}
+
+// dial opens the configured connection, probing the usual local sockets
+// when no network is given, and returns the network and address it
+// connected to
+func (w *SyslogWriter) dial() (net.Conn, string, string, error) {
+	if w.network != "" {
+		conn, err := net.DialTimeout(w.network, w.addr, 5*time.Second)
+		return conn, w.network, w.addr, err
+	}
+	var errs []error
+	for _, path := range localSyslogPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, path)
+			if err == nil {
+				return conn, network, path, nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return nil, "", "", errors.Join(errs...)
+}
+
+// syslogSeverity maps a log level to an RFC 5424 severity. Custom levels
+// take the severity of the range they fall in, with notice between INFO and
+// WARN and critical from PANIC up.
+func syslogSeverity(level LogLevel) int {
+	switch {
+	case level >= PANIC:
+		return syslogSeverityCrit
+	case level >= ERROR:
+		return syslogSeverityErr
+	case level >= WARN:
+		return syslogSeverityWarning
+	case level > INFO:
+		return syslogSeverityNotice
+	case level == INFO:
+		return syslogSeverityInfo
+	default:
+		return syslogSeverityDebug
+	}
+}
+
+// Write sends p at info severity
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(INFO, p)
+}
+
+// WriteLevel sends p with the severity for level
+func (w *SyslogWriter) WriteLevel(level LogLevel, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.conn != nil {
+		if _, err := w.conn.Write(w.format(level, p)); err == nil {
+			w.backoff = 0
+			return len(p), nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	// Redial once the backoff allows and retry on the fresh connection
+	if err := w.reconnect(); err != nil {
+		if w.closed {
+			return 0, os.ErrClosed
+		}
+		return w.writeFallback(p, err)
+	}
+	if _, err := w.conn.Write(w.format(level, p)); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		w.scheduleRetry()
+		return w.writeFallback(p, err)
+	}
+	w.backoff = 0
+	return len(p), nil
+}
+
+// format builds "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG"
+func (w *SyslogWriter) format(level LogLevel, p []byte) []byte {
+	msg := bytes.TrimRight(p, "\n")
+
+	buf := make([]byte, 0, len(msg)+96)
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(w.facility*8+syslogSeverity(level)), 10)
+	buf = append(buf, ">1 "...)
+	buf = time.Now().AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+	buf = append(buf, ' ')
+	buf = append(buf, w.hostname...)
+	buf = append(buf, ' ')
+	buf = append(buf, w.tag...)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(os.Getpid()), 10)
+	buf = append(buf, " - - "...)
+	buf = append(buf, msg...)
+
+	// Stream transports need a frame delimiter; datagrams are self-delimiting
+	if w.network == "tcp" || w.network == "tcp4" || w.network == "tcp6" || w.network == "unix" {
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// writeFallback writes p to the fallback writer, or reports err without one
+func (w *SyslogWriter) writeFallback(p []byte, err error) (int, error) {
+	if w.fallback == nil {
+		return 0, err
+	}
+	return w.fallback.Write(p)
+}
+
+// Close closes the syslog connection; later writes fail with os.ErrClosed
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,110 @@
+package emit
+
+import (
+	"context"
+	"errors"
+)
+
+// Tee returns a logger that forwards every entry to each of the given
+// loggers, so one call site can feed two pipelines during a migration:
+//
+//	jsonLogger, _ := emit.New(emit.WithOutput(pipeline))
+//	consoleLogger, _ := emit.New(emit.WithFormat(emit.FormatConsole))
+//	logger := emit.Tee(jsonLogger, consoleLogger)
+//
+// Each logger encodes the entry with its own format, masking, level and the
+// rest of its configuration, so an entry below one logger's level can still
+// reach another. WithFields and Named apply to every logger; SetLevel and
+// Reconfigure change every logger, and Sync and Close act on all of them and
+// join their errors. Like WithFields children, the tee holds copies that
+// share each logger's state: Reconfigure on an original reaches the tee,
+// closing the tee closes the originals, and SetLevel on an original after Tee
+// reaches the tee only once the original has been reconfigured. Nil loggers
+// are ignored.
+func Tee(loggers ...*Logger) *Logger {
+	children := make([]*Logger, 0, len(loggers))
+	for _, logger := range loggers {
+		if logger == nil {
+			continue
+		}
+		if logger.tee != nil {
+			// Nested tees are flattened; their children already account for the frame
+			children = append(children, logger.tee...)
+			continue
+		}
+		child := logger.derive()
+		child.extraCallerSkip += teeCallerSkip
+		child.teeChild = true
+		children = append(children, child)
+	}
+	return &Logger{tee: children, state: &loggerState{}}
+}
+
+// teeCallerSkip is the number of frames forwarding adds between the call
+// site and a child's encoder: the tee's logging method and its forwarder
+const teeCallerSkip = 2
+
+// teeEnabled reports whether any tee child accepts level
+func (l *Logger) teeEnabled(level LogLevel) bool {
+	if l.isClosed() {
+		return false
+	}
+	for _, child := range l.tee {
+		if child.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// teeLogContext forwards a context-aware call to each tee child
+func (l *Logger) teeLogContext(ctx context.Context, level LogLevel, message string, args ...any) {
+	for _, child := range l.tee {
+		child.logContext(ctx, level, message, args...)
+	}
+}
+
+// teeLog forwards a field map to each tee child
+func (l *Logger) teeLog(level LogLevel, message string, fields map[string]any) {
+	for _, child := range l.tee {
+		child.log(level, message, fields)
+	}
+}
+
+// teeLogStructured forwards structured fields to each tee child
+func (l *Logger) teeLogStructured(level LogLevel, message string, fields ...ZField) {
+	for _, child := range l.tee {
+		child.logStructuredFields(level, message, fields...)
+	}
+}
+
+// teeMap returns a tee of the children transformed by fn
+func (l *Logger) teeMap(fn func(*Logger) *Logger) *Logger {
+	tee := *l
+	tee.tee = make([]*Logger, len(l.tee))
+	for i, child := range l.tee {
+		tee.tee[i] = fn(child)
+	}
+	return &tee
+}
+
+// teeLevel returns the lowest level of the tee children
+func (l *Logger) teeLevel() LogLevel {
+	if len(l.tee) == 0 {
+		return l.level
+	}
+	level := l.tee[0].GetLevel()
+	for _, child := range l.tee[1:] {
+		level = min(level, child.GetLevel())
+	}
+	return level
+}
+
+// teeEach calls fn on each tee child and joins the errors
+func (l *Logger) teeEach(fn func(*Logger) error) error {
+	errs := make([]error, 0, len(l.tee))
+	for _, child := range l.tee {
+		errs = append(errs, fn(child))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package emit
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// defaultTimeKey is the entry key holding the timestamp
+const defaultTimeKey = "timestamp"
+
+// TimeFormatEpochMillis writes timestamps as numeric Unix milliseconds
+const TimeFormatEpochMillis = "epoch_millis"
+
+// WithTimeKey sets the key the timestamp is written under, e.g. "@timestamp"
+func WithTimeKey(key string) Option {
+	return func(l *Logger) error {
+		if key == "" {
+			return errors.New("emit: time key must not be empty")
+		}
+		l.timeKey = key
+		return nil
+	}
+}
+
+// WithTimeFormat sets a time.Format layout for timestamps (time.RFC3339Nano,
+// for example) or TimeFormatEpochMillis for numeric milliseconds. Custom
+// layouts use local time unless WithUTC is also given.
+func WithTimeFormat(layout string) Option {
+	return func(l *Logger) error {
+		if layout == "" {
+			return errors.New("emit: time format must not be empty")
+		}
+		l.timeFormat = layout
+		return nil
+	}
+}
+
+// WithUTC formats custom timestamp layouts in UTC. The default format is
+// always UTC.
+func WithUTC() Option {
+	return func(l *Logger) error {
+		l.timeUTC = true
+		return nil
+	}
+}
+
+// WithClock sets the function entry timestamps are read from, time.Now by
+// default. Tests can pass a fixed time to make output reproducible:
+//
+//	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+//	logger, _ := emit.New(emit.WithClock(func() time.Time { return fixed }))
+//
+// Sampling and deduplication windows still follow the wall clock.
+func WithClock(clock func() time.Time) Option {
+	return func(l *Logger) error {
+		if clock == nil {
+			return errors.New("emit: clock must not be nil")
+		}
+		l.clock = clock
+		return nil
+	}
+}
+
+// now returns the current time from the logger's clock, or the time of the
+// record being logged
+func (l *Logger) now() time.Time {
+	if l.recordTime != nil {
+		return *l.recordTime
+	}
+	if l.clock != nil {
+		return l.clock()
+	}
+	return time.Now()
+}
+
+// customTimestamp reports whether entries need per-entry timestamp handling
+// instead of the cached default
+func (l *Logger) customTimestamp() bool {
+	return l.timeKey != "" || l.timeFormat != "" || l.clock != nil || l.recordTime != nil
+}
+
+// omitsTimestamp reports whether entries are written without a timestamp,
+// as for records whose time is zero
+func (l *Logger) omitsTimestamp() bool {
+	return l.recordTime != nil && l.recordTime.IsZero()
+}
+
+// withRecordTime returns a copy of l that stamps entries with t, or writes
+// them without a timestamp when t is zero, for records such as slog's that
+// carry their own time
+func (l *Logger) withRecordTime(t time.Time) *Logger {
+	if l.tee != nil {
+		return l.teeMap(func(child *Logger) *Logger { return child.withRecordTime(t) })
+	}
+	child := *l
+	child.recordTime = &t
+	child.resolveCache = nil
+	return &child
+}
+
+// timestampKey returns the key the timestamp is written under
+func (l *Logger) timestampKey() string {
+	if l.timeKey != "" {
+		return l.timeKey
+	}
+	return defaultTimeKey
+}
+
+// formatTimestamp returns the entry timestamp and whether it is numeric
+func (l *Logger) formatTimestamp() (string, bool) {
+	switch l.timeFormat {
+	case "":
+		if l.clock != nil || l.recordTime != nil {
+			return formatFastTimestamp(l.now()), false
+		}
+		return GetUltraFastTimestamp(), false
+	case TimeFormatEpochMillis:
+		return strconv.FormatInt(l.now().UnixMilli(), 10), true
+	}
+	now := l.now()
+	if l.timeUTC {
+		now = now.UTC()
+	}
+	return now.Format(l.timeFormat), false
+}
+
+// writeJSONTimestamp writes the "key":value timestamp member
+func (l *Logger) writeJSONTimestamp(buf *bytes.Buffer) {
+	writeJSONString(buf, l.timestampKey(), l.escapeHTML)
+	buf.WriteByte(':')
+	ts, numeric := l.formatTimestamp()
+	if numeric {
+		buf.WriteString(ts)
+		return
+	}
+	writeJSONString(buf, ts, l.escapeHTML)
+}
@@ -103,7 +103,12 @@ func GetUltraFastTimestamp() string {
 // generateFastTimestamp creates a timestamp string with millisecond precision
 // This is only called once per second to update the cache
 func generateFastTimestamp() string {
-	now := time.Now().UTC()
+	return formatFastTimestamp(time.Now())
+}
+
+// formatFastTimestamp formats t in the default timestamp format
+func formatFastTimestamp(t time.Time) string {
+	now := t.UTC()
 
 	// Pre-calculate the most common case: millisecond precision
 	// Format: 2006-01-02T15:04:05.000Z
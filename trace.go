@@ -0,0 +1,46 @@
+package emit
+
+import "context"
+
+// TraceExtractor returns the trace and span IDs of the active span in ctx.
+// It returns empty strings when no valid span is present.
+//
+// Keeping the extractor as a plain function lets emit support OpenTelemetry
+// without depending on it. A typical OpenTelemetry adapter looks like:
+//
+//	func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return "", ""
+//		}
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	}
+type TraceExtractor func(ctx context.Context) (traceID, spanID string)
+
+// WithOTelTrace adds trace_id and span_id fields to context-aware log calls
+// whenever the extractor finds an active span in the context
+func WithOTelTrace(extractor TraceExtractor) Option {
+	return func(l *Logger) error {
+		l.traceExtractor = extractor
+		return nil
+	}
+}
+
+// traceFields returns the trace fields for ctx, or nil when tracing is not
+// configured or no valid span is present
+func (l *Logger) traceFields(ctx context.Context) map[string]any {
+	if l.traceExtractor == nil || ctx == nil {
+		return nil
+	}
+
+	traceID, spanID := l.traceExtractor(ctx)
+	if traceID == "" {
+		return nil
+	}
+
+	fields := map[string]any{traceIDKey: traceID}
+	if spanID != "" {
+		fields["span_id"] = spanID
+	}
+	return fields
+}
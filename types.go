@@ -1,15 +1,35 @@
 package emit
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // LogLevel represents the logging level
-type LogLevel int
+type LogLevel int32
 
+// The predefined levels are four apart, leaving room for levels added with
+// RegisterLevel
 const (
-	DEBUG LogLevel = iota
+	DEBUG LogLevel = iota * 4
 	INFO
 	WARN
 	ERROR
+	PANIC
+	FATAL
+)
+
+// Level is an alias of LogLevel
+type Level = LogLevel
+
+// Level constants
+const (
+	LevelDebug = DEBUG
+	LevelInfo  = INFO
+	LevelWarn  = WARN
+	LevelError = ERROR
+	LevelPanic = PANIC
+	LevelFatal = FATAL
 )
 
 // OutputFormat represents the output format type
@@ -18,6 +38,20 @@ type OutputFormat int
 const (
 	JSON_FORMAT OutputFormat = iota
 	PLAIN_FORMAT
+	LOGFMT_FORMAT
+	CONSOLE_FORMAT
+	ECS_FORMAT
+	GCP_FORMAT
+)
+
+// Format constants for use with WithFormat
+const (
+	FormatJSON    = JSON_FORMAT
+	FormatPlain   = PLAIN_FORMAT
+	FormatLogfmt  = LOGFMT_FORMAT
+	FormatConsole = CONSOLE_FORMAT
+	FormatECS     = ECS_FORMAT
+	FormatGCP     = GCP_FORMAT
 )
 
 // SensitiveDataMode represents how to handle sensitive data
@@ -26,6 +60,8 @@ type SensitiveDataMode int
 const (
 	MASK_SENSITIVE SensitiveDataMode = iota // Default: mask sensitive data
 	SHOW_SENSITIVE                          // Show sensitive data (not recommended for production)
+	HASH_SENSITIVE                          // Replace sensitive data with a salted SHA-256 prefix
+	DROP_SENSITIVE                          // Omit sensitive fields from the output entirely
 )
 
 // PIIDataMode represents how to handle PII data
@@ -34,6 +70,7 @@ type PIIDataMode int
 const (
 	MASK_PII PIIDataMode = iota // Default: mask PII data
 	SHOW_PII                    // Show PII data (not recommended for production)
+	DROP_PII                    // Omit PII fields from the output entirely
 )
 
 // LogEntry represents a structured log entry for Kubernetes
@@ -63,4 +100,81 @@ type Logger struct {
 	piiFields       []string
 	maskString      string
 	piiMaskString   string
+	maskFuncs       *maskFuncRegistry
+	fieldRules      *loggerFieldRules
+	maskCategories  []*categoryRule
+	partialMask     PartialMasking
+	piiPartialMask  PartialMasking
+	hashSalt        *hashSaltValue
+
+	valuePatterns         *valuePatternSet
+	valuePatternDetection bool
+	entropy               *entropyDetector
+
+	traceExtractor TraceExtractor
+	baseFields     map[string]any
+
+	stackTraceEnabled bool
+	stackTraceLevel   LogLevel
+	stackTraceDepth   int
+
+	streamingEncoder bool
+	escapeHTML       bool
+	prettyJSON       bool
+	sortedKeys       bool
+	colorMode        colorMode
+	sampler          *sampler
+	traceSampler     *traceSampler
+	dedup            *deduplicator
+	state            *loggerState
+	async            *asyncWriter
+	writeGuard       *writeGuard
+	encoder          Encoder
+	sequence         bool
+	scanLimit        *scanLimit
+	scanBudget       *scanBudget
+	groups           []string
+	callerPC         uintptr
+	resolveCache     *resolveCache
+	name             string
+	timeKey          string
+	timeFormat       string
+	timeUTC          bool
+	gcpProjectID     string
+	caller           *callerConfig
+	extraCallerSkip  int
+	defaultFields    map[string]any
+	unmaskedDefaults map[string]any
+	runtimeFields    map[string]any
+	source           string
+	translator       MessageTranslator
+	levelNames       map[LogLevel]string
+	structMasking    bool
+	maskObserver     MaskObserver
+	stats            *loggerStats
+	levelRoutes      []levelRoute
+	hooks            []Hook
+	errorHandler     func(error)
+	floatFormat      floatFormat
+	durationFormat   DurationFormat
+	strictMasking    bool
+	premasked        bool
+	batch            *batchBuffer
+	clock            func() time.Time
+	recordTime       *time.Time
+	keyTransformer   func(string) string
+	maxFields        int
+	maxValueLen      int
+	maxDepth         int
+	byteSlices       *byteSliceHandling
+	exitFunc         func(int)
+	fatalHooks       []func()
+	panicValue       func(message string) any
+	flattenSeparator string
+	flattenSlices    bool
+	jsonFields       map[string]bool
+	resolved         bool
+	schema           *schema
+	tee              []*Logger
+	teeChild         bool
 }
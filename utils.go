@@ -1,6 +1,10 @@
 package emit
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
 
 // String returns the string representation of the log level
 func (l LogLevel) String() string {
@@ -13,7 +17,14 @@ func (l LogLevel) String() string {
 		return "warn"
 	case ERROR:
 		return "error"
+	case PANIC:
+		return "panic"
+	case FATAL:
+		return "fatal"
 	default:
+		if custom, ok := lookupCustomLevel(l); ok {
+			return custom.name
+		}
 		return "info"
 	}
 }
@@ -30,23 +41,120 @@ func (l LogLevel) StringFast() string {
 		return "warn"
 	case ERROR:
 		return "error"
+	case PANIC:
+		return "panic"
+	case FATAL:
+		return "fatal"
 	default:
+		if custom, ok := lookupCustomLevel(l); ok {
+			return custom.name
+		}
 		return "info"
 	}
 }
 
-// ParseLogLevel parses a string into a LogLevel
+// ParseLogLevel parses a string into a LogLevel, returning INFO for unknown names
 func ParseLogLevel(level string) LogLevel {
-	switch strings.ToLower(level) {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return INFO
+	}
+	return parsed
+}
+
+// ParseLevel parses a level name, including names added with RegisterLevel,
+// returning an error for unknown names
+func ParseLevel(level string) (LogLevel, error) {
+	name := strings.ToLower(level)
+	if parsed, err := parseBuiltinLevel(name); err == nil {
+		return parsed, nil
+	}
+	if parsed, ok := parseCustomLevel(strings.TrimSpace(name)); ok {
+		return parsed, nil
+	}
+	return INFO, fmt.Errorf("emit: unknown log level %q", level)
+}
+
+// parseBuiltinLevel parses a lower-cased predefined level name
+func parseBuiltinLevel(name string) (LogLevel, error) {
+	switch name {
 	case "debug":
-		return DEBUG
+		return DEBUG, nil
 	case "info", "information":
-		return INFO
+		return INFO, nil
 	case "warn", "warning":
-		return WARN
+		return WARN, nil
 	case "error":
-		return ERROR
+		return ERROR, nil
+	case "panic":
+		return PANIC, nil
+	case "fatal":
+		return FATAL, nil
 	default:
-		return INFO
+		return INFO, fmt.Errorf("emit: unknown log level %q", name)
+	}
+}
+
+// UnmarshalText parses a level name, allowing levels to be read from config files
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalText returns the level name
+func (l LogLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// SetLevel atomically changes the minimum level logged by l.
+// It is safe to call while other goroutines are logging.
+func (l *Logger) SetLevel(level LogLevel) {
+	if l.tee != nil {
+		for _, child := range l.tee {
+			child.SetLevel(level)
+		}
+		return
+	}
+	if l.state != nil && !l.resolved {
+		// Reconfigure copies the logger under the same lock
+		l.state.levelMu.Lock()
+		defer l.state.levelMu.Unlock()
+	}
+	if l.reconfigured() && !l.resolved {
+		l.state.level.Store(int32(level))
+		return
+	}
+	atomic.StoreInt32((*int32)(&l.level), int32(level))
+}
+
+// GetLevel returns the minimum level logged by l
+func (l *Logger) GetLevel() LogLevel {
+	if l.tee != nil {
+		return l.teeLevel()
+	}
+	if l.reconfigured() {
+		return LogLevel(l.state.level.Load())
+	}
+	return LogLevel(atomic.LoadInt32((*int32)(&l.level)))
+}
+
+// Enabled reports whether entries at level would be logged; named loggers
+// use the level registered for their prefix, if any, and nothing is logged
+// after Close. Suppressed entries return before any masking or
+// allocation happens.
+func (l *Logger) Enabled(level LogLevel) bool {
+	if l.tee != nil {
+		return l.teeEnabled(level)
+	}
+	minLevel := l.GetLevel()
+	if l.name != "" {
+		if prefixLevel, ok := levelForName(l.name); ok {
+			minLevel = prefixLevel
+		}
 	}
+	return level >= minLevel && !l.isClosed()
 }
@@ -0,0 +1,257 @@
+package emit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// floatFormat controls how JSON encoders write float values. The zero value
+// matches encoding/json, with NaN and infinities written as null.
+type floatFormat struct {
+	verb             byte // strconv.FormatFloat verb, 0 for the encoding/json style
+	precision        int
+	nonFiniteStrings bool
+}
+
+// WithFloatFormat sets how float field values are written in JSON output.
+// format is a strconv.FormatFloat verb ('f', 'g' or 'e') and precision the
+// number of digits after the decimal point ('f', 'e') or significant digits
+// ('g'), or -1 for the fewest digits that round-trip.
+//
+// By default floats are written like encoding/json: 'f' with -1, switching
+// to 'e' below 1e-6 and from 1e21. Use 'f' with -1 to never write exponents,
+// or a fixed precision such as 'f' with 2 for amounts.
+func WithFloatFormat(format byte, precision int) Option {
+	return func(l *Logger) error {
+		if format != 'f' && format != 'g' && format != 'e' {
+			return errors.New("emit: float format must be 'f', 'g' or 'e'")
+		}
+		if precision < -1 {
+			return errors.New("emit: float precision must be -1 or more")
+		}
+		l.floatFormat.verb = format
+		l.floatFormat.precision = precision
+		return nil
+	}
+}
+
+// WithNonFiniteFloatsAsStrings writes NaN and infinities, which JSON cannot
+// represent, as the strings "NaN", "+Inf" and "-Inf" instead of null
+func WithNonFiniteFloatsAsStrings() Option {
+	return func(l *Logger) error {
+		l.floatFormat.nonFiniteStrings = true
+		return nil
+	}
+}
+
+// append appends v as a JSON value
+func (f floatFormat) append(dst []byte, v float64, bits int) []byte {
+	switch {
+	case math.IsNaN(v):
+		return f.appendNonFinite(dst, `"NaN"`)
+	case math.IsInf(v, 1):
+		return f.appendNonFinite(dst, `"+Inf"`)
+	case math.IsInf(v, -1):
+		return f.appendNonFinite(dst, `"-Inf"`)
+	}
+
+	if f.verb != 0 {
+		return strconv.AppendFloat(dst, v, f.verb, f.precision, bits)
+	}
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, v, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9, as encoding/json does
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
+func (f floatFormat) appendNonFinite(dst []byte, sentinel string) []byte {
+	if f.nonFiniteStrings {
+		return append(dst, sentinel...)
+	}
+	return append(dst, "null"...)
+}
+
+// DurationFormat selects how time.Duration field values are written
+type DurationFormat int
+
+const (
+	DurationNanos  DurationFormat = iota + 1 // Integer nanoseconds, as encoding/json writes them
+	DurationString                           // Duration.String, such as "1.5s"
+)
+
+// WithDurationFormat sets how time.Duration field values are written in JSON,
+// logfmt and console output. By default JSON holds nanoseconds and logfmt
+// and console output the human-readable string.
+func WithDurationFormat(format DurationFormat) Option {
+	return func(l *Logger) error {
+		if format != DurationNanos && format != DurationString {
+			return errors.New("emit: unknown duration format")
+		}
+		l.durationFormat = format
+		return nil
+	}
+}
+
+// formatTime returns a time.Time field value as RFC 3339 with nanoseconds,
+// in UTC when WithUTC is set
+func (l *Logger) formatTime(t time.Time) string {
+	if l.timeUTC {
+		t = t.UTC()
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// customValueFormat reports whether values must be written by the streaming
+// encoder rather than encoding/json
+func (l *Logger) customValueFormat() bool {
+	return l.floatFormat != floatFormat{} || l.durationFormat != 0 || l.timeUTC
+}
+
+// jsonSafeFields returns a copy of fields that encoding/json can encode,
+// replacing NaN and infinities and values that fail to marshal, so one bad
+// field does not lose the entry. It is used after encoding an entry failed.
+func (l *Logger) jsonSafeFields(fields map[string]any) map[string]any {
+	safe := maps.Clone(fields)
+	for key, value := range safe {
+		safe[key] = l.jsonSafeValue(value, 0)
+	}
+	return safe
+}
+
+// jsonSafeMaxDepth bounds the descent into nested values, which also stops
+// at circular references
+const jsonSafeMaxDepth = 32
+
+// jsonSafeValue replaces non-finite floats and unencodable values in value,
+// descending into field maps and slices
+func (l *Logger) jsonSafeValue(value any, depth int) any {
+	if depth > jsonSafeMaxDepth {
+		return value
+	}
+	switch v := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return value
+	case float64:
+		return l.jsonSafeFloat(v)
+	case float32:
+		return l.jsonSafeFloat(float64(v))
+	case map[string]any:
+		safe := make(map[string]any, len(v))
+		for key, element := range v {
+			safe[key] = l.jsonSafeValue(element, depth+1)
+		}
+		return safe
+	case Fields:
+		safe := make(map[string]any, len(v))
+		for key, element := range v {
+			safe[key] = l.jsonSafeValue(element, depth+1)
+		}
+		return safe
+	case []any:
+		safe := make([]any, len(v))
+		for i, element := range v {
+			safe[i] = l.jsonSafeValue(element, depth+1)
+		}
+		return safe
+	case []map[string]any:
+		safe := make([]any, len(v))
+		for i, element := range v {
+			safe[i] = l.jsonSafeValue(element, depth+1)
+		}
+		return safe
+	}
+	if _, err := json.Marshal(value); err != nil {
+		return marshalErrorPlaceholder(value, err)
+	}
+	return value
+}
+
+// marshalErrorPlaceholder replaces a value that cannot be encoded, such as a
+// channel or a type whose MarshalJSON fails
+func marshalErrorPlaceholder(value any, err error) string {
+	return fmt.Sprintf("!ERROR: %T: %v", value, err)
+}
+
+// jsonSafeFloat returns nil or the string sentinel for non-finite floats
+func (l *Logger) jsonSafeFloat(v float64) any {
+	if !math.IsNaN(v) && !math.IsInf(v, 0) {
+		return v
+	}
+	if !l.floatFormat.nonFiniteStrings {
+		return nil
+	}
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case v > 0:
+		return "+Inf"
+	default:
+		return "-Inf"
+	}
+}
+
+// textValue returns the message of an error or the text of a fmt.Stringer,
+// so values such as custom enums are written as their text rather than their
+// underlying shape. Types with their own JSON encoding keep it, as do
+// json.Number values, and durations and stack traces are encoded separately.
+func textValue(value any) (string, bool) {
+	switch value.(type) {
+	case json.Marshaler, json.Number, time.Duration, StackTrace:
+		return "", false
+	}
+	switch v := value.(type) {
+	case error:
+		if isNilPointer(v) {
+			return "", false
+		}
+		return v.Error(), true
+	case fmt.Stringer:
+		if isNilPointer(v) {
+			return "", false
+		}
+		return v.String(), true
+	}
+	return "", false
+}
+
+// isNilPointer reports whether value is a typed nil pointer, whose methods
+// may panic
+func isNilPointer(value any) bool {
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// textFields replaces error and fmt.Stringer values at the top level of an
+// unmasked field map, copying the map only when one is found
+func textFields(fields map[string]any) map[string]any {
+	var result map[string]any
+	for key, value := range fields {
+		text, ok := textValue(value)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = maps.Clone(fields)
+		}
+		result[key] = text
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package emit
+
+import (
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Value-content detection masks string values that match known secret formats
+// regardless of the field name they are logged under.
+//
+// Performance: every string value that passes the field-name checks is matched
+// against every registered pattern, so the cost grows with both the number of
+// fields and the number of patterns. Detection is therefore disabled by default
+// and must be enabled explicitly.
+
+// valuePatternSet holds the value patterns registered on a logger. Adding
+// copies the list, so readers load it without locking.
+type valuePatternSet struct {
+	mu       sync.Mutex
+	patterns atomic.Pointer[[]*regexp.Regexp]
+}
+
+// add appends a pattern to a copy of the list
+func (s *valuePatternSet) add(pattern *regexp.Regexp) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := append(slices.Clone(s.load()), pattern)
+	s.patterns.Store(&next)
+}
+
+// load returns the registered patterns
+func (s *valuePatternSet) load() []*regexp.Regexp {
+	if s == nil {
+		return nil
+	}
+	if patterns := s.patterns.Load(); patterns != nil {
+		return *patterns
+	}
+	return nil
+}
+
+// AddValuePattern registers a precompiled pattern used to detect sensitive values
+func (l *Logger) AddValuePattern(pattern *regexp.Regexp) {
+	if pattern == nil {
+		return
+	}
+	registryInitMu.Lock()
+	if l.valuePatterns == nil {
+		l.valuePatterns = &valuePatternSet{}
+	}
+	registryInitMu.Unlock()
+
+	l.valuePatterns.add(pattern)
+}
+
+// SetValuePatternDetection enables or disables value-content detection
+func (l *Logger) SetValuePatternDetection(enabled bool) {
+	l.valuePatternDetection = enabled
+}
+
+// matchesValuePattern reports whether a value looks like a secret based on its content
+func (l *Logger) matchesValuePattern(value any) bool {
+	if !l.detectsValues() || l.sensitiveMode == SHOW_SENSITIVE {
+		return false
+	}
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return false
+	}
+
+	for _, pattern := range l.valuePatterns.load() {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectsValues reports whether value-content detection is on with patterns
+// registered
+func (l *Logger) detectsValues() bool {
+	return l.valuePatternDetection && len(l.valuePatterns.load()) > 0
+}
+
+// AddValuePattern registers a sensitive value pattern on the default logger
+func AddValuePattern(pattern *regexp.Regexp) {
+	if logger := Default(); logger != nil {
+		logger.AddValuePattern(pattern)
+	}
+}
+
+// EnableValuePatternDetection turns on value-content detection for the default logger
+func EnableValuePatternDetection() {
+	if logger := Default(); logger != nil {
+		logger.SetValuePatternDetection(true)
+	}
+}
+
+// DisableValuePatternDetection turns off value-content detection for the default logger
+func DisableValuePatternDetection() {
+	if logger := Default(); logger != nil {
+		logger.SetValuePatternDetection(false)
+	}
+}
@@ -0,0 +1,92 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// States of a write run by writeGuard
+const (
+	writeRunning int32 = iota
+	writeFinished
+	writeAbandoned
+)
+
+// writeGuard bounds how long a write may block
+type writeGuard struct {
+	timeout time.Duration
+	blocked atomic.Int32 // timed-out writes that have not returned yet
+	dropped atomic.Uint64
+}
+
+// WithWriteTimeout drops a line when writing it takes longer than d, so a
+// hung network sink cannot stall every goroutine that logs:
+//
+//	emit.WithWriteTimeout(200 * time.Millisecond)
+//
+// Each write runs in its own goroutine and the caller waits at most d. A
+// write that times out keeps running in the background, as io.Writer cannot
+// be canceled; until it returns, further lines are dropped at once instead
+// of piling up behind it. Dropped lines are counted in Stats().TimedOut and
+// reported to the error handler as ErrWriteTimeout. This is a safety valve
+// that keeps the application responsive, not a delivery guarantee: the
+// dropped lines are lost, and a line that timed out may still be written
+// late. The goroutine and timer add to the cost of every write, so use
+// WithAsync instead where blocking a queue is acceptable.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(l *Logger) error {
+		if d <= 0 {
+			return errors.New("emit: write timeout must be positive")
+		}
+		l.writeGuard = &writeGuard{timeout: d}
+		return nil
+	}
+}
+
+// write writes line to w, giving up after the timeout
+func (g *writeGuard) write(l *Logger, w io.Writer, level LogLevel, line []byte) {
+	if g.blocked.Load() > 0 {
+		g.drop(l)
+		return
+	}
+
+	// The line outlives the call if the write times out
+	line = append([]byte(nil), line...)
+	done := make(chan error, 1)
+	var state atomic.Int32 // writeRunning, writeFinished or writeAbandoned
+	go func() {
+		done <- writeLevel(w, level, line)
+		if !state.CompareAndSwap(writeRunning, writeFinished) {
+			g.blocked.Add(-1)
+		}
+	}()
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			l.reportError(fmt.Errorf("emit: write failed: %w", err))
+		}
+	case <-timer.C:
+		// Counted before abandoning so a write finishing now cannot be missed
+		g.blocked.Add(1)
+		if state.CompareAndSwap(writeRunning, writeAbandoned) {
+			g.drop(l)
+			return
+		}
+		g.blocked.Add(-1)
+		if err := <-done; err != nil {
+			l.reportError(fmt.Errorf("emit: write failed: %w", err))
+		}
+	}
+}
+
+// drop counts and reports a line that was not written in time
+func (g *writeGuard) drop(l *Logger) {
+	g.dropped.Add(1)
+	l.reportError(ErrWriteTimeout)
+}
@@ -14,53 +14,7 @@ type ZeroAllocEncoder struct {
 
 // writeString appends a JSON-escaped string to the buffer
 func (e *ZeroAllocEncoder) writeString(s string) {
-	e.buf = append(e.buf, '"')
-
-	// Fast path for strings without special characters
-	needsEscape := false
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		if c < 32 || c == '"' || c == '\\' {
-			needsEscape = true
-			break
-		}
-	}
-
-	if !needsEscape {
-		e.buf = append(e.buf, s...)
-	} else {
-		// Slow path with proper escaping
-		for _, r := range s {
-			switch r {
-			case '"':
-				e.buf = append(e.buf, '\\', '"')
-			case '\\':
-				e.buf = append(e.buf, '\\', '\\')
-			case '\n':
-				e.buf = append(e.buf, '\\', 'n')
-			case '\r':
-				e.buf = append(e.buf, '\\', 'r')
-			case '\t':
-				e.buf = append(e.buf, '\\', 't')
-			default:
-				if r < 32 {
-					e.buf = append(e.buf, '\\', 'u', '0', '0')
-					e.buf = append(e.buf, "0123456789abcdef"[r>>4])
-					e.buf = append(e.buf, "0123456789abcdef"[r&0xF])
-				} else {
-					// Use unsafe to convert rune to bytes efficiently
-					if r <= 0x7F {
-						e.buf = append(e.buf, byte(r))
-					} else {
-						// For non-ASCII, fall back to string conversion
-						e.buf = append(e.buf, string(r)...)
-					}
-				}
-			}
-		}
-	}
-
-	e.buf = append(e.buf, '"')
+	e.buf = appendJSONString(e.buf, s, false)
 }
 
 // writeStringField writes a string field to JSON
@@ -116,7 +70,7 @@ func (e *ZeroAllocEncoder) writeFloat64Field(key string, value float64) {
 	e.writeString(key)
 	e.buf = append(e.buf, ':')
 
-	num := strconv.AppendFloat(e.scratch[:0], value, 'f', -1, 64)
+	num := floatFormat{}.append(e.scratch[:0], value, 64)
 	e.buf = append(e.buf, num...)
 
 	e.fieldCount++
@@ -131,6 +131,43 @@ func (f DurationZField) WriteToEncoder(enc *ZeroAllocEncoder) {
 func (f DurationZField) IsSensitive() bool { return false }
 func (f DurationZField) IsPII() bool       { return false }
 
+// zfieldKeyValue extracts the key and value of a known ZField for map-based logging
+func zfieldKeyValue(field ZField) (string, any, bool) {
+	switch f := field.(type) {
+	case StringZField:
+		return f.Key, f.Value, true
+	case IntZField:
+		return f.Key, f.Value, true
+	case Int64ZField:
+		return f.Key, f.Value, true
+	case Float64ZField:
+		return f.Key, f.Value, true
+	case BoolZField:
+		return f.Key, f.Value, true
+	case TimeZField:
+		return f.Key, f.Value, true
+	case DurationZField:
+		return f.Key, f.Value, true
+	case LazyZField:
+		return f.Key, lazyValue(f.Fn), true
+	case LevelZField:
+		return f.Key, f, true
+	case DiffZField:
+		return f.Key, f.Changes, true
+	default:
+		return "", nil, false
+	}
+}
+
+// zfieldArgs converts ZFields to variadic logging arguments
+func zfieldArgs(fields []ZField) []any {
+	args := make([]any, len(fields))
+	for i, field := range fields {
+		args[i] = field
+	}
+	return args
+}
+
 // Zero-allocation field constructors
 
 // ZString creates a zero-allocation string field
@@ -167,3 +204,45 @@ func ZTime(key string, value time.Time) TimeZField {
 func ZDuration(key string, value time.Duration) DurationZField {
 	return DurationZField{Key: key, Value: value}
 }
+
+// Typed field constructors for the Logger methods:
+//
+//	logger.Info("order placed", emit.String("order_id", id), emit.Int("items", n))
+//
+// They return the same field types as the Z constructors and are masked by key
+// like any other field.
+
+// String creates a string field
+func String(key, value string) StringZField {
+	return StringZField{Key: key, Value: value}
+}
+
+// Int creates an int field
+func Int(key string, value int) IntZField {
+	return IntZField{Key: key, Value: value}
+}
+
+// Int64 creates an int64 field
+func Int64(key string, value int64) Int64ZField {
+	return Int64ZField{Key: key, Value: value}
+}
+
+// Float64 creates a float64 field
+func Float64(key string, value float64) Float64ZField {
+	return Float64ZField{Key: key, Value: value}
+}
+
+// Bool creates a bool field
+func Bool(key string, value bool) BoolZField {
+	return BoolZField{Key: key, Value: value}
+}
+
+// Time creates a time field, encoded as RFC 3339 with nanoseconds
+func Time(key string, value time.Time) TimeZField {
+	return TimeZField{Key: key, Value: value}
+}
+
+// Duration creates a duration field, encoded as nanoseconds
+func Duration(key string, value time.Duration) DurationZField {
+	return DurationZField{Key: key, Value: value}
+}